@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
 	workbench "github.com/rflorenc/ansible-automation-workbench"
 	"github.com/rflorenc/ansible-automation-workbench/internal/api"
 	"github.com/rflorenc/ansible-automation-workbench/internal/config"
+	"github.com/rflorenc/ansible-automation-workbench/internal/migration"
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+	"github.com/rflorenc/ansible-automation-workbench/internal/webhook"
 )
 
 var (
@@ -31,96 +38,120 @@ func main() {
 	}
 
 	cfg := config.Parse()
+	if cfg.DebugHTTP {
+		platform.SetHTTPDebug(func(line string) { log.Print(line) }, cfg.SecretKeys)
+		fmt.Println("Logging HTTP traffic to platforms")
+	}
+
+	jobs := models.NewJobStore()
+	if cfg.DataDir != "" {
+		var err error
+		jobs, err = models.NewPersistentJobStore(filepath.Join(cfg.DataDir, "jobs"))
+		if err != nil {
+			log.Fatalf("Loading job history: %v", err)
+		}
+		fmt.Printf("Persisting jobs to %s\n", filepath.Join(cfg.DataDir, "jobs"))
+	}
+	if len(cfg.SecretKeys) > 0 {
+		jobs.SetSecretKeys(cfg.SecretKeys)
+	}
+	if cfg.MaxLogLines < 0 {
+		jobs.SetMaxLogLines(0)
+	} else if cfg.MaxLogLines > 0 {
+		jobs.SetMaxLogLines(cfg.MaxLogLines)
+	}
+	jobs.SetStructuredLogs(cfg.StructuredLogs)
+	initial := models.Settings{
+		ExportConcurrency: cfg.ExportConcurrency,
+		ImportConcurrency: cfg.ImportConcurrency,
+		MaxRetries:        cfg.MaxRetries,
+		HealthInterval:    cfg.HealthInterval,
+		SecretKeys:        cfg.SecretKeys,
+	}
+	if err := initial.Validate(); err != nil {
+		log.Fatalf("Invalid settings: %v", err)
+	}
+	settings := models.NewSettingsStore(initial)
+	settings.OnChange(func(s models.Settings) { jobs.SetSecretKeys(s.SecretKeys) })
+	platform.UseSettings(settings)
+	if cfg.WebhookURL != "" {
+		jobs.OnFinish(webhook.New(cfg.WebhookURL).Notify)
+	}
+
+	rules := make([]migration.TransformRule, len(cfg.Transforms))
+	for i, t := range cfg.Transforms {
+		rules[i] = migration.TransformRule{Type: t.Type, Field: t.Field, Find: t.Find, Replace: t.Replace}
+	}
+	for _, a := range cfg.NameAffixes {
+		rules = append(rules, migration.NameAffix{Type: a.Type, Prefix: a.Prefix, Suffix: a.Suffix}.Rules()...)
+	}
+	transforms, err := migration.NewTransforms(rules)
+	if err != nil {
+		log.Fatalf("Loading transforms: %v", err)
+	}
 
 	server := &api.Server{
 		Connections: models.NewConnectionStore(),
-		Jobs:        models.NewJobStore(),
+		Jobs:        jobs,
 		Previews:    api.NewPreviewStore(),
+		Secrets:     migration.CredentialSecrets(cfg.CredentialSecrets),
+		Transforms:  transforms,
+		Settings:    settings,
+		Users: &migration.UserOptions{
+			Password:          cfg.UserDefaults.Password,
+			SystemAuditor:     cfg.UserDefaults.SystemAuditor,
+			PreserveSuperuser: cfg.UserDefaults.PreserveSuperuser,
+		},
+	}
+	server.NameTemplate = cfg.NameTemplate
+	server.ArchiveDir = cfg.ArchiveDir
+	if server.ArchiveDir == "" && cfg.DataDir != "" {
+		server.ArchiveDir = filepath.Join(cfg.DataDir, "archives")
+	}
+	if cfg.ListCacheTTL > 0 {
+		server.ListCache = platform.NewResponseCache(cfg.ListCacheTTL)
+	}
+	if cfg.Metrics {
+		metrics := platform.NewMetrics()
+		platform.SetMetrics(metrics)
+		server.Metrics = metrics
+		fmt.Println("Serving platform request metrics on /metrics")
+	}
+	if cfg.Path() != "" {
+		server.SaveSettings = func(s models.Settings) error { return cfg.Save(s) }
 	}
 
 	// Load pre-configured connections from config file
+	seenNames := make(map[string]bool)
+	var loaded []*models.Connection
 	for _, cc := range cfg.Connections {
-		conn := &models.Connection{
-			Name:     cc.Name,
-			Type:     cc.Type,
-			Role:     cc.Role,
-			Scheme:   cc.Scheme,
-			Host:     cc.Host,
-			Port:     cc.Port,
-			Username: cc.Username,
-			Password: cc.Password,
-			Insecure: cc.Insecure,
-			CACert:   cc.CACert,
+		conn := cc.ToConnection()
+		if conn.Name == "" {
+			conn.Name = config.ExpandNameTemplate(cfg.NameTemplate, conn)
 		}
-		if conn.Role == "" {
-			if conn.Type == "awx" {
-				conn.Role = "source"
-			} else {
-				conn.Role = "destination"
-			}
+		if conn.Name == "" {
+			log.Fatalf("Connection for host %q has an empty name (check name_template)", conn.Host)
 		}
-		if conn.Scheme == "" {
-			if conn.Type == "aap" {
-				conn.Scheme = "https"
-			} else {
-				conn.Scheme = "http"
-			}
+		if seenNames[conn.Name] {
+			log.Fatalf("Duplicate connection name %q in config (set name or adjust name_template)", conn.Name)
 		}
-		if conn.Port == 0 {
-			if conn.Scheme == "https" {
-				conn.Port = 443
-			} else {
-				conn.Port = 80
-			}
+		seenNames[conn.Name] = true
+		if err := platform.CheckCACert(conn); err != nil {
+			log.Fatalf("Connection %q: %v", conn.Name, err)
 		}
-		server.Connections.Create(conn)
-		fmt.Printf("Loaded connection: %s (%s://%s:%d)\n", conn.Name, conn.Scheme, conn.Host, conn.Port)
-
-		// Verify connectivity and auth early
-		p := platform.NewPlatform(conn)
-		client := platform.NewClient(conn)
-		pingStatus, pingError := "ok", ""
-		if err := p.Ping(); err != nil {
-			pingStatus = "error"
-			pingError = err.Error()
-			fmt.Printf("  PING FAILED: %s: %v\n", conn.Name, err)
-		} else {
-			fmt.Printf("  PING OK: %s: reachable\n", conn.Name)
+		if err := platform.CheckClientCert(conn); err != nil {
+			log.Fatalf("Connection %q: %v", conn.Name, err)
 		}
-
-		authStatus, authError := "unknown", ""
-		if pingStatus == "ok" {
-			if conn.Username == "" || conn.Password == "" {
-				authStatus = "error"
-				authError = "no credentials configured"
-				fmt.Printf("  AUTH FAILED: %s: %s\n", conn.Name, authError)
-			} else if err := p.CheckAuth(); err != nil {
-				authStatus = "error"
-				authError = err.Error()
-				fmt.Printf("  AUTH FAILED: %s: %v\n", conn.Name, err)
-			} else {
-				authStatus = "ok"
-				fmt.Printf("  AUTH OK: %s: authenticated successfully\n", conn.Name)
-
-				// Discovery: detect version and API prefix (only after auth succeeds)
-				var pingResp *platform.PingResponse
-				for _, pp := range platform.PingPaths(conn.Type) {
-					pingResp, err = client.PingWithVersion(pp)
-					if err == nil {
-						break
-					}
-				}
-				if err == nil && pingResp.Version != "" {
-					conn.Version = pingResp.Version
-					server.Connections.SetVersion(conn.ID, pingResp.Version, "")
-					fmt.Printf("  VERSION: %s: %s\n", conn.Name, pingResp.Version)
-				}
-				platform.DiscoverAndStore(client, conn, server.Connections)
-			}
-		}
-		server.Connections.SetHealth(conn.ID, pingStatus, pingError, authStatus, authError)
+		server.Connections.Create(conn)
+		fmt.Printf("Loaded connection: %s (%s)\n", conn.Name, conn.BaseURL())
+		loaded = append(loaded, conn)
 	}
 
+	// Keep connection health current for the dashboard
+	poller := platform.NewHealthPoller(server.Connections, cfg.HealthInterval)
+	settings.OnChange(func(s models.Settings) { poller.SetInterval(s.HealthInterval) })
+	go poller.Run(context.Background())
+
 	var webFS fs.FS
 	if cfg.Dev {
 		// In dev mode, proxy to Vite dev server
@@ -148,9 +179,70 @@ func main() {
 	}
 	fmt.Printf("Open http://localhost%s in your browser\n", cfg.Listen)
 
-	if err := http.ListenAndServe(cfg.Listen, handler); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
 		log.Fatal(err)
 	}
+	// Connections are checked while serving; /readyz reports 200 once done.
+	go func() {
+		for _, conn := range loaded {
+			checkConnection(conn, server.Connections)
+		}
+		server.MarkReady()
+	}()
+	if err := api.Serve(ctx, ln, handler, jobs, api.DefaultShutdownGrace); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Workbench stopped")
+}
+
+// checkConnection pings a connection loaded from the config file, verifies
+// its credentials and, once authenticated, discovers its version and API
+// prefix, recording the results in store.
+func checkConnection(conn *models.Connection, store *models.ConnectionStore) {
+	p := platform.NewPlatform(conn)
+	client := platform.NewClient(conn)
+	pingStatus, pingError := "ok", ""
+	if err := p.Ping(); err != nil {
+		pingStatus = "error"
+		pingError = err.Error()
+		fmt.Printf("  PING FAILED: %s: %v\n", conn.Name, err)
+	} else {
+		fmt.Printf("  PING OK: %s: reachable\n", conn.Name)
+	}
+
+	authStatus, authError := "unknown", ""
+	if pingStatus == "ok" {
+		if !conn.HasCredentials() {
+			authStatus = "error"
+			authError = "no credentials configured"
+			fmt.Printf("  AUTH FAILED: %s: %s\n", conn.Name, authError)
+		} else if err := p.CheckAuth(); err != nil {
+			authStatus = "error"
+			authError = err.Error()
+			fmt.Printf("  AUTH FAILED: %s: %v\n", conn.Name, err)
+		} else {
+			authStatus = "ok"
+			fmt.Printf("  AUTH OK: %s: authenticated successfully\n", conn.Name)
+
+			// Discovery: detect version and API prefix (only after auth succeeds)
+			var pingResp *platform.PingResponse
+			for _, pp := range platform.PingPaths(conn.Type) {
+				pingResp, err = client.PingWithVersion(pp)
+				if err == nil {
+					break
+				}
+			}
+			if err == nil && pingResp.Version != "" {
+				store.SetVersion(conn.ID, pingResp.Version, "")
+				fmt.Printf("  VERSION: %s: %s\n", conn.Name, pingResp.Version)
+			}
+			platform.DiscoverAndStore(client, conn, store)
+		}
+	}
+	store.SetHealth(conn.ID, pingStatus, pingError, authStatus, authError)
 }
 
 // devRouter creates a handler that serves API routes directly and proxies
@@ -164,7 +256,11 @@ func devRouter(server *api.Server) http.Handler {
 	proxy := httputil.NewSingleHostReverseProxy(viteURL)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Route /api/* and /ws/* to our Go server
+		// Route /api/*, /ws/*, the probes and metrics to our Go server
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" || r.URL.Path == "/metrics" {
+			apiRouter.ServeHTTP(w, r)
+			return
+		}
 		if len(r.URL.Path) >= 4 && r.URL.Path[:4] == "/api" {
 			apiRouter.ServeHTTP(w, r)
 			return
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/config"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// connectionsFile is the part of a config file that holds connections.
+type connectionsFile struct {
+	Connections []config.ConnectionConfig `yaml:"connections"`
+}
+
+// ExportConnections returns every connection as a config file "connections"
+// section, sorted by name. Passwords and tokens are left out, or masked with
+// ?masked=true to show which connections had them.
+func (s *Server) ExportConnections(w http.ResponseWriter, r *http.Request) {
+	mask := r.URL.Query().Get("masked") == "true"
+	conns := s.Connections.List()
+	sort.Slice(conns, func(a, b int) bool { return conns[a].Name < conns[b].Name })
+
+	var file connectionsFile
+	for _, c := range conns {
+		file.Connections = append(file.Connections, config.FromConnection(c, mask))
+	}
+	out, err := yaml.Marshal(file)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", `attachment; filename="connections.yaml"`)
+	w.Write(out)
+}
+
+// ImportConnections creates the connections of an uploaded config file,
+// filling in defaults as the config file loader does. Entries whose name is
+// already taken, or that are invalid, are reported and skipped. Masked
+// passwords and tokens from ?masked=true exports are treated as unset.
+func (s *Server) ImportConnections(w http.ResponseWriter, r *http.Request) {
+	var file connectionsFile
+	if err := yaml.NewDecoder(r.Body).Decode(&file); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid YAML: "+err.Error())
+		return
+	}
+
+	taken := make(map[string]bool)
+	for _, c := range s.Connections.List() {
+		taken[c.Name] = true
+	}
+
+	type result struct {
+		Name   string `json:"name"`
+		ID     string `json:"id,omitempty"`
+		Status string `json:"status"` // "created", "conflict", "invalid"
+		Error  string `json:"error,omitempty"`
+	}
+	nameTemplate := s.NameTemplate
+	if nameTemplate == "" {
+		nameTemplate = config.DefaultNameTemplate
+	}
+	results := make([]result, 0, len(file.Connections))
+	for _, cc := range file.Connections {
+		conn := cc.ToConnection()
+		if conn.Password == conn.MaskedPassword() {
+			conn.Password = ""
+		}
+		if conn.Token == conn.MaskedToken() {
+			conn.Token = ""
+		}
+		if strings.Trim(conn.ClientKey, "•") == "" {
+			conn.ClientKey = ""
+		}
+		if conn.Name == "" {
+			conn.Name = config.ExpandNameTemplate(nameTemplate, conn)
+		}
+		if taken[conn.Name] {
+			results = append(results, result{Name: conn.Name, Status: "conflict", Error: "a connection with this name already exists"})
+			continue
+		}
+		if err := validateImported(conn); err != nil {
+			results = append(results, result{Name: conn.Name, Status: "invalid", Error: err.Error()})
+			continue
+		}
+		taken[conn.Name] = true
+		s.Connections.Create(conn)
+		results = append(results, result{Name: conn.Name, ID: conn.ID, Status: "created"})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+// validateImported checks an imported connection as CreateConnection does.
+func validateImported(conn *models.Connection) error {
+	if err := conn.Validate(); err != nil {
+		return err
+	}
+	if err := checkCACert(conn); err != nil {
+		return err
+	}
+	return platform.CheckClientCert(conn)
+}
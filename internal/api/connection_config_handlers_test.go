@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func newConnectionConfigRouter(s *Server) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/connections/export", s.ExportConnections)
+	r.Post("/api/connections/import", s.ImportConnections)
+	return r
+}
+
+func TestExportImportConnections_RoundTrip(t *testing.T) {
+	src := &Server{Connections: models.NewConnectionStore()}
+	src.Connections.Create(&models.Connection{Name: "awx", Type: "awx", Role: "source", Scheme: "http",
+		Host: "awx.lab.local", Port: 80, Username: "admin", Password: "hunter2"})
+	src.Connections.Create(&models.Connection{Name: "aap", Type: "aap", Role: "auditor", Scheme: "https",
+		Host: "aap.lab.local", Port: 8443, Token: "tok-123", Timeout: 45 * time.Second, ImportConcurrency: 8})
+
+	for _, query := range []string{"", "?masked=true"} {
+		rec := httptest.NewRecorder()
+		newConnectionConfigRouter(src).ServeHTTP(rec, httptest.NewRequest("GET", "/api/connections/export"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("export%s status = %d, want 200", query, rec.Code)
+		}
+		body := rec.Body.String()
+		if strings.Contains(body, "hunter2") || strings.Contains(body, "tok-123") {
+			t.Errorf("export%s leaks a secret:\n%s", query, body)
+		}
+		if masked := strings.Contains(body, "••••"); masked != (query != "") {
+			t.Errorf("export%s masked = %v:\n%s", query, masked, body)
+		}
+
+		dst := &Server{Connections: models.NewConnectionStore()}
+		rec = httptest.NewRecorder()
+		newConnectionConfigRouter(dst).ServeHTTP(rec, httptest.NewRequest("POST", "/api/connections/import", bytes.NewBufferString(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("import status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+
+		byName := make(map[string]*models.Connection)
+		for _, c := range dst.Connections.List() {
+			byName[c.Name] = c
+		}
+		aap := byName["aap"]
+		if len(byName) != 2 || aap == nil {
+			t.Fatalf("imported connections = %v, want awx and aap", byName)
+		}
+		if aap.Role != "auditor" || aap.Port != 8443 || aap.Timeout != 45*time.Second || aap.ImportConcurrency != 8 {
+			t.Errorf("imported aap = %+v, want its settings kept", aap)
+		}
+		if aap.Token != "" || byName["awx"].Password != "" {
+			t.Errorf("imported token/password = %q/%q, want empty", aap.Token, byName["awx"].Password)
+		}
+	}
+}
+
+func TestImportConnections_DefaultsAndConflicts(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore()}
+	s.Connections.Create(&models.Connection{Name: "taken", Type: "awx", Host: "old.lab.local"})
+
+	body := `connections:
+  - type: aap
+    host: aap.lab.local
+  - name: taken
+    type: awx
+    host: awx.lab.local
+  - name: bad
+    type: tower
+    host: tower.lab.local
+`
+	rec := httptest.NewRecorder()
+	newConnectionConfigRouter(s).ServeHTTP(rec, httptest.NewRequest("POST", "/api/connections/import", strings.NewReader(body)))
+	var resp struct {
+		Results []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	want := []string{"aap-aap.lab.local:created", "taken:conflict", "bad:invalid"}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("results = %+v, want %v", resp.Results, want)
+	}
+	for i, r := range resp.Results {
+		if got := r.Name + ":" + r.Status; got != want[i] {
+			t.Errorf("result %d = %s, want %s", i, got, want[i])
+		}
+	}
+	for _, c := range s.Connections.List() {
+		if c.Name == "aap-aap.lab.local" && (c.Role != "destination" || c.Scheme != "https" || c.Port != 443) {
+			t.Errorf("defaults = %s/%s/%d, want destination/https/443", c.Role, c.Scheme, c.Port)
+		}
+	}
+}
+
+func TestImportConnections_UsesNameTemplate(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore(), NameTemplate: "{role}-{host}"}
+	body := "connections:\n  - type: aap\n    host: aap.lab.local\n"
+	rec := httptest.NewRecorder()
+	newConnectionConfigRouter(s).ServeHTTP(rec, httptest.NewRequest("POST", "/api/connections/import", strings.NewReader(body)))
+	if conns := s.Connections.List(); len(conns) != 1 || conns[0].Name != "destination-aap.lab.local" {
+		t.Errorf("imported connections = %+v, want one named destination-aap.lab.local", conns)
+	}
+}
@@ -2,6 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -15,13 +18,17 @@ func (s *Server) CreateConnection(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
 		return
 	}
-	if conn.Host == "" {
-		writeError(w, http.StatusBadRequest, "host is required")
-		return
+	if conn.Scheme == "" {
+		conn.Scheme = "https"
 	}
-	if conn.Type == "" {
-		conn.Type = "awx"
+	if conn.Port == 0 {
+		if conn.Scheme == "https" {
+			conn.Port = 443
+		} else {
+			conn.Port = 80
+		}
 	}
+	detectType(&conn)
 	if conn.Role == "" {
 		if conn.Type == "awx" {
 			conn.Role = "source"
@@ -29,33 +36,81 @@ func (s *Server) CreateConnection(w http.ResponseWriter, r *http.Request) {
 			conn.Role = "destination"
 		}
 	}
-	if conn.Scheme == "" {
-		conn.Scheme = "https"
+	if err := conn.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	if conn.Port == 0 {
-		if conn.Scheme == "https" {
-			conn.Port = 443
-		} else {
-			conn.Port = 80
-		}
+	if err := checkCACert(&conn); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := platform.CheckClientCert(&conn); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 	s.Connections.Create(&conn)
 	resp := conn
 	resp.Password = conn.MaskedPassword()
+	resp.Token = conn.MaskedToken()
+	resp.ClientKey = conn.MaskedClientKey()
 	writeJSON(w, http.StatusCreated, resp)
 }
 
+// errCACertFile is returned for any unusable ca_cert_file, so API callers
+// cannot probe which paths exist or are readable on the workbench host.
+var errCACertFile = errors.New("ca_cert_file could not be loaded as a PEM CA bundle")
+
+// checkCACert is platform.CheckCACert for API requests: the details are
+// logged and the caller gets errCACertFile.
+func checkCACert(conn *models.Connection) error {
+	if err := platform.CheckCACert(conn); err != nil {
+		log.Printf("Connection %q: %v", conn.Name, err)
+		return errCACertFile
+	}
+	return nil
+}
+
+// detectType fills in a blank connection type from the platform's API root,
+// falling back to awx when the platform cannot be reached or recognized.
+func detectType(conn *models.Connection) {
+	if conn.Type != "" || conn.Host == "" {
+		return
+	}
+	conn.Type = "awx"
+	if t, err := platform.DetectType(platform.NewClient(conn)); err == nil {
+		conn.Type = t
+	}
+}
+
 func (s *Server) ListConnections(w http.ResponseWriter, r *http.Request) {
 	conns := s.Connections.List()
-	// Return copies with masked passwords
 	masked := make([]models.Connection, len(conns))
 	for i, c := range conns {
-		masked[i] = *c
-		masked[i].Password = c.MaskedPassword()
+		masked[i] = maskedConnection(c)
 	}
 	writeJSON(w, http.StatusOK, masked)
 }
 
+// GetConnection returns one connection, with its last health check and
+// detected version, by ID.
+func (s *Server) GetConnection(w http.ResponseWriter, r *http.Request) {
+	conn := s.Connections.Get(chi.URLParam(r, "id"))
+	if conn == nil {
+		writeError(w, http.StatusNotFound, "connection not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, maskedConnection(conn))
+}
+
+// maskedConnection returns a copy of c with its password and token masked.
+func maskedConnection(c *models.Connection) models.Connection {
+	masked := *c
+	masked.Password = c.MaskedPassword()
+	masked.Token = c.MaskedToken()
+	masked.ClientKey = c.MaskedClientKey()
+	return masked
+}
+
 func (s *Server) UpdateConnection(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	var conn models.Connection
@@ -64,24 +119,117 @@ func (s *Server) UpdateConnection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	conn.ID = id
+	detectType(&conn)
+	if err := conn.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := checkCACert(&conn); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := platform.CheckClientCert(&conn); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if !s.Connections.Update(&conn) {
 		writeError(w, http.StatusNotFound, "connection not found")
 		return
 	}
+	s.ListCache.Invalidate(id)
 	resp := conn
 	resp.Password = conn.MaskedPassword()
+	resp.Token = conn.MaskedToken()
+	resp.ClientKey = conn.MaskedClientKey()
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// DeleteConnection removes a connection, unless a running job uses it.
 func (s *Server) DeleteConnection(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	if !s.Connections.Delete(id) {
-		writeError(w, http.StatusNotFound, "connection not found")
+	if err := s.deleteConnection(chi.URLParam(r, "id")); err != nil {
+		writeError(w, deleteStatus(err), err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+var (
+	errConnectionNotFound = errors.New("connection not found")
+	errConnectionInUse    = errors.New("connection has running jobs")
+)
+
+// deleteConnection removes connection id and what is cached for it. A
+// connection used by a running job, as source or destination, is kept.
+func (s *Server) deleteConnection(id string) error {
+	if s.Connections.Get(id) == nil {
+		return errConnectionNotFound
+	}
+	if s.Jobs.HasRunning(id) {
+		return errConnectionInUse
+	}
+	if !s.Connections.Delete(id) {
+		return errConnectionNotFound
+	}
+	s.ListCache.Invalidate(id)
+	platform.ForgetConnection(id)
+	return nil
+}
+
+// deleteStatus maps a deleteConnection error to its HTTP status.
+func deleteStatus(err error) int {
+	if err == errConnectionInUse {
+		return http.StatusConflict
+	}
+	return http.StatusNotFound
+}
+
+// BulkDeleteConnections removes several connections at once. Targets are the
+// IDs listed in the JSON body ({"ids": [...]}) or, if none are given, every
+// connection matching the ?role= and/or ?type= query filters. Connections
+// used by running jobs are left in place and reported as a conflict, as
+// DeleteConnection does.
+func (s *Server) BulkDeleteConnections(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	ids := req.IDs
+	if len(ids) == 0 {
+		role, connType := r.URL.Query().Get("role"), r.URL.Query().Get("type")
+		if role == "" && connType == "" {
+			writeError(w, http.StatusBadRequest, "ids or a role/type filter is required")
+			return
+		}
+		for _, c := range s.Connections.List() {
+			if (role == "" || c.Role == role) && (connType == "" || c.Type == connType) {
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+
+	type result struct {
+		ID     string `json:"id"`
+		Status string `json:"status"` // "deleted", "not_found", "conflict"
+		Error  string `json:"error,omitempty"`
+	}
+	results := make([]result, 0, len(ids))
+	for _, id := range ids {
+		switch err := s.deleteConnection(id); {
+		case err == nil:
+			results = append(results, result{ID: id, Status: "deleted"})
+		case deleteStatus(err) == http.StatusConflict:
+			results = append(results, result{ID: id, Status: "conflict", Error: err.Error()})
+		default:
+			results = append(results, result{ID: id, Status: "not_found", Error: err.Error()})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
 func (s *Server) TestConnection(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	conn := s.Connections.Get(id)
@@ -89,52 +237,34 @@ func (s *Server) TestConnection(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "connection not found")
 		return
 	}
-	p := platform.NewPlatform(conn)
-	client := platform.NewClient(conn)
-
-	// Step 1: connectivity check (unauthenticated)
-	pingStatus, pingError := "ok", ""
-	if err := p.Ping(); err != nil {
-		pingStatus = "error"
-		pingError = err.Error()
+	if conn.Type == "" {
+		updated := *conn
+		detectType(&updated)
+		s.Connections.Update(&updated)
+		conn = &updated
 	}
+	writeJSON(w, http.StatusOK, platform.CheckConnection(conn, s.Connections))
+}
 
-	// Step 2: credential check (authenticated)
-	authStatus, authError := "unknown", ""
-	version := conn.Version
-	if pingStatus == "ok" {
-		if conn.Username == "" || conn.Password == "" {
-			authStatus = "error"
-			authError = "no credentials configured"
-		} else if err := p.CheckAuth(); err != nil {
-			authStatus = "error"
-			authError = err.Error()
-		} else {
-			authStatus = "ok"
-
-			// Step 3: discovery (only after auth succeeds)
-			var pingResp *platform.PingResponse
-			for _, pp := range platform.PingPaths(conn.Type) {
-				pingResp, err = client.PingWithVersion(pp)
-				if err == nil {
-					break
-				}
-			}
-			if err == nil && pingResp.Version != "" {
-				version = pingResp.Version
-				conn.Version = version
-				s.Connections.SetVersion(id, version, "")
-			}
-			platform.DiscoverAndStore(client, conn, s.Connections)
-		}
+// RefreshConnectionVersion re-runs version and API prefix discovery for a
+// connection, e.g. after the platform was upgraded, without a health check.
+func (s *Server) RefreshConnectionVersion(w http.ResponseWriter, r *http.Request) {
+	conn := s.Connections.Get(chi.URLParam(r, "id"))
+	if conn == nil {
+		writeError(w, http.StatusNotFound, "connection not found")
+		return
 	}
+	info, err := platform.RefreshVersion(conn, s.Connections)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
 
-	s.Connections.SetHealth(id, pingStatus, pingError, authStatus, authError)
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"ping_ok":    pingStatus == "ok",
-		"ping_error": pingError,
-		"auth_ok":    authStatus == "ok",
-		"auth_error": authError,
-		"version":    version,
-	})
+// TestAllConnections tests every connection concurrently and returns one
+// result per connection.
+func (s *Server) TestAllConnections(w http.ResponseWriter, r *http.Request) {
+	results := platform.CheckConnections(s.Connections.List(), s.Connections, platform.DefaultCheckConcurrency)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
 }
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func newConnectionRouter(s *Server) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/connections", s.CreateConnection)
+	r.Get("/api/connections/{id}", s.GetConnection)
+	r.Delete("/api/connections", s.BulkDeleteConnections)
+	r.Delete("/api/connections/{id}", s.DeleteConnection)
+	return r
+}
+
+func TestGetConnection(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore()}
+	conn := &models.Connection{Name: "awx", Type: "awx", Host: "awx.example.com", Password: "secret", Token: "tok", Version: "23.4.0"}
+	s.Connections.Create(conn)
+
+	rec := httptest.NewRecorder()
+	newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("GET", "/api/connections/"+conn.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var got models.Connection
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if got.ID != conn.ID || got.Version != "23.4.0" {
+		t.Errorf("got connection %+v, want ID %s with version 23.4.0", got, conn.ID)
+	}
+	if got.Password != conn.MaskedPassword() || got.Token != conn.MaskedToken() {
+		t.Errorf("password/token = %q/%q, want masked", got.Password, got.Token)
+	}
+	if conn.Password != "secret" {
+		t.Error("masking modified the stored connection")
+	}
+}
+
+func TestGetConnection_NotFound(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore()}
+	rec := httptest.NewRecorder()
+	newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("GET", "/api/connections/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestCreateConnection_DetectsType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"apis":{"gateway":"/api/gateway/","controller":"/api/controller/"}}`))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+
+	s := &Server{Connections: models.NewConnectionStore()}
+	body := `{"name":"lab","scheme":"http","host":"` + host + `","port":` + strconv.Itoa(port) + `,"username":"admin","password":"secret"}`
+	rec := httptest.NewRecorder()
+	newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("POST", "/api/connections", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var got models.Connection
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if got.Type != "aap" || got.Role != "destination" {
+		t.Errorf("type/role = %q/%q, want aap/destination", got.Type, got.Role)
+	}
+}
+
+func TestCreateConnection_CACertFileErrorIsGeneric(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{Connections: models.NewConnectionStore()}
+	var bodies []string
+	for _, path := range []string{invalid, "/nonexistent/ca.pem"} {
+		body := `{"name":"lab","type":"awx","host":"awx.example.com","username":"admin","password":"secret","ca_cert_file":"` + path + `"}`
+		rec := httptest.NewRecorder()
+		newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("POST", "/api/connections", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", path, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), path) {
+			t.Errorf("%s: error reveals the path: %s", path, rec.Body)
+		}
+		bodies = append(bodies, rec.Body.String())
+	}
+	// An unreadable path and an existing file give the same answer.
+	if bodies[0] != bodies[1] {
+		t.Errorf("errors differ: %s vs %s", bodies[0], bodies[1])
+	}
+}
+
+// bulkDelete sends DELETE /api/connections and returns the status of each ID.
+func bulkDelete(t *testing.T, s *Server, query, body string) map[string]string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/connections"+query, strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Results []struct{ ID, Status string }
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	statuses := make(map[string]string)
+	for _, r := range resp.Results {
+		statuses[r.ID] = r.Status
+	}
+	return statuses
+}
+
+func TestBulkDeleteConnections_MixedIDs(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore()}
+	a := &models.Connection{Name: "a", Type: "awx"}
+	b := &models.Connection{Name: "b", Type: "aap"}
+	s.Connections.Create(a)
+	s.Connections.Create(b)
+
+	got := bulkDelete(t, s, "", `{"ids":["`+a.ID+`","missing"]}`)
+	if got[a.ID] != "deleted" || got["missing"] != "not_found" || len(got) != 2 {
+		t.Errorf("results = %v, want %s deleted and missing not_found", got, a.ID)
+	}
+	if s.Connections.Get(a.ID) != nil || s.Connections.Get(b.ID) == nil {
+		t.Error("only the listed connection should be deleted")
+	}
+}
+
+func TestBulkDeleteConnections_Filter(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore()}
+	src := &models.Connection{Name: "src", Type: "awx", Role: "source"}
+	dstAAP := &models.Connection{Name: "dst", Type: "aap", Role: "destination"}
+	srcAAP := &models.Connection{Name: "old-aap", Type: "aap", Role: "source"}
+	for _, c := range []*models.Connection{src, dstAAP, srcAAP} {
+		s.Connections.Create(c)
+	}
+
+	got := bulkDelete(t, s, "?role=source&type=aap", "")
+	if len(got) != 1 || got[srcAAP.ID] != "deleted" {
+		t.Errorf("results = %v, want only %s deleted", got, srcAAP.ID)
+	}
+	if s.Connections.Get(src.ID) == nil || s.Connections.Get(dstAAP.ID) == nil {
+		t.Error("connections outside the filter were deleted")
+	}
+
+	rec := httptest.NewRecorder()
+	newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/connections", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("no ids and no filter: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestDeleteConnection_RunningJobConflict(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore()}
+	src := &models.Connection{Name: "src", Type: "awx"}
+	dst := &models.Connection{Name: "dst", Type: "aap"}
+	s.Connections.Create(src)
+	s.Connections.Create(dst)
+	// A one-shot migration is listed under its destination but also reads
+	// from its source.
+	job := s.Jobs.Create("migration-one-shot", dst.ID, src.ID)
+
+	if got := bulkDelete(t, s, "", `{"ids":["`+src.ID+`","`+dst.ID+`"]}`); got[src.ID] != "conflict" || got[dst.ID] != "conflict" {
+		t.Errorf("bulk results = %v, want both conflict", got)
+	}
+	rec := httptest.NewRecorder()
+	newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/connections/"+src.ID, nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("single delete of the source: status = %d, want 409", rec.Code)
+	}
+	if s.Connections.Get(src.ID) == nil || s.Connections.Get(dst.ID) == nil {
+		t.Fatal("connections of a running job were deleted")
+	}
+
+	job.Complete()
+	rec = httptest.NewRecorder()
+	newConnectionRouter(s).ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/connections/"+src.ID, nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("delete after the job finished: status = %d, want 204", rec.Code)
+	}
+}
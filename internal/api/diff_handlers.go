@@ -0,0 +1,118 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/migration"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// ResourceDiffHandler compares a single named object between two connections.
+func (s *Server) ResourceDiffHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	typeName, name := q.Get("type"), q.Get("name")
+	if typeName == "" || name == "" {
+		writeError(w, http.StatusBadRequest, "type and name are required")
+		return
+	}
+	if !migration.SupportsDiff(typeName) {
+		writeError(w, http.StatusBadRequest, "unsupported resource type: "+typeName)
+		return
+	}
+
+	src := s.Connections.Get(q.Get("source_id"))
+	if src == nil {
+		writeError(w, http.StatusNotFound, "source connection not found")
+		return
+	}
+	dst := s.Connections.Get(q.Get("destination_id"))
+	if dst == nil {
+		writeError(w, http.StatusNotFound, "destination connection not found")
+		return
+	}
+
+	diff, err := migration.DiffResource(src, dst, typeName, name)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	if diff.Differences == nil {
+		diff.Differences = []models.FieldDiff{}
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
+
+// ConnectionDiffHandler starts a job comparing which objects exist on a
+// connection and a destination connection, by name and per resource type.
+// An optional comma-separated "types" parameter limits the comparison. Both
+// connections are exported in full, so the result is fetched from
+// GetConnectionDiff once the job has finished.
+func (s *Server) ConnectionDiffHandler(w http.ResponseWriter, r *http.Request) {
+	src := s.Connections.Get(chi.URLParam(r, "id"))
+	if src == nil {
+		writeError(w, http.StatusNotFound, "source connection not found")
+		return
+	}
+	dst := s.Connections.Get(r.URL.Query().Get("destination_id"))
+	if dst == nil {
+		writeError(w, http.StatusNotFound, "destination connection not found")
+		return
+	}
+
+	var types []string
+	if t := r.URL.Query().Get("types"); t != "" {
+		types = strings.Split(t, ",")
+	}
+	if err := migration.ValidateTypes(types); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job := s.Jobs.Create("connection-diff", src.ID, dst.ID)
+
+	s.Jobs.Go(func() {
+		diff, err := migration.DiffConnections(job.Context(), src, dst, types, job.AppendLog)
+		if err == nil {
+			s.diffs.Store(job.ID, diff)
+		}
+		finishJob(job, err)
+	})
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
+}
+
+// GetConnectionDiff returns the result of a finished connection diff job.
+func (s *Server) GetConnectionDiff(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "jobId")
+
+	job := s.Jobs.Get(jobID)
+	if job == nil {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	if job.Status == "running" {
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"status":  "running",
+			"message": "diff is still in progress",
+		})
+		return
+	}
+
+	if job.Status == "failed" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status": "failed",
+			"error":  job.Error,
+		})
+		return
+	}
+
+	diff, ok := s.diffs.Load(jobID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "diff result not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestConnectionDiffHandler_RunsAsJob(t *testing.T) {
+	srcTS, src := emptyPlatform(t)
+	defer srcTS.Close()
+	dstTS, dst := emptyPlatform(t)
+	defer dstTS.Close()
+	src.Name, dst.Name = "old", "new"
+
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore()}
+	s.Connections.Create(src)
+	s.Connections.Create(dst)
+	finished := make(chan models.JobResult, 1)
+	s.Jobs.OnFinish(func(r models.JobResult) { finished <- r })
+	r := chi.NewRouter()
+	r.Post("/api/connections/{id}/diff", s.ConnectionDiffHandler)
+	r.Get("/api/diff/connections/{jobId}", s.GetConnectionDiff)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/connections/"+src.ID+"/diff?destination_id="+dst.ID+"&types=organizations", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		JobID string `json:"job_id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&resp)
+
+	select {
+	case result := <-finished:
+		if result.Status != "completed" {
+			t.Fatalf("diff job finished as %q: %s", result.Status, result.Error)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("diff job did not finish")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/diff/connections/"+resp.JobID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("result status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var diff models.ConnectionDiff
+	if err := json.NewDecoder(rec.Body).Decode(&diff); err != nil {
+		t.Fatalf("decoding diff: %v", err)
+	}
+	if diff.SourceID != src.ID || diff.DestinationID != dst.ID {
+		t.Errorf("diff is for %s → %s, want %s → %s", diff.SourceID, diff.DestinationID, src.ID, dst.ID)
+	}
+	if _, ok := diff.Types["organizations"]; !ok {
+		t.Errorf("diff types = %v, want organizations", diff.Types)
+	}
+}
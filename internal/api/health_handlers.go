@@ -0,0 +1,25 @@
+package api
+
+import "net/http"
+
+// MarkReady makes /readyz report the server as ready. It is called once the
+// connections from the config file have been checked.
+func (s *Server) MarkReady() {
+	s.ready.Store(true)
+}
+
+// Healthz answers liveness probes: it succeeds whenever the server is
+// handling requests.
+func (s *Server) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readyz answers readiness probes: it fails with 503 until MarkReady is
+// called.
+func (s *Server) Readyz(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "starting"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
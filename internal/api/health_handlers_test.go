@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHealthAndReadiness(t *testing.T) {
+	s := &Server{}
+	router := NewRouter(s, fstest.MapFS{})
+	status := func(path string) int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec.Code
+	}
+
+	if got := status("/healthz"); got != http.StatusOK {
+		t.Errorf("/healthz before ready = %d, want 200", got)
+	}
+	if got := status("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz before ready = %d, want 503", got)
+	}
+
+	s.MarkReady()
+	if got := status("/healthz"); got != http.StatusOK {
+		t.Errorf("/healthz after ready = %d, want 200", got)
+	}
+	if got := status("/readyz"); got != http.StatusOK {
+		t.Errorf("/readyz after ready = %d, want 200", got)
+	}
+}
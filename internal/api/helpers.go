@@ -2,7 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 )
 
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
@@ -14,3 +17,13 @@ func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 func writeError(w http.ResponseWriter, status int, msg string) {
 	writeJSON(w, status, map[string]string{"error": msg})
 }
+
+// rejectReadOnly writes a 403 and returns true when conn is an auditor
+// connection, which operations that modify the platform must not use.
+func rejectReadOnly(w http.ResponseWriter, conn *models.Connection) bool {
+	if !conn.ReadOnly() {
+		return false
+	}
+	writeError(w, http.StatusForbidden, fmt.Sprintf("connection %q has the auditor role and is read-only", conn.Name))
+	return true
+}
@@ -1,16 +1,55 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 )
 
+// ListJobs returns jobs most recent first. ?limit= and ?offset= select a
+// page; total is the number of jobs across all pages.
 func (s *Server) ListJobs(w http.ResponseWriter, r *http.Request) {
-	jobs := s.Jobs.List()
-	writeJSON(w, http.StatusOK, jobs)
+	limit, ok := queryInt(w, r, "limit")
+	if !ok {
+		return
+	}
+	offset, ok := queryInt(w, r, "offset")
+	if !ok {
+		return
+	}
+	jobs, total := s.Jobs.Page(limit, offset)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs":   jobs,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
 }
 
+// queryInt reads a non-negative integer query parameter, defaulting to 0.
+// It writes a 400 response and returns false if the value is invalid.
+func queryInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		writeError(w, http.StatusBadRequest, name+" must be a non-negative integer")
+		return 0, false
+	}
+	return n, true
+}
+
+// GetJob returns a job. With ?log=structured the response also carries
+// log_entries, the output classified by level, phase and resource; this
+// needs structured_logs in the config.
 func (s *Server) GetJob(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	job := s.Jobs.Get(id)
@@ -18,10 +57,52 @@ func (s *Server) GetJob(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "job not found")
 		return
 	}
+	if r.URL.Query().Get("log") == "structured" {
+		if !s.Jobs.StructuredLogs() {
+			writeError(w, http.StatusBadRequest, "structured job logs are disabled; set structured_logs in the config")
+			return
+		}
+		writeJSON(w, http.StatusOK, struct {
+			*models.Job
+			LogEntries []models.JobLogEntry `json:"log_entries"`
+		}{job, job.LogEntries()})
+		return
+	}
 	writeJSON(w, http.StatusOK, job)
 }
 
-// CancelJob cancels a running job.
+// GetJobLogs returns a job's log lines from ?offset= onwards, for clients that
+// cannot use the WebSocket stream. The returned offset is where the next poll
+// should continue; clients can stop once status is no longer "running".
+func (s *Server) GetJobLogs(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	job := s.Jobs.Get(id)
+	if job == nil {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	offset, ok := queryInt(w, r, "offset")
+	if !ok {
+		return
+	}
+
+	// Read the status before the lines so a finished status always comes
+	// with the complete output.
+	status := job.Status
+	lines, next := job.LogsFrom(offset)
+	if lines == nil {
+		lines = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": status,
+		"offset": next,
+		"lines":  lines,
+	})
+}
+
+// CancelJob cancels a running job. The CANCELLED line is logged here, before
+// the job's context is cancelled, so the job goroutine does not log its own.
 func (s *Server) CancelJob(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	job := s.Jobs.Get(id)
@@ -33,7 +114,51 @@ func (s *Server) CancelJob(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusConflict, "job is not running")
 		return
 	}
+	job.AppendLog("CANCELLED: " + job.Type + " stopped by user")
 	job.Cancel()
-	job.AppendLog("CANCELLED: migration stopped by user")
 	writeJSON(w, http.StatusOK, map[string]string{"status": "cancelled"})
 }
+
+// DeleteJob removes a finished job and its log. Running jobs must be
+// cancelled first.
+func (s *Server) DeleteJob(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	err := s.Jobs.Delete(id)
+	if err == nil {
+		s.diffs.Delete(id)
+	}
+	switch {
+	case errors.Is(err, models.ErrJobNotFound):
+		writeError(w, http.StatusNotFound, "job not found")
+	case errors.Is(err, models.ErrJobRunning):
+		writeError(w, http.StatusConflict, "job is still running")
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// PruneJobs removes every finished job older than the body's older_than
+// duration (e.g. "168h"); without one, all finished jobs are removed.
+func (s *Server) PruneJobs(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OlderThan string `json:"older_than"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	var age time.Duration
+	if req.OlderThan != "" {
+		var err error
+		age, err = time.ParseDuration(req.OlderThan)
+		if err != nil || age < 0 {
+			writeError(w, http.StatusBadRequest, "older_than must be a non-negative duration such as 24h")
+			return
+		}
+	}
+	removed := s.Jobs.Prune(age)
+	for _, id := range removed {
+		s.diffs.Delete(id)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"removed": removed})
+}
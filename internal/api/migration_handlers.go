@@ -2,8 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rflorenc/ansible-automation-workbench/internal/migration"
@@ -15,6 +20,20 @@ import (
 type previewCache struct {
 	Preview    *models.MigrationPreview
 	ExportData *migration.ExportedData
+	Source     *models.Connection
+	ExportedAt time.Time
+	Conflicts  map[string]string
+
+	// Set on entries stored under a run job ID when the run did not finish,
+	// so it can be continued with POST /migrate/resume. Like the rest of the
+	// store it is kept in memory only, so a restart discards it.
+	Resume        *migration.ResumeState
+	DestinationID string
+	Exclude       map[string][]string
+
+	// IncludeGroups is the preview's inventory name → groups selection,
+	// used by runs that do not send their own.
+	IncludeGroups map[string][]string
 }
 
 // PreviewStore provides thread-safe storage for migration previews.
@@ -48,13 +67,35 @@ func (ps *PreviewStore) Delete(jobID string) {
 // MigrationPreviewHandler starts an async preview job (export + preflight).
 func (s *Server) MigrationPreviewHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		SourceID      string `json:"source_id"`
-		DestinationID string `json:"destination_id"`
+		SourceID        string `json:"source_id"`
+		DestinationID   string `json:"destination_id"`
+		ExcludeDisabled bool   `json:"exclude_disabled"`
+		Concurrency     int    `json:"concurrency"`
+		// Conflicts sets, per resource type, whether existing destination
+		// resources are skipped ("skip") or updated ("update").
+		Conflicts map[string]string `json:"conflicts"`
+		// Types limits the migration to these resource types and their
+		// dependencies. Empty migrates every type.
+		Types []string `json:"types"`
+		// Exclude lists resource names, per type, to leave out of the
+		// migration; they are previewed as "skip_excluded".
+		Exclude map[string][]string `json:"exclude"`
+		// IncludeGroups limits inventories to some of their groups and
+		// those groups' hosts (inventory name → group names).
+		IncludeGroups map[string][]string `json:"include_groups"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
 		return
 	}
+	if err := migration.ValidateTypes(req.Types); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := migration.ValidateConflicts(req.Conflicts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	src := s.Connections.Get(req.SourceID)
 	if src == nil {
@@ -67,10 +108,21 @@ func (s *Server) MigrationPreviewHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	job := s.Jobs.Create("migration-preview", req.SourceID)
+	job := s.Jobs.Create("migration-preview", req.SourceID, req.DestinationID)
 
-	go func() {
-		preview, data, err := migration.Preview(src, dst, job.AppendLog)
+	s.Jobs.Go(func() {
+		opts := migration.ExportOptions{
+			ExcludeDisabled: req.ExcludeDisabled,
+			Concurrency:     s.exportConcurrency(req.Concurrency),
+			Conflicts:       req.Conflicts,
+			Types:           req.Types,
+			Transforms:      s.Transforms,
+			Exclude:         req.Exclude,
+		}
+		preview, data, err := migration.Preview(job.Context(), src, dst, opts, job.AppendLog)
+		if job.IsCancelled() {
+			return
+		}
 		if err != nil {
 			job.AppendLog("ERROR: " + err.Error())
 			job.Fail(err.Error())
@@ -78,12 +130,16 @@ func (s *Server) MigrationPreviewHandler(w http.ResponseWriter, r *http.Request)
 		}
 
 		s.Previews.Store(job.ID, &previewCache{
-			Preview:    preview,
-			ExportData: data,
+			Preview:       preview,
+			ExportData:    data,
+			Source:        src,
+			ExportedAt:    time.Now(),
+			Conflicts:     req.Conflicts,
+			IncludeGroups: req.IncludeGroups,
 		})
 
 		job.Complete()
-	}()
+	})
 
 	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
 }
@@ -123,6 +179,21 @@ func (s *Server) GetMigrationPreview(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, cached.Preview)
 }
 
+// ExportPreviewBundle downloads the data captured by a preview as a single JSON bundle.
+func (s *Server) ExportPreviewBundle(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "jobId")
+
+	cached := s.Previews.Get(jobID)
+	if cached == nil {
+		writeError(w, http.StatusNotFound, "preview data not found")
+		return
+	}
+
+	bundle := migration.NewBundle(cached.Source, cached.ExportData, cached.ExportedAt)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%s.json"`, jobID))
+	writeJSON(w, http.StatusOK, bundle)
+}
+
 // MigrationRunHandler starts the import from a previously cached preview.
 func (s *Server) MigrationRunHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -130,6 +201,7 @@ func (s *Server) MigrationRunHandler(w http.ResponseWriter, r *http.Request) {
 		DestinationID string              `json:"destination_id"`
 		PreviewJobID  string              `json:"preview_job_id"`
 		Exclude       map[string][]string `json:"exclude"`
+		IncludeGroups map[string][]string `json:"include_groups"` // inventory name → groups to migrate
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
@@ -147,24 +219,388 @@ func (s *Server) MigrationRunHandler(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "destination connection not found")
 		return
 	}
+	if rejectReadOnly(w, dst) {
+		return
+	}
+	release, ok := s.lockDestination(w, req.DestinationID)
+	if !ok {
+		return
+	}
 
 	job := s.Jobs.Create("migration-run", req.DestinationID)
+	s.migrating.Store(req.DestinationID, job.ID)
 
-	go func() {
-		err := migration.Run(job.Context(), dst, cached.ExportData, cached.Preview, req.Exclude, job.AppendLog)
-		if err != nil {
-			if job.IsCancelled() {
-				job.AppendLog("CANCELLED: migration stopped by user")
-			} else {
-				job.AppendLog("ERROR: " + err.Error())
-				job.Fail(err.Error())
-			}
-		} else {
-			job.Complete()
-		}
+	s.Jobs.Go(func() {
+		defer release()
+		state := migration.NewResumeState()
+		opts := s.importOptions(req.Exclude, cached.includeGroups(req.IncludeGroups))
+		err := migration.Run(job.Context(), dst, cached.ExportData, cached.Preview, opts, state, job.SetProgress, job.AppendLog)
+		s.finishMigration(job, err, cached, state, req.DestinationID, opts)
 		// Clean up preview cache after migration completes
 		s.Previews.Delete(req.PreviewJobID)
-	}()
+	})
 
 	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
 }
+
+// MigrationDryRunHandler walks a cached preview's import without writing to
+// the destination, logging the body of every request the run would send and
+// any references it could not resolve. The preview stays cached, so a run
+// can follow. Read-only destinations are allowed, since nothing is written.
+func (s *Server) MigrationDryRunHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DestinationID string              `json:"destination_id"`
+		PreviewJobID  string              `json:"preview_job_id"`
+		Exclude       map[string][]string `json:"exclude"`
+		IncludeGroups map[string][]string `json:"include_groups"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	cached := s.Previews.Get(req.PreviewJobID)
+	if cached == nil {
+		writeError(w, http.StatusNotFound, "preview not found — run preview first")
+		return
+	}
+
+	dst := s.Connections.Get(req.DestinationID)
+	if dst == nil {
+		writeError(w, http.StatusNotFound, "destination connection not found")
+		return
+	}
+
+	job := s.Jobs.Create("migration-dry-run", req.DestinationID)
+
+	s.Jobs.Go(func() {
+		opts := s.importOptions(req.Exclude, cached.includeGroups(req.IncludeGroups))
+		err := migration.DryRun(job.Context(), dst, cached.ExportData, cached.Preview, opts, job.SetProgress, job.AppendLog)
+		finishJob(job, err)
+	})
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
+}
+
+// MigrateHandler runs a preview and the import in a single job, for clients
+// that do not need to review the preview before migrating. The job's log
+// covers export, preflight and import; a run that does not complete can be
+// resumed like one started with MigrationRunHandler.
+func (s *Server) MigrateHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SourceID        string              `json:"source_id"`
+		DestinationID   string              `json:"destination_id"`
+		ExcludeDisabled bool                `json:"exclude_disabled"`
+		Concurrency     int                 `json:"concurrency"`
+		Conflicts       map[string]string   `json:"conflicts"`
+		Types           []string            `json:"types"`
+		Exclude         map[string][]string `json:"exclude"`
+		IncludeGroups   map[string][]string `json:"include_groups"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if err := migration.ValidateTypes(req.Types); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := migration.ValidateConflicts(req.Conflicts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	src := s.Connections.Get(req.SourceID)
+	if src == nil {
+		writeError(w, http.StatusNotFound, "source connection not found")
+		return
+	}
+	dst := s.Connections.Get(req.DestinationID)
+	if dst == nil {
+		writeError(w, http.StatusNotFound, "destination connection not found")
+		return
+	}
+	if rejectReadOnly(w, dst) {
+		return
+	}
+	release, ok := s.lockDestination(w, req.DestinationID)
+	if !ok {
+		return
+	}
+
+	job := s.Jobs.Create("migration-one-shot", req.DestinationID, req.SourceID)
+	s.migrating.Store(req.DestinationID, job.ID)
+
+	s.Jobs.Go(func() {
+		defer release()
+		opts := migration.ExportOptions{
+			ExcludeDisabled: req.ExcludeDisabled,
+			Concurrency:     s.exportConcurrency(req.Concurrency),
+			Conflicts:       req.Conflicts,
+			Types:           req.Types,
+			Transforms:      s.Transforms,
+			Exclude:         req.Exclude,
+		}
+		preview, data, err := migration.Preview(job.Context(), src, dst, opts, job.AppendLog)
+		if err != nil {
+			finishJob(job, err)
+			return
+		}
+		cached := &previewCache{
+			Preview:    preview,
+			ExportData: data,
+			Source:     src,
+			ExportedAt: time.Now(),
+			Conflicts:  req.Conflicts,
+		}
+
+		job.AppendLog("")
+		state := migration.NewResumeState()
+		importOpts := s.importOptions(req.Exclude, req.IncludeGroups)
+		err = migration.Run(job.Context(), dst, data, preview, importOpts, state, job.SetProgress, job.AppendLog)
+		s.finishMigration(job, err, cached, state, req.DestinationID, importOpts)
+	})
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
+}
+
+// MigrationResumeHandler continues a cancelled or failed migration run,
+// skipping the resources that run already created. The saved state lives in
+// memory, so only runs that ended in this workbench process can be resumed.
+func (s *Server) MigrationResumeHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RunJobID string `json:"run_job_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	cached := s.Previews.Get(req.RunJobID)
+	if cached == nil || cached.Resume == nil {
+		writeError(w, http.StatusNotFound, "no resumable migration for this job; resume state is kept in memory and lost when the workbench restarts")
+		return
+	}
+	dst := s.Connections.Get(cached.DestinationID)
+	if dst == nil {
+		writeError(w, http.StatusNotFound, "destination connection not found")
+		return
+	}
+	if rejectReadOnly(w, dst) {
+		return
+	}
+	release, ok := s.lockDestination(w, cached.DestinationID)
+	if !ok {
+		return
+	}
+	// Only one run may use the saved state at a time.
+	s.Previews.Delete(req.RunJobID)
+
+	job := s.Jobs.Create("migration-resume", cached.DestinationID)
+	s.migrating.Store(cached.DestinationID, job.ID)
+
+	s.Jobs.Go(func() {
+		defer release()
+		job.AppendLog("Resuming migration job " + req.RunJobID)
+		opts := s.importOptions(cached.Exclude, cached.IncludeGroups)
+		err := migration.Resume(job.Context(), dst, cached.ExportData, cached.Conflicts, opts, cached.Resume, job.SetProgress, job.AppendLog)
+		s.finishMigration(job, err, cached, cached.Resume, cached.DestinationID, opts)
+	})
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
+}
+
+// lockDestination claims a destination for one migration, since overlapping
+// runs do not see each other's creates and would duplicate resources. It
+// writes 409 and returns false when another migration holds the destination;
+// otherwise the caller runs release once its job has finished.
+func (s *Server) lockDestination(w http.ResponseWriter, dstID string) (release func(), ok bool) {
+	if running, busy := s.migrating.LoadOrStore(dstID, ""); busy {
+		msg := "a migration into this destination is already running"
+		if id, _ := running.(string); id != "" {
+			msg += " (job " + id + ")"
+		}
+		writeError(w, http.StatusConflict, msg)
+		return nil, false
+	}
+	return func() { s.migrating.Delete(dstID) }, true
+}
+
+// finishMigration records the outcome of a migration run. A run that did not
+// complete keeps its state under the job ID so it can be resumed.
+func (s *Server) finishMigration(job *models.Job, err error, cached *previewCache, state *migration.ResumeState, dstID string, opts migration.ImportOptions) {
+	if err != nil {
+		s.Previews.Store(job.ID, &previewCache{
+			Preview:       cached.Preview,
+			ExportData:    cached.ExportData,
+			Source:        cached.Source,
+			ExportedAt:    cached.ExportedAt,
+			Conflicts:     cached.Conflicts,
+			Resume:        state,
+			DestinationID: dstID,
+			Exclude:       opts.Exclude,
+			IncludeGroups: opts.IncludeGroups,
+		})
+	}
+	finishJob(job, err)
+}
+
+// MigrationValidateHandler runs read-only sanity checks on a source and
+// destination pair and returns them as pass/warn/fail results. Unlike a
+// preview it exports nothing, so it answers synchronously.
+func (s *Server) MigrationValidateHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SourceID      string `json:"source_id"`
+		DestinationID string `json:"destination_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	src := s.Connections.Get(req.SourceID)
+	if src == nil {
+		writeError(w, http.StatusNotFound, "source connection not found")
+		return
+	}
+	dst := s.Connections.Get(req.DestinationID)
+	if dst == nil {
+		writeError(w, http.StatusNotFound, "destination connection not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, migration.SanityChecks(src, dst))
+}
+
+// ExportArchiveHandler exports a source connection to a gzip tarball in the
+// archive directory, for importing into a destination on another network
+// later.
+func (s *Server) ExportArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SourceID        string `json:"source_id"`
+		Name            string `json:"name"` // optional file name in the archive directory
+		ExcludeDisabled bool   `json:"exclude_disabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	src := s.Connections.Get(req.SourceID)
+	if src == nil {
+		writeError(w, http.StatusNotFound, "source connection not found")
+		return
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-%s.tar.gz", src.ID, time.Now().Format("20060102-150405"))
+	}
+	path, err := s.archivePath(name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		writeError(w, http.StatusInternalServerError, "creating archive directory: "+err.Error())
+		return
+	}
+
+	job := s.Jobs.Create("migration-export-archive", req.SourceID)
+
+	s.Jobs.Go(func() {
+		opts := migration.ExportOptions{ExcludeDisabled: req.ExcludeDisabled, Concurrency: s.exportConcurrency(0)}
+		err := migration.ExportToArchive(job.Context(), src, path, opts, job.AppendLog)
+		finishJob(job, err)
+	})
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID, "name": name})
+}
+
+// ImportArchiveHandler migrates the contents of an archive written by
+// ExportArchiveHandler, or of a migration-format export written by RunExport,
+// into a destination connection.
+func (s *Server) ImportArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DestinationID string              `json:"destination_id"`
+		Name          string              `json:"name"` // archive, or migration export directory, in the archive directory
+		Exclude       map[string][]string `json:"exclude"`
+		IncludeGroups map[string][]string `json:"include_groups"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	path, err := s.archivePath(req.Name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() && !info.IsDir() {
+		writeError(w, http.StatusNotFound, "archive not found: "+req.Name)
+		return
+	}
+
+	dst := s.Connections.Get(req.DestinationID)
+	if dst == nil {
+		writeError(w, http.StatusNotFound, "destination connection not found")
+		return
+	}
+	if rejectReadOnly(w, dst) {
+		return
+	}
+	release, ok := s.lockDestination(w, req.DestinationID)
+	if !ok {
+		return
+	}
+
+	job := s.Jobs.Create("migration-import-archive", req.DestinationID)
+	s.migrating.Store(req.DestinationID, job.ID)
+
+	s.Jobs.Go(func() {
+		defer release()
+		err := migration.RunFromArchive(job.Context(), dst, path, s.importOptions(req.Exclude, req.IncludeGroups), job.SetProgress, job.AppendLog)
+		finishJob(job, err)
+	})
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
+}
+
+// archivePath resolves an archive name inside the archive directory. Names
+// are relative paths that must stay inside it, so API callers cannot read or
+// write other files.
+func (s *Server) archivePath(name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("archive name must be relative to the archive directory")
+	}
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return "", fmt.Errorf("archive name must not contain \"..\"")
+		}
+	}
+	dir := s.ArchiveDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "migration-tool-export")
+	}
+	return filepath.Join(dir, filepath.Clean(name)), nil
+}
+
+// importOptions returns the import options configured on the server, with
+// exclude left out of the migration and inventories limited to the groups
+// in include.
+func (s *Server) importOptions(exclude, include map[string][]string) migration.ImportOptions {
+	return migration.ImportOptions{Exclude: exclude, IncludeGroups: include, Secrets: s.Secrets, Transforms: s.Transforms, Users: s.Users}
+}
+
+// includeGroups returns the group selection of a run: the one it sent, or
+// else the one its preview was made with.
+func (pc *previewCache) includeGroups(requested map[string][]string) map[string][]string {
+	if len(requested) > 0 {
+		return requested
+	}
+	return pc.IncludeGroups
+}
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/migration"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// emptyPlatform fakes an AWX instance with nothing to export that accepts
+// every POST.
+func emptyPlatform(t *testing.T) (*httptest.Server, *models.Connection) {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":1}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	return ts, &models.Connection{Type: "awx", Scheme: "http", Host: host, Port: port, Username: "admin", Password: "secret"}
+}
+
+func TestMigrateHandler_SingleJob(t *testing.T) {
+	srcTS, src := emptyPlatform(t)
+	defer srcTS.Close()
+	dstTS, dst := emptyPlatform(t)
+	defer dstTS.Close()
+	src.Name, dst.Name = "old", "new"
+
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore(), Previews: NewPreviewStore()}
+	s.Connections.Create(src)
+	s.Connections.Create(dst)
+	finished := make(chan models.JobResult, 1)
+	s.Jobs.OnFinish(func(r models.JobResult) { finished <- r })
+	r := chi.NewRouter()
+	r.Post("/api/migrate", s.MigrateHandler)
+
+	body := `{"source_id":"` + src.ID + `","destination_id":"` + dst.ID + `"}`
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/migrate", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		JobID string `json:"job_id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&resp)
+
+	var result models.JobResult
+	select {
+	case result = <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("migration job did not finish")
+	}
+	job := s.Jobs.Get(resp.JobID)
+	if result.ID != resp.JobID || result.Status != "completed" {
+		t.Fatalf("job %s finished as %q (%s), want %s completed; logs: %q", result.ID, result.Status, result.Error, resp.JobID, job.LogsSince(0))
+	}
+	if got := s.Jobs.List(); len(got) != 1 {
+		t.Fatalf("%d jobs created, want 1", len(got))
+	}
+	if job.Type != "migration-one-shot" {
+		t.Errorf("job type = %q, want migration-one-shot", job.Type)
+	}
+
+	// Export, preflight and import all happen in this one job, in order.
+	logs := job.LogsSince(0)
+	next := 0
+	for _, phase := range []string{"=== Exporting from source ===", "=== Checking destination ===", "=== Starting migration to new ==="} {
+		found := false
+		for ; next < len(logs); next++ {
+			if logs[next] == phase {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("phase %q missing or out of order in %q", phase, logs)
+		}
+	}
+}
+
+func TestMigrateHandler_CancelledDuringExport(t *testing.T) {
+	exporting := make(chan struct{}, 1)
+	srcTS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case exporting <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+	}))
+	defer srcTS.Close()
+	u, _ := url.Parse(srcTS.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	src := &models.Connection{Name: "old", Type: "awx", Scheme: "http", Host: host, Port: port, Username: "admin", Password: "secret"}
+	dstTS, dst := emptyPlatform(t)
+	defer dstTS.Close()
+	dst.Name = "new"
+
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore(), Previews: NewPreviewStore()}
+	s.Connections.Create(src)
+	s.Connections.Create(dst)
+	r := chi.NewRouter()
+	r.Post("/api/migrate", s.MigrateHandler)
+	r.Post("/api/jobs/{id}/cancel", s.CancelJob)
+
+	body := `{"source_id":"` + src.ID + `","destination_id":"` + dst.ID + `"}`
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/migrate", strings.NewReader(body)))
+	var resp struct {
+		JobID string `json:"job_id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	job := s.Jobs.Get(resp.JobID)
+
+	select {
+	case <-exporting:
+	case <-time.After(10 * time.Second):
+		t.Fatal("export did not start")
+	}
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/jobs/"+job.ID+"/cancel", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cancel status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if !s.Jobs.Wait(ctx) {
+		t.Fatal("migration goroutine did not return after cancel")
+	}
+	var cancelled []string
+	for _, l := range job.LogsSince(0) {
+		if strings.Contains(l, "stopped by user") {
+			cancelled = append(cancelled, l)
+		}
+	}
+	if len(cancelled) != 1 || cancelled[0] != "CANCELLED: migration-one-shot stopped by user" {
+		t.Errorf("cancellation lines = %q, want exactly one from CancelJob", cancelled)
+	}
+}
+
+func containsLog(logs []string, want string) bool {
+	for _, l := range logs {
+		if l == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMigrationPreviewHandler_RejectsUnknownConflictStrategy(t *testing.T) {
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore(), Previews: NewPreviewStore()}
+	r := chi.NewRouter()
+	r.Post("/api/migrate/preview", s.MigrationPreviewHandler)
+
+	body := `{"source_id":"a","destination_id":"b","conflicts":{"job_templates":"overwrite"}}`
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/migrate/preview", strings.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body)
+	}
+	if len(s.Jobs.List()) != 0 {
+		t.Error("a job was started for an invalid request")
+	}
+}
+
+func TestMigrationDryRunHandler_WritesNothing(t *testing.T) {
+	var writes []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":1}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	// Dry runs are allowed against read-only connections.
+	dst := &models.Connection{Name: "new", Type: "awx", Scheme: "http", Host: host, Port: port, Username: "audit", Password: "secret", Role: models.RoleAuditor}
+
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore(), Previews: NewPreviewStore()}
+	s.Connections.Create(dst)
+	s.Previews.Store("preview-1", &previewCache{
+		Preview:    &models.MigrationPreview{},
+		ExportData: &migration.ExportedData{Organizations: []models.Resource{{"id": float64(1), "name": "Engineering"}}},
+	})
+	finished := make(chan models.JobResult, 1)
+	s.Jobs.OnFinish(func(r models.JobResult) { finished <- r })
+	r := chi.NewRouter()
+	r.Post("/api/migrate/dry-run", s.MigrationDryRunHandler)
+
+	body := `{"destination_id":"` + dst.ID + `","preview_job_id":"preview-1"}`
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/migrate/dry-run", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body)
+	}
+
+	var result models.JobResult
+	select {
+	case result = <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("dry run job did not finish")
+	}
+	logs := s.Jobs.Get(result.ID).LogsSince(0)
+	if result.Status != "completed" {
+		t.Fatalf("job finished as %q (%s); logs: %q", result.Status, result.Error, logs)
+	}
+	if len(writes) != 0 {
+		t.Errorf("dry run sent %v", writes)
+	}
+	want := `  DRY RUN: POST /api/v2/organizations/ {"description":"","name":"Engineering"}`
+	found := false
+	for _, l := range logs {
+		found = found || l == want
+	}
+	if !found {
+		t.Errorf("log is missing %q: %q", want, logs)
+	}
+	if s.Previews.Get("preview-1") == nil {
+		t.Error("preview was dropped after the dry run")
+	}
+}
+
+func TestMigrationRunHandler_RejectsBusyDestination(t *testing.T) {
+	created := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			created <- struct{}{}
+			<-unblock
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":1}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	dst := &models.Connection{Name: "new", Type: "awx", Scheme: "http", Host: host, Port: port, Username: "admin", Password: "secret"}
+
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore(), Previews: NewPreviewStore()}
+	s.Connections.Create(dst)
+	for _, id := range []string{"preview-1", "preview-2", "preview-3"} {
+		s.Previews.Store(id, &previewCache{
+			Preview:    &models.MigrationPreview{},
+			ExportData: &migration.ExportedData{Organizations: []models.Resource{{"id": float64(1), "name": "Engineering"}}},
+		})
+	}
+	finished := make(chan models.JobResult, 1)
+	s.Jobs.OnFinish(func(r models.JobResult) { finished <- r })
+	r := chi.NewRouter()
+	r.Post("/api/migrate/run", s.MigrationRunHandler)
+	run := func(previewID string) *httptest.ResponseRecorder {
+		body := `{"destination_id":"` + dst.ID + `","preview_job_id":"` + previewID + `"}`
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/migrate/run", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := run("preview-1"); rec.Code != http.StatusAccepted {
+		t.Fatalf("first run: status = %d, want 202: %s", rec.Code, rec.Body)
+	}
+	select {
+	case <-created:
+	case <-time.After(10 * time.Second):
+		t.Fatal("first run sent no create")
+	}
+
+	rec := run("preview-2")
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("second run: status = %d, want 409: %s", rec.Code, rec.Body)
+	}
+	if got := len(s.Jobs.List()); got != 1 {
+		t.Errorf("%d jobs created, want 1", got)
+	}
+
+	close(unblock)
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("first run did not finish")
+	}
+	// The lock is released once the job's goroutine has returned.
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, busy := s.migrating.Load(dst.ID); !busy {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("destination still locked after the first run finished")
+		}
+	}
+	if rec := run("preview-3"); rec.Code != http.StatusAccepted {
+		t.Fatalf("run after the first finished: status = %d, want 202: %s", rec.Code, rec.Body)
+	}
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("third run did not finish")
+	}
+}
+
+func TestImportArchiveHandler_ConfinesPaths(t *testing.T) {
+	dstTS, dst := emptyPlatform(t)
+	defer dstTS.Close()
+	dir := t.TempDir()
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore(), ArchiveDir: dir}
+	s.Connections.Create(dst)
+	r := chi.NewRouter()
+	r.Post("/api/migrate/import-archive", s.ImportArchiveHandler)
+
+	for name, want := range map[string]int{
+		"/etc/passwd":              http.StatusBadRequest,
+		"../outside.tar.gz":        http.StatusBadRequest,
+		"sub/../../outside.tar.gz": http.StatusBadRequest,
+		"missing.tar.gz":           http.StatusNotFound,
+		"":                         http.StatusBadRequest,
+	} {
+		body := `{"destination_id":"` + dst.ID + `","name":"` + name + `"}`
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/migrate/import-archive", strings.NewReader(body)))
+		if rec.Code != want {
+			t.Errorf("name %q: status = %d, want %d: %s", name, rec.Code, want, rec.Body)
+		}
+		if strings.Contains(rec.Body.String(), dir) {
+			t.Errorf("name %q: response reveals the archive directory: %s", name, rec.Body)
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/migration"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
 )
 
@@ -17,21 +22,30 @@ func (s *Server) RunCleanup(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "connection not found")
 		return
 	}
+	if rejectReadOnly(w, conn) {
+		return
+	}
+
+	// ?dry_run=true lists what would be deleted without deleting anything;
+	// ?archive=true deactivates users and job templates instead of deleting them
+	opts := platform.CleanupOptions{
+		DryRun:  r.URL.Query().Get("dry_run") == "true",
+		Archive: r.URL.Query().Get("archive") == "true",
+	}
 
 	jobType := conn.Type + "-cleanup"
 	job := s.Jobs.Create(jobType, id)
 	p := platform.NewPlatform(conn)
 
-	go func() {
-		job.AppendLog(fmt.Sprintf("Cleaning up %s (%s)", conn.Name, conn.BaseURL()))
-		err := p.Cleanup(job.AppendLog)
-		if err != nil {
-			job.AppendLog("ERROR: " + err.Error())
-			job.Fail(err.Error())
+	s.Jobs.Go(func() {
+		if opts.DryRun {
+			job.AppendLog(fmt.Sprintf("Cleanup dry run on %s (%s) — nothing will be deleted", conn.Name, conn.BaseURL()))
 		} else {
-			job.Complete()
+			job.AppendLog(fmt.Sprintf("Cleaning up %s (%s)", conn.Name, conn.BaseURL()))
 		}
-	}()
+		err := p.Cleanup(job.Context(), opts, job.AppendLog)
+		finishJob(job, err)
+	})
 
 	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
 }
@@ -43,21 +57,19 @@ func (s *Server) RunPopulate(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "connection not found")
 		return
 	}
+	if rejectReadOnly(w, conn) {
+		return
+	}
 
 	jobType := conn.Type + "-populate"
 	job := s.Jobs.Create(jobType, id)
 	p := platform.NewPlatform(conn)
 
-	go func() {
+	s.Jobs.Go(func() {
 		job.AppendLog(fmt.Sprintf("Populating %s (%s)", conn.Name, conn.BaseURL()))
-		err := p.Populate(job.AppendLog)
-		if err != nil {
-			job.AppendLog("ERROR: " + err.Error())
-			job.Fail(err.Error())
-		} else {
-			job.Complete()
-		}
-	}()
+		err := p.Populate(job.Context(), job.AppendLog)
+		finishJob(job, err)
+	})
 
 	writeJSON(w, http.StatusAccepted, map[string]string{"job_id": job.ID})
 }
@@ -70,28 +82,84 @@ func (s *Server) RunExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Optional body: {"metadata": true} to write provenance sidecars, or
+	// {"format": "migration"} to stream a migration export into the archive
+	// directory, from where POST /api/migrate/import-archive can import it
+	var req struct {
+		platform.ExportOptions
+		Format string `json:"format"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.Format != "" && req.Format != "migration" {
+		writeError(w, http.StatusBadRequest, "unknown format: "+req.Format)
+		return
+	}
+	// ?format=yaml writes the files as YAML instead of JSON
+	switch req.ExportOptions.Format = r.URL.Query().Get("format"); req.ExportOptions.Format {
+	case "", platform.ExportJSON:
+	case platform.ExportYAML:
+		if req.Format == "migration" {
+			writeError(w, http.StatusBadRequest, "migration exports are written as JSON")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "unknown output format: "+req.ExportOptions.Format)
+		return
+	}
+
 	// Create export output dir
 	outputDir := filepath.Join(os.TempDir(), "migration-tool-export", id)
+	var name string // archive name of a migration export
+	if req.Format == "migration" {
+		name = fmt.Sprintf("%s-%s", id, time.Now().Format("20060102-150405"))
+		dir, err := s.archivePath(name)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		outputDir = dir
+	}
 	os.MkdirAll(outputDir, 0755)
 
 	jobType := conn.Type + "-export"
 	job := s.Jobs.Create(jobType, id)
 	p := platform.NewPlatform(conn)
 
-	go func() {
+	s.Jobs.Go(func() {
 		job.AppendLog(fmt.Sprintf("Exporting %s (%s)", conn.Name, conn.BaseURL()))
 		job.AppendLog("Exporting to: " + outputDir)
-		err := p.Export(outputDir, job.AppendLog)
-		if err != nil {
-			job.AppendLog("ERROR: " + err.Error())
-			job.Fail(err.Error())
+		var err error
+		if req.Format == "migration" {
+			err = migration.ExportStreaming(job.Context(), conn, outputDir, migration.ExportOptions{Concurrency: s.exportConcurrency(0)}, job.AppendLog)
 		} else {
-			job.Complete()
+			err = p.Export(job.Context(), outputDir, req.ExportOptions, job.AppendLog)
 		}
-	}()
+		finishJob(job, err)
+	})
 
-	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+	resp := map[string]interface{}{
 		"job_id":     job.ID,
 		"output_dir": outputDir,
-	})
+	}
+	if name != "" {
+		resp["name"] = name
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// finishJob records the outcome of a cancellable operation. A cancelled job
+// keeps its "cancelled" status rather than being marked failed or completed.
+func finishJob(job *models.Job, err error) {
+	switch {
+	case job.IsCancelled():
+		return
+	case err != nil:
+		job.AppendLog("ERROR: " + err.Error())
+		job.Fail(err.Error())
+	default:
+		job.Complete()
+	}
 }
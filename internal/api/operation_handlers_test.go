@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestAuditorConnection_ReadOnly(t *testing.T) {
+	ts, conn := emptyPlatform(t)
+	defer ts.Close()
+	conn.Name, conn.Role = "prod", models.RoleAuditor
+
+	s := &Server{Connections: models.NewConnectionStore(), Jobs: models.NewJobStore()}
+	s.Connections.Create(conn)
+	r := chi.NewRouter()
+	r.Post("/api/connections/{id}/cleanup", s.RunCleanup)
+	r.Post("/api/connections/{id}/populate", s.RunPopulate)
+	r.Get("/api/connections/{id}/resources/{type}", s.ListResourcesOfType)
+
+	for _, op := range []string{"cleanup", "populate"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("POST", "/api/connections/"+conn.ID+"/"+op, nil))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s status = %d, want 403", op, rec.Code)
+		}
+	}
+	if jobs := s.Jobs.List(); len(jobs) != 0 {
+		t.Errorf("%d jobs started for an auditor connection, want 0", len(jobs))
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/connections/"+conn.ID+"/resources/organizations", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("listing status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+}
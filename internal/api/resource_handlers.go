@@ -1,13 +1,18 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
 )
 
+// ListResourceTypes returns the browsable resource types of a connection.
+// With ?with_counts=true each type also carries the number of objects of it,
+// read from the first page of its list.
 func (s *Server) ListResourceTypes(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	conn := s.Connections.Get(id)
@@ -16,7 +21,12 @@ func (s *Server) ListResourceTypes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	p := platform.NewPlatform(conn)
-	writeJSON(w, http.StatusOK, p.GetResourceTypes())
+	if r.URL.Query().Get("with_counts") != "true" {
+		writeJSON(w, http.StatusOK, p.GetResourceTypes())
+		return
+	}
+	client := platform.NewClient(conn).WithCache(s.ListCache, id)
+	writeJSON(w, http.StatusOK, platform.CountResources(client, p.GetResourceTypes()))
 }
 
 func (s *Server) ListResourcesOfType(w http.ResponseWriter, r *http.Request) {
@@ -27,8 +37,25 @@ func (s *Server) ListResourcesOfType(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "connection not found")
 		return
 	}
-	p := platform.NewPlatform(conn)
-	resources, err := p.ListResources(resourceType)
+	q := r.URL.Query()
+	filter := platform.ListFilter{
+		Search: q.Get("search"),
+		Name:   q.Get("name"),
+	}
+	if v := q.Get("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "page_size must be a positive integer")
+			return
+		}
+		filter.PageSize = n
+	}
+	// ?refresh=true drops the connection's cached pages before listing
+	if q.Get("refresh") == "true" {
+		s.ListCache.Invalidate(id)
+	}
+	p := platform.NewCachedPlatform(conn, s.ListCache)
+	resources, err := p.ListResourcesFiltered(resourceType, filter)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -39,3 +66,71 @@ func (s *Server) ListResourcesOfType(w http.ResponseWriter, r *http.Request) {
 	}
 	writeJSON(w, http.StatusOK, resources)
 }
+
+// GetResource returns one object's full detail, e.g. a job template with its
+// prompts, as the API returns it.
+func (s *Server) GetResource(w http.ResponseWriter, r *http.Request) {
+	_, p, resourceType, objID, ok := s.resolveObject(w, r)
+	if !ok {
+		return
+	}
+	obj, err := p.GetResource(resourceType, objID)
+	switch {
+	case errors.Is(err, platform.ErrNotFound):
+		writeError(w, http.StatusNotFound, resourceType+" "+strconv.Itoa(objID)+" not found")
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, obj)
+	}
+}
+
+// DeleteResource deletes one object. Managed objects and the defaults that
+// Cleanup keeps are refused with 403.
+func (s *Server) DeleteResource(w http.ResponseWriter, r *http.Request) {
+	conn, p, resourceType, objID, ok := s.resolveObject(w, r)
+	if !ok {
+		return
+	}
+	if rejectReadOnly(w, conn) {
+		return
+	}
+	err := p.DeleteResource(resourceType, objID)
+	switch {
+	case errors.Is(err, platform.ErrNotFound):
+		writeError(w, http.StatusNotFound, resourceType+" "+strconv.Itoa(objID)+" not found")
+	case errors.Is(err, platform.ErrProtected):
+		writeError(w, http.StatusForbidden, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		s.ListCache.Invalidate(conn.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// resolveObject reads the connection, resource type and object ID of a
+// single-object request, writing an error and returning false when one of
+// them is invalid.
+func (s *Server) resolveObject(w http.ResponseWriter, r *http.Request) (*models.Connection, platform.Platform, string, int, bool) {
+	id := chi.URLParam(r, "id")
+	resourceType := chi.URLParam(r, "type")
+	objID, err := strconv.Atoi(chi.URLParam(r, "objId"))
+	if err != nil || objID < 1 {
+		writeError(w, http.StatusBadRequest, "object ID must be a positive integer")
+		return nil, nil, "", 0, false
+	}
+	conn := s.Connections.Get(id)
+	if conn == nil {
+		writeError(w, http.StatusNotFound, "connection not found")
+		return nil, nil, "", 0, false
+	}
+	p := platform.NewPlatform(conn)
+	for _, rt := range p.GetResourceTypes() {
+		if rt.Name == resourceType {
+			return conn, p, resourceType, objID, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "unknown resource type: "+resourceType)
+	return nil, nil, "", 0, false
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestListResourceTypes_WithCounts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":7,"next":"` + r.URL.Path + `?page=2&page_size=1","results":[{"id":1}]}`))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	conn := &models.Connection{Name: "awx", Type: "awx", Scheme: "http", Host: host, Port: port, Username: "admin", Password: "secret"}
+
+	s := &Server{Connections: models.NewConnectionStore()}
+	s.Connections.Create(conn)
+	r := chi.NewRouter()
+	r.Get("/api/connections/{id}/resources", s.ListResourceTypes)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/connections/"+conn.ID+"/resources?with_counts=true", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var types []struct {
+		Name    string `json:"name"`
+		APIPath string `json:"api_path"`
+		Count   *int   `json:"count"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&types); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(types) == 0 {
+		t.Fatal("no resource types returned")
+	}
+	for _, rt := range types {
+		if rt.Name == "" || rt.APIPath == "" || rt.Count == nil || *rt.Count != 7 {
+			t.Errorf("type %+v, want a name, api_path and count 7", rt)
+		}
+	}
+
+	// Without the flag the plain types are returned.
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/connections/"+conn.ID+"/resources", nil))
+	var plain []map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&plain)
+	if len(plain) != len(types) || plain[0]["count"] != nil {
+		t.Errorf("plain listing = %v, want %d types without counts", plain, len(types))
+	}
+}
+
+func TestGetResource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/job_templates/7/" {
+			w.Write([]byte(`{"id":7,"name":"Deploy","ask_limit_on_launch":true,"ask_variables_on_launch":false}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail":"Not found."}`))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	conn := &models.Connection{Name: "awx", Type: "awx", Scheme: "http", Host: host, Port: port, Username: "admin", Password: "secret"}
+
+	s := &Server{Connections: models.NewConnectionStore()}
+	s.Connections.Create(conn)
+	r := chi.NewRouter()
+	r.Get("/api/connections/{id}/resources/{type}/{objId}", s.GetResource)
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/connections/"+conn.ID+"/resources/"+path, nil))
+		return rec
+	}
+
+	rec := get("job_templates/7")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var jt map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&jt)
+	if jt["name"] != "Deploy" || jt["ask_limit_on_launch"] != true {
+		t.Errorf("job template = %v", jt)
+	}
+
+	for path, want := range map[string]int{
+		"job_templates/8":   http.StatusNotFound,
+		"no_such_type/7":    http.StatusNotFound,
+		"job_templates/abc": http.StatusBadRequest,
+		"job_templates/-1":  http.StatusBadRequest,
+	} {
+		if rec := get(path); rec.Code != want {
+			t.Errorf("GET %s: status = %d, want %d: %s", path, rec.Code, want, rec.Body)
+		}
+	}
+}
+
+func TestDeleteResource(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "DELETE":
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v2/job_templates/7/":
+			w.Write([]byte(`{"id":7,"name":"Deploy"}`))
+		case r.URL.Path == "/api/v2/organizations/1/":
+			w.Write([]byte(`{"id":1,"name":"Default"}`))
+		case r.URL.Path == "/api/v2/credential_types/1/":
+			w.Write([]byte(`{"id":1,"name":"Machine","managed":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"Not found."}`))
+		}
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	conn := &models.Connection{Name: "awx", Type: "awx", Scheme: "http", Host: host, Port: port, Username: "admin", Password: "secret"}
+
+	s := &Server{Connections: models.NewConnectionStore()}
+	s.Connections.Create(conn)
+	r := chi.NewRouter()
+	r.Delete("/api/connections/{id}/resources/{type}/{objId}", s.DeleteResource)
+
+	for path, want := range map[string]int{
+		"job_templates/7":    http.StatusNoContent,
+		"organizations/1":    http.StatusForbidden,
+		"credential_types/1": http.StatusForbidden,
+		"job_templates/8":    http.StatusNotFound,
+		"no_such_type/7":     http.StatusNotFound,
+		"job_templates/abc":  http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/connections/"+conn.ID+"/resources/"+path, nil))
+		if rec.Code != want {
+			t.Errorf("DELETE %s: status = %d, want %d: %s", path, rec.Code, want, rec.Body)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deleted) != 1 || deleted[0] != "/api/v2/job_templates/7/" {
+		t.Errorf("deleted = %v, want only job template 7", deleted)
+	}
+}
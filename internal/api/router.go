@@ -3,17 +3,39 @@ package api
 import (
 	"io/fs"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rflorenc/ansible-automation-workbench/internal/migration"
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
 )
 
 // Server holds shared state for all API handlers.
 type Server struct {
-	Connections *models.ConnectionStore
-	Jobs        *models.JobStore
-	Previews    *PreviewStore
+	Connections  *models.ConnectionStore
+	Jobs         *models.JobStore
+	Previews     *PreviewStore
+	Secrets      migration.CredentialSecrets // credential inputs applied during migration runs
+	Transforms   *migration.Transforms       // field rewrites applied during migration runs
+	Users        *migration.UserOptions      // password and privileges given to migrated users
+	ListCache    *platform.ResponseCache     // ETag cache for resource browsing; nil disables
+	Settings     *models.SettingsStore       // runtime defaults changed through /api/settings
+	ArchiveDir   string                      // where migration archives are written and read; "" uses the temp dir
+	NameTemplate string                      // names imported connections without one; "" uses config.DefaultNameTemplate
+
+	// Metrics serves /metrics when set; nil leaves the endpoint out.
+	Metrics http.Handler
+
+	// SaveSettings writes settings to the config file for
+	// PUT /api/settings?persist=true; nil when there is no config file.
+	SaveSettings func(models.Settings) error
+
+	ready     atomic.Bool // set by MarkReady once startup has finished
+	migrating sync.Map    // destination connection ID → ID of the migration job writing to it
+	diffs     sync.Map    // connection diff job ID → its *models.ConnectionDiff
 }
 
 // NewRouter builds the chi router with all API routes and static file serving.
@@ -30,13 +52,22 @@ func NewRouter(s *Server, webFS fs.FS) http.Handler {
 		// Connections
 		r.Post("/connections", s.CreateConnection)
 		r.Get("/connections", s.ListConnections)
+		r.Get("/connections/export", s.ExportConnections)
+		r.Post("/connections/import", s.ImportConnections)
+		r.Get("/connections/{id}", s.GetConnection)
+		r.Delete("/connections", s.BulkDeleteConnections)
 		r.Put("/connections/{id}", s.UpdateConnection)
 		r.Delete("/connections/{id}", s.DeleteConnection)
+		r.Post("/connections/test-all", s.TestAllConnections)
 		r.Post("/connections/{id}/test", s.TestConnection)
+		r.Post("/connections/{id}/version", s.RefreshConnectionVersion)
 
 		// Resource browsing
 		r.Get("/connections/{id}/resources", s.ListResourceTypes)
 		r.Get("/connections/{id}/resources/{type}", s.ListResourcesOfType)
+		r.Get("/connections/{id}/resources/{type}/{objId}", s.GetResource)
+		r.Delete("/connections/{id}/resources/{type}/{objId}", s.DeleteResource)
+		r.Post("/connections/{id}/diff", s.ConnectionDiffHandler)
 
 		// Operations (async)
 		r.Post("/connections/{id}/cleanup", s.RunCleanup)
@@ -44,19 +75,44 @@ func NewRouter(s *Server, webFS fs.FS) http.Handler {
 		r.Post("/connections/{id}/export", s.RunExport)
 
 		// Migration
+		r.Post("/migrate", s.MigrateHandler)
+		r.Post("/migrate/validate", s.MigrationValidateHandler)
 		r.Post("/migrate/preview", s.MigrationPreviewHandler)
 		r.Get("/migrate/preview/{jobId}", s.GetMigrationPreview)
+		r.Get("/migrate/preview/{jobId}/export", s.ExportPreviewBundle)
 		r.Post("/migrate/run", s.MigrationRunHandler)
+		r.Post("/migrate/dry-run", s.MigrationDryRunHandler)
+		r.Post("/migrate/resume", s.MigrationResumeHandler)
+		r.Post("/migrate/export-archive", s.ExportArchiveHandler)
+		r.Post("/migrate/import-archive", s.ImportArchiveHandler)
+
+		// Diff
+		r.Get("/diff/resource", s.ResourceDiffHandler)
+		r.Get("/diff/connections/{jobId}", s.GetConnectionDiff)
 
 		// Exclusions
 		r.Get("/exclusions", s.GetExclusions)
 
 		// Jobs
 		r.Get("/jobs", s.ListJobs)
+		r.Post("/jobs/prune", s.PruneJobs)
 		r.Get("/jobs/{id}", s.GetJob)
+		r.Delete("/jobs/{id}", s.DeleteJob)
+		r.Get("/jobs/{id}/logs", s.GetJobLogs)
 		r.Post("/jobs/{id}/cancel", s.CancelJob)
+
+		// Settings
+		r.Get("/settings", s.GetSettings)
+		r.Put("/settings", s.UpdateSettings)
 	})
 
+	// Probes for the workbench itself
+	r.Get("/healthz", s.Healthz)
+	r.Get("/readyz", s.Readyz)
+	if s.Metrics != nil {
+		r.Get("/metrics", s.Metrics.ServeHTTP)
+	}
+
 	// WebSocket (outside /api to avoid JSON content-type assumptions)
 	r.Get("/ws/jobs/{id}/logs", s.StreamJobLogs)
 
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// DefaultShutdownGrace bounds how long Serve waits for in-flight requests
+// and cancelled jobs when shutting down.
+const DefaultShutdownGrace = 5 * time.Second
+
+// Serve serves handler on ln until ctx is done. It then stops accepting
+// connections, waits up to grace for in-flight requests, cancels every
+// running job and waits for the job goroutines started with JobStore.Go to
+// return, for the rest of grace at most, before flushing the job store to
+// disk.
+func Serve(ctx context.Context, ln net.Listener, handler http.Handler, jobs *models.JobStore, grace time.Duration) error {
+	srv := &http.Server{Handler: handler}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("Shutdown: in-flight requests still running after %s", grace)
+		err = nil
+	}
+	if n := jobs.CancelRunning(); n > 0 {
+		log.Printf("Shutdown: cancelled %d running job(s)", n)
+	}
+	if !jobs.Wait(shutdownCtx) {
+		log.Printf("Shutdown: job goroutines still running after %s", grace)
+	}
+	jobs.Flush()
+	if serveErr := <-errc; !errors.Is(serveErr, http.ErrServerClosed) && err == nil {
+		err = serveErr
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestServe_ShutdownCancelsJobs(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+
+	jobs := models.NewJobStore()
+	jobs.SetStructuredLogs(true)
+	job := jobs.Create("awx-populate", "conn-1")
+	exited := make(chan struct{})
+	jobs.Go(func() {
+		defer close(exited)
+		<-job.Context().Done()
+		job.AppendLog("Operation cancelled")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	const grace = 5 * time.Second
+	go func() { done <- Serve(ctx, ln, handler, jobs, grace) }()
+
+	resp, err := http.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatalf("GET before shutdown: %v", err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	// The job goroutine returns as soon as it is cancelled, so Serve must not
+	// sit out the grace period before flushing.
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Serve returned error: %v", err)
+		}
+	case <-time.After(grace / 2):
+		t.Fatal("Serve did not return once the job goroutine had exited")
+	}
+
+	if conn, err := net.Dial("tcp", addr); err == nil {
+		conn.Close()
+		t.Error("listener still accepts connections after shutdown")
+	}
+	if job.Status != "cancelled" {
+		t.Errorf("job status = %q, want cancelled", job.Status)
+	}
+	select {
+	case <-exited:
+	default:
+		t.Error("Serve returned before the job goroutine had exited")
+	}
+	if logs := job.LogsSince(0); len(logs) != 2 || logs[0] != "CANCELLED: workbench is shutting down" || logs[1] != "Operation cancelled" {
+		t.Errorf("job logs = %q", logs)
+	}
+	if entries := job.LogEntries(); len(entries) == 0 || entries[0].Level != "warn" {
+		t.Errorf("shutdown line entries = %+v, want a warn entry", entries)
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetSettings returns the current runtime settings.
+func (s *Server) GetSettings(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Settings.Get())
+}
+
+// UpdateSettings changes the runtime settings named in the body, keeping the
+// others. With ?persist=true they are also written to the config file.
+func (s *Server) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if s.Settings == nil {
+		writeError(w, http.StatusServiceUnavailable, "settings are not available")
+		return
+	}
+	persist := r.URL.Query().Get("persist") == "true"
+	if persist && s.SaveSettings == nil {
+		writeError(w, http.StatusBadRequest, "no config file to persist settings to")
+		return
+	}
+
+	settings := s.Settings.Get()
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if err := s.Settings.Update(settings); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if persist {
+		if err := s.SaveSettings(settings); err != nil {
+			writeError(w, http.StatusInternalServerError, "saving settings: "+err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, s.Settings.Get())
+}
+
+// exportConcurrency returns requested, or the configured export concurrency
+// when the request does not set one.
+func (s *Server) exportConcurrency(requested int) int {
+	if requested > 0 {
+		return requested
+	}
+	return s.Settings.Get().ExportConcurrency
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestSettingsHandlers(t *testing.T) {
+	var saved *models.Settings
+	s := &Server{
+		Settings:     models.NewSettingsStore(models.Settings{MaxRetries: 3}),
+		SaveSettings: func(st models.Settings) error { saved = &st; return nil },
+	}
+	r := chi.NewRouter()
+	r.Get("/api/settings", s.GetSettings)
+	r.Put("/api/settings", s.UpdateSettings)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/settings", nil))
+	var got models.Settings
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding settings: %v", err)
+	}
+	if got.MaxRetries != 3 || got.ExportConcurrency != 0 {
+		t.Errorf("defaults = %+v, want max_retries 3 and export_concurrency 0", got)
+	}
+	if n := s.exportConcurrency(0); n != 0 {
+		t.Errorf("exportConcurrency(0) before update = %d, want 0 (built-in default)", n)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("PUT", "/api/settings?persist=true", strings.NewReader(`{"export_concurrency": 8}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if n := s.exportConcurrency(0); n != 8 {
+		t.Errorf("exportConcurrency(0) after update = %d, want 8", n)
+	}
+	if n := s.exportConcurrency(2); n != 2 {
+		t.Errorf("exportConcurrency(2) = %d, want the requested 2", n)
+	}
+	if got := s.Settings.Get().MaxRetries; got != 3 {
+		t.Errorf("max_retries = %d, want 3 kept by a partial update", got)
+	}
+	if saved == nil || saved.ExportConcurrency != 8 {
+		t.Errorf("saved settings = %+v, want export_concurrency 8 persisted", saved)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("PUT", "/api/settings", strings.NewReader(`{"max_retries": -5}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid PUT status = %d, want 400", rec.Code)
+	}
+}
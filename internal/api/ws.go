@@ -12,7 +12,18 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
-// StreamJobLogs streams job log lines over WebSocket.
+// Log streams are kept alive through idle proxies with pings. A client that
+// has not answered within wsPongWait is considered gone. Variables so tests
+// can shorten them.
+var (
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsWriteWait    = 10 * time.Second
+)
+
+// StreamJobLogs streams job log lines over WebSocket. Pings are sent every
+// wsPingInterval, so proxies do not time out idle streams, and the stream
+// ends when the client stops answering them.
 func (s *Server) StreamJobLogs(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	job := s.Jobs.Get(id)
@@ -27,24 +38,50 @@ func (s *Server) StreamJobLogs(w http.ResponseWriter, r *http.Request) {
 	}
 	defer conn.Close()
 
+	// Read in the background so pongs and close frames are processed; the
+	// read fails once the deadline passes without a pong.
+	pongWait := wsPongWait
+	gone := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
 	offset := 0
 	ticker := time.NewTicker(200 * time.Millisecond)
 	defer ticker.Stop()
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
 
 	for {
 		select {
+		case <-gone:
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
 		case <-ticker.C:
-			lines := job.LogsSince(offset)
+			lines, next := job.LogsFrom(offset)
 			for _, line := range lines {
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
 				if err := conn.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
 					return
 				}
-				offset++
 			}
+			offset = next
 			// If job is done and we've sent everything, close
 			if (job.Status == "completed" || job.Status == "failed" || job.Status == "cancelled") && len(lines) == 0 {
-				conn.WriteMessage(websocket.CloseMessage,
-					websocket.FormatCloseMessage(websocket.CloseNormalClosure, job.Status))
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, job.Status), time.Now().Add(wsWriteWait))
 				return
 			}
 		}
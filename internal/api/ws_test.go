@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// streamServer serves StreamJobLogs and closes the returned channel when a
+// stream handler returns.
+func streamServer(t *testing.T, s *Server) (*httptest.Server, chan struct{}) {
+	t.Helper()
+	ended := make(chan struct{})
+	r := chi.NewRouter()
+	r.Get("/ws/jobs/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		s.StreamJobLogs(w, r)
+		close(ended)
+	})
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts, ended
+}
+
+func shortenWSTimeouts(t *testing.T) {
+	interval, pongWait := wsPingInterval, wsPongWait
+	wsPingInterval, wsPongWait = 20*time.Millisecond, 100*time.Millisecond
+	t.Cleanup(func() { wsPingInterval, wsPongWait = interval, pongWait })
+}
+
+func TestStreamJobLogs_PingsIdleClients(t *testing.T) {
+	shortenWSTimeouts(t)
+	s := &Server{Jobs: models.NewJobStore()}
+	job := s.Jobs.Create("cleanup", "conn")
+	ts, ended := streamServer(t, s)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws/jobs/"+job.ID+"/logs", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	pings := make(chan struct{}, 10)
+	conn.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// A client that answers pings keeps the stream open well past the pong wait.
+	for i := 0; i < 10; i++ {
+		select {
+		case <-pings:
+		case <-time.After(time.Second):
+			t.Fatal("no ping received")
+		}
+	}
+	select {
+	case <-ended:
+		t.Fatal("stream ended while the client was answering pings")
+	default:
+	}
+
+	conn.Close()
+	select {
+	case <-ended:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not end after the client disconnected")
+	}
+}
+
+func TestStreamJobLogs_EndsWhenClientStopsReading(t *testing.T) {
+	shortenWSTimeouts(t)
+	s := &Server{Jobs: models.NewJobStore()}
+	job := s.Jobs.Create("cleanup", "conn")
+	ts, ended := streamServer(t, s)
+
+	// The client never reads, so it never answers a ping.
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws/jobs/"+job.ID+"/logs", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case <-ended:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not end after the client stopped answering pings")
+	}
+}
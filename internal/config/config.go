@@ -4,29 +4,97 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
 // ConnectionConfig represents a pre-configured connection in the config file.
 type ConnectionConfig struct {
-	Name     string `yaml:"name"`
-	Type     string `yaml:"type"`
-	Role     string `yaml:"role"` // "source" or "destination"
-	Scheme   string `yaml:"scheme"`
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
-	Insecure bool   `yaml:"insecure"`
-	CACert   string `yaml:"ca_cert"`
+	Name              string        `yaml:"name"`
+	Type              string        `yaml:"type"`
+	Role              string        `yaml:"role,omitempty"` // "source", "destination" or "auditor"
+	Scheme            string        `yaml:"scheme,omitempty"`
+	Host              string        `yaml:"host"`
+	Port              int           `yaml:"port,omitempty"`
+	Username          string        `yaml:"username,omitempty"` // username, password and token may reference ${VAR}
+	Password          string        `yaml:"password,omitempty"`
+	Token             string        `yaml:"token,omitempty"` // OAuth2 token, preferred over username/password
+	Insecure          bool          `yaml:"insecure,omitempty"`
+	CACert            string        `yaml:"ca_cert,omitempty"`
+	CACertFile        string        `yaml:"ca_cert_file,omitempty"`       // path to a PEM CA bundle, used when ca_cert is empty
+	ClientCert        string        `yaml:"client_cert,omitempty"`        // PEM client certificate for mutual TLS, or a path to one
+	ClientKey         string        `yaml:"client_key,omitempty"`         // PEM private key of client_cert, or a path to one
+	Timeout           time.Duration `yaml:"timeout,omitempty"`            // per-request HTTP timeout, e.g. "30s"
+	MaxRetries        int           `yaml:"max_retries,omitempty"`        // retries for transient errors; 0 = default (3), -1 disables
+	RateLimit         float64       `yaml:"rate_limit,omitempty"`         // max requests per second; 0 = unlimited
+	PageConcurrency   int           `yaml:"page_concurrency,omitempty"`   // list pages fetched in parallel; 0 or 1 = serial
+	ImportConcurrency int           `yaml:"import_concurrency,omitempty"` // hosts created in parallel per inventory when migrating to this connection
 }
 
+// TransformConfig is a find/replace rule applied to one field of migrated
+// resources of one type, e.g. to rewrite scm_url to an internal mirror.
+type TransformConfig struct {
+	Type    string `yaml:"type"`    // resource type, e.g. "projects"
+	Field   string `yaml:"field"`   // e.g. "name", "scm_url", "description"
+	Find    string `yaml:"find"`    // regular expression
+	Replace string `yaml:"replace"` // replacement, may reference groups as $1
+}
+
+// NameAffixConfig adds a prefix and/or suffix to the names of migrated
+// resources of one type.
+type NameAffixConfig struct {
+	Type   string `yaml:"type"`   // resource type, e.g. "organizations"
+	Prefix string `yaml:"prefix"` // e.g. "DEV-"
+	Suffix string `yaml:"suffix"`
+}
+
+// UserDefaultsConfig sets how migrated users are created on the destination.
+type UserDefaultsConfig struct {
+	Password          string `yaml:"password"`           // placeholder password, default "changeme!"
+	SystemAuditor     bool   `yaml:"system_auditor"`     // make migrated users system auditors
+	PreserveSuperuser bool   `yaml:"preserve_superuser"` // keep is_superuser from the source
+}
+
+// DefaultNameTemplate names auto-loaded connections that have no explicit name.
+const DefaultNameTemplate = "{type}-{host}"
+
 // Config holds all configuration (CLI flags + config file).
 type Config struct {
-	Listen      string             `yaml:"listen"`
-	Dev         bool               `yaml:"-"`
-	Connections []ConnectionConfig `yaml:"connections"`
+	Listen         string             `yaml:"listen"`
+	Dev            bool               `yaml:"-"`
+	DebugHTTP      bool               `yaml:"debug_http"`      // log every request to the platforms, with secrets masked
+	Metrics        bool               `yaml:"metrics"`         // serve Prometheus metrics of platform requests on /metrics
+	NameTemplate   string             `yaml:"name_template"`   // e.g. "{type}-{host}", used when a connection has no name
+	DataDir        string             `yaml:"data_dir"`        // directory for persisted jobs; empty keeps jobs in memory only
+	ArchiveDir     string             `yaml:"archive_dir"`     // where migration archives are kept; default <data_dir>/archives or the temp dir
+	HealthInterval time.Duration      `yaml:"health_interval"` // how often connections are re-checked; 0 = default (60s)
+	ListCacheTTL   time.Duration      `yaml:"list_cache_ttl"`  // how long browsed lists are kept for ETag revalidation; 0 disables
+	WebhookURL     string             `yaml:"webhook_url"`     // receives a JSON POST when a job completes or fails
+	SecretKeys     []string           `yaml:"secret_keys"`     // keys masked in job logs, in addition to password, token, secret
+	MaxRetries     int                `yaml:"max_retries"`     // retries for connections that set none; 0 = default (3), -1 disables
+	MaxLogLines    int                `yaml:"max_log_lines"`   // lines kept per job log; 0 = default (50000), -1 keeps all
+	StructuredLogs bool               `yaml:"structured_logs"` // record job log entries by level, phase and resource
+	Connections    []ConnectionConfig `yaml:"connections"`
+
+	// ExportConcurrency and ImportConcurrency apply to migrations and
+	// connections that do not set their own; 0 uses the built-in defaults.
+	ExportConcurrency int `yaml:"export_concurrency"`
+	ImportConcurrency int `yaml:"import_concurrency"`
+
+	// CredentialSecrets fills credential inputs during migration:
+	// credential name → input key → value (${VAR} reads the environment).
+	CredentialSecrets map[string]map[string]string `yaml:"credential_secrets"`
+
+	// Transforms rewrite resource fields during migration, in order.
+	Transforms []TransformConfig `yaml:"transforms"`
+
+	// NameAffixes rename migrated resources, after Transforms are applied.
+	NameAffixes []NameAffixConfig `yaml:"name_affixes"`
+
+	// UserDefaults applies to users created by migrations.
+	UserDefaults UserDefaultsConfig `yaml:"user_defaults"`
 
 	// internal: path to config file (from CLI flag)
 	configFile string
@@ -39,6 +107,9 @@ func Parse() *Config {
 	flag.StringVar(&c.configFile, "config", "", "Path to config file (YAML)")
 	flag.StringVar(&c.Listen, "listen", "", "HTTP listen address")
 	flag.BoolVar(&c.Dev, "dev", false, "Dev mode (proxy frontend to Vite dev server)")
+	flag.StringVar(&c.DataDir, "data-dir", "", "Directory to persist job history (default: in-memory only)")
+	flag.BoolVar(&c.DebugHTTP, "debug-http", false, "Log HTTP requests and responses sent to platforms")
+	flag.BoolVar(&c.Metrics, "metrics", false, "Serve Prometheus metrics of platform requests on /metrics")
 	flag.Parse()
 
 	// Load config file if specified
@@ -53,6 +124,9 @@ func Parse() *Config {
 	if c.Listen == "" {
 		c.Listen = ":8080"
 	}
+	if c.NameTemplate == "" {
+		c.NameTemplate = DefaultNameTemplate
+	}
 
 	return c
 }
@@ -74,9 +148,96 @@ func (c *Config) loadFile(path string) error {
 	if c.Listen == "" && file.Listen != "" {
 		c.Listen = file.Listen
 	}
+	if c.DataDir == "" && file.DataDir != "" {
+		c.DataDir = file.DataDir
+	}
+	if !c.DebugHTTP {
+		c.DebugHTTP = file.DebugHTTP
+	}
+	if !c.Metrics {
+		c.Metrics = file.Metrics
+	}
 
 	// Connections always come from config file
+	c.NameTemplate = file.NameTemplate
 	c.Connections = file.Connections
+	for i := range c.Connections {
+		cc := &c.Connections[i]
+		cc.Username = models.ExpandEnv(cc.Username)
+		cc.Password = models.ExpandEnv(cc.Password)
+		cc.Token = models.ExpandEnv(cc.Token)
+	}
+	c.CredentialSecrets = file.CredentialSecrets
+	c.Transforms = file.Transforms
+	c.NameAffixes = file.NameAffixes
+	c.UserDefaults = file.UserDefaults
+	c.UserDefaults.Password = models.ExpandEnv(c.UserDefaults.Password)
+	c.ArchiveDir = file.ArchiveDir
+	c.HealthInterval = file.HealthInterval
+	c.ListCacheTTL = file.ListCacheTTL
+	c.WebhookURL = file.WebhookURL
+	c.SecretKeys = file.SecretKeys
+	c.MaxRetries = file.MaxRetries
+	c.MaxLogLines = file.MaxLogLines
+	c.StructuredLogs = file.StructuredLogs
+	c.ExportConcurrency = file.ExportConcurrency
+	c.ImportConcurrency = file.ImportConcurrency
 
 	return nil
 }
+
+// Path returns the config file given with --config, or "" if there is none.
+func (c *Config) Path() string {
+	return c.configFile
+}
+
+// Save writes the top-level keys of values, a struct with yaml tags, into
+// the config file, replacing existing keys and keeping everything else,
+// including comments, as it was.
+func (c *Config) Save(values interface{}) error {
+	if c.configFile == "" {
+		return fmt.Errorf("no config file given")
+	}
+	data, err := os.ReadFile(c.configFile)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", c.configFile, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s: %w", c.configFile, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: top level is not a mapping", c.configFile)
+	}
+
+	var update yaml.Node
+	if err := update.Encode(values); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(update.Content); i += 2 {
+		setKey(root, update.Content[i], update.Content[i+1])
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.configFile, out, 0o600)
+}
+
+// setKey replaces the value of key in mapping, or appends key if missing.
+func setKey(mapping, key, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key.Value {
+			value.HeadComment = mapping.Content[i+1].HeadComment
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, key, value)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFile_ExpandsEnv(t *testing.T) {
+	t.Setenv("WORKBENCH_TEST_PASSWORD", "s3cret")
+	t.Setenv("WORKBENCH_TEST_TOKEN", "abc")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`connections:
+  - name: AWX
+    type: awx
+    host: awx.lab.local
+    username: admin
+    password: ${WORKBENCH_TEST_PASSWORD}
+  - name: AAP
+    type: aap
+    host: aap.lab.local
+    token: tok-${WORKBENCH_TEST_TOKEN}
+    password: pa$$word${WORKBENCH_TEST_UNSET}
+`), 0600)
+
+	var c Config
+	if err := c.loadFile(path); err != nil {
+		t.Fatalf("loadFile returned error: %v", err)
+	}
+	if got := c.Connections[0].Password; got != "s3cret" {
+		t.Errorf("password = %q, want s3cret", got)
+	}
+	if got := c.Connections[0].Username; got != "admin" {
+		t.Errorf("username = %q, want admin", got)
+	}
+	if got := c.Connections[1].Token; got != "tok-abc" {
+		t.Errorf("token = %q, want tok-abc", got)
+	}
+	// Bare $ is kept; unset variables expand to nothing.
+	if got := c.Connections[1].Password; got != "pa$$word" {
+		t.Errorf("password = %q, want pa$$word", got)
+	}
+}
+
+func TestSave_KeepsOtherKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`listen: ":9090"
+# retries for every connection
+max_retries: 3
+connections:
+  - name: AWX
+    host: awx.lab.local
+`), 0600)
+
+	c := Config{configFile: path}
+	err := c.Save(struct {
+		MaxRetries        int `yaml:"max_retries"`
+		ExportConcurrency int `yaml:"export_concurrency"`
+	}{5, 8})
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	var got Config
+	if err := got.loadFile(path); err != nil {
+		t.Fatalf("loadFile returned error: %v", err)
+	}
+	if got.Listen != ":9090" || len(got.Connections) != 1 {
+		t.Errorf("listen/connections = %q/%d, want kept", got.Listen, len(got.Connections))
+	}
+	if got.MaxRetries != 5 || got.ExportConcurrency != 8 {
+		t.Errorf("max_retries/export_concurrency = %d/%d, want 5/8", got.MaxRetries, got.ExportConcurrency)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# retries for every connection") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+}
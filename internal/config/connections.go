@@ -0,0 +1,103 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// ToConnection converts a config file entry into a connection. A blank role
+// defaults from the type (awx is a source, aap a destination), a blank scheme
+// from the type and a zero port from the scheme. The name is left as given;
+// see ExpandNameTemplate.
+func (cc ConnectionConfig) ToConnection() *models.Connection {
+	conn := &models.Connection{
+		Name:              cc.Name,
+		Type:              cc.Type,
+		Role:              cc.Role,
+		Scheme:            cc.Scheme,
+		Host:              cc.Host,
+		Port:              cc.Port,
+		Username:          cc.Username,
+		Password:          cc.Password,
+		Token:             cc.Token,
+		Insecure:          cc.Insecure,
+		CACert:            cc.CACert,
+		CACertFile:        cc.CACertFile,
+		ClientCert:        cc.ClientCert,
+		ClientKey:         cc.ClientKey,
+		Timeout:           cc.Timeout,
+		MaxRetries:        cc.MaxRetries,
+		RateLimit:         cc.RateLimit,
+		PageConcurrency:   cc.PageConcurrency,
+		ImportConcurrency: cc.ImportConcurrency,
+	}
+	if conn.Role == "" {
+		if conn.Type == "awx" {
+			conn.Role = "source"
+		} else {
+			conn.Role = "destination"
+		}
+	}
+	if conn.Scheme == "" {
+		if conn.Type == "aap" {
+			conn.Scheme = "https"
+		} else {
+			conn.Scheme = "http"
+		}
+	}
+	if conn.Port == 0 {
+		if conn.Scheme == "https" {
+			conn.Port = 443
+		} else {
+			conn.Port = 80
+		}
+	}
+	return conn
+}
+
+// FromConnection returns the config file entry for conn. The password, token
+// and an inline client key are left out, or replaced by a mask when mask is
+// set, so exported entries never carry secrets. A client key path is kept.
+func FromConnection(conn *models.Connection, mask bool) ConnectionConfig {
+	cc := ConnectionConfig{
+		Name:              conn.Name,
+		Type:              conn.Type,
+		Role:              conn.Role,
+		Scheme:            conn.Scheme,
+		Host:              conn.Host,
+		Port:              conn.Port,
+		Username:          conn.Username,
+		Insecure:          conn.Insecure,
+		CACert:            conn.CACert,
+		CACertFile:        conn.CACertFile,
+		ClientCert:        conn.ClientCert,
+		Timeout:           conn.Timeout,
+		MaxRetries:        conn.MaxRetries,
+		RateLimit:         conn.RateLimit,
+		PageConcurrency:   conn.PageConcurrency,
+		ImportConcurrency: conn.ImportConcurrency,
+	}
+	cc.ClientKey = conn.MaskedClientKey()
+	if mask {
+		cc.Password = conn.MaskedPassword()
+		cc.Token = conn.MaskedToken()
+	} else if cc.ClientKey != conn.ClientKey {
+		cc.ClientKey = "" // inline key
+	}
+	return cc
+}
+
+// ExpandNameTemplate fills {type}, {role}, {scheme}, {host} and {port}
+// placeholders in tmpl from the connection's settings.
+func ExpandNameTemplate(tmpl string, conn *models.Connection) string {
+	r := strings.NewReplacer(
+		"{type}", conn.Type,
+		"{role}", conn.Role,
+		"{scheme}", conn.Scheme,
+		"{host}", conn.Host,
+		"{port}", strconv.Itoa(conn.Port),
+	)
+	return strings.TrimSpace(r.Replace(tmpl))
+}
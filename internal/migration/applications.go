@@ -0,0 +1,108 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// exportApplications fetches the OAuth2 applications. Their client secrets
+// are dropped: the API only shows them once, when an application is created,
+// so the destination issues new ones. A source without the endpoint is
+// reported and skipped.
+func exportApplications(client *platform.Client, prefix string, logger func(string)) []models.Resource {
+	apps, err := fetchFiltered(client, prefix+"applications/", "applications", logger)
+	if err != nil {
+		logger(fmt.Sprintf("  WARNING: %v", err))
+		return nil
+	}
+	for _, app := range apps {
+		if _, ok := app["client_secret"]; ok {
+			delete(app, "client_secret")
+			logger(fmt.Sprintf("  %s: client secret removed (a new one is issued on the destination)", resourceName(app)))
+		}
+	}
+	return apps
+}
+
+// countConfidentialApplications returns the number of exported applications
+// whose client secret the destination will replace.
+func countConfidentialApplications(data *ExportedData) int {
+	var n int
+	for _, app := range data.Applications {
+		if stringField(app, "client_type") == "confidential" {
+			n++
+		}
+	}
+	return n
+}
+
+// grantType returns an application's authorization_grant_type in the form
+// the API expects, e.g. "authorization-code" for "authorization_code".
+func grantType(app models.Resource) string {
+	return strings.ReplaceAll(stringField(app, "authorization_grant_type"), "_", "-")
+}
+
+// redirectURIs returns an application's redirect_uris as the single
+// space-separated string the API expects.
+func redirectURIs(app models.Resource) string {
+	return strings.Join(strings.Fields(stringField(app, "redirect_uris")), " ")
+}
+
+// importApplications recreates OAuth2 applications in their organizations.
+// Confidential applications get a new client secret from the destination,
+// which has to be handed to their clients.
+func importApplications(dst *platform.Client, prefix string, data *ExportedData, preview *models.MigrationPreview, exclude map[string][]string, tf *Transforms, ids *idMap, progress *progressTracker, logger func(string)) {
+	for _, app := range data.Applications {
+		progress.step()
+		name := resourceName(app)
+		if isExcluded(exclude, "applications", name) {
+			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
+			continue
+		}
+		if ids.migrated(ids.apps, name, logger) {
+			continue
+		}
+		action, destID := actionFor(preview, "applications", name)
+		if action != "create" {
+			ids.apps[name] = destID
+			logger(fmt.Sprintf("  SKIP (exists): %s", name))
+			continue
+		}
+		orgID := ids.orgs[extractOrgName(app)]
+		if orgID == 0 {
+			logger(fmt.Sprintf("  SKIP: %s (organization not found)", name))
+			continue
+		}
+		grant, uris := grantType(app), redirectURIs(app)
+		if grant == "authorization-code" && uris == "" {
+			logger(fmt.Sprintf("  FAIL: %s: authorization-code applications need redirect_uris", name))
+			continue
+		}
+
+		payload := map[string]interface{}{
+			"name":                     name,
+			"description":              stringField(app, "description"),
+			"organization":             orgID,
+			"client_type":              stringField(app, "client_type"),
+			"authorization_grant_type": grant,
+			"redirect_uris":            uris,
+			"skip_authorization":       boolField(app, "skip_authorization"),
+		}
+
+		tf.apply("applications", name, payload, logger)
+		id, err := createResource(dst, prefix+"applications/", payload)
+		if err != nil {
+			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
+			continue
+		}
+		ids.apps[name] = id
+		if stringField(app, "client_type") == "confidential" {
+			logger(fmt.Sprintf("  CREATED: %s (ID %d) [new client secret — update its clients]", name, id))
+			continue
+		}
+		logger(fmt.Sprintf("  CREATED: %s (ID %d)", name, id))
+	}
+}
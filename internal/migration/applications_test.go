@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestImportAll_Application(t *testing.T) {
+	srv := &postRecorder{nextID: 100, posts: make(map[string][]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := newExportedData()
+	data.Organizations = []models.Resource{{"id": float64(1), "name": "Engineering"}}
+	data.Applications = []models.Resource{{
+		"id": float64(4), "name": "Grafana", "description": "dashboards",
+		"client_type": "confidential", "authorization_grant_type": "authorization_code",
+		"redirect_uris":      "https://grafana.example.com/login\n  https://grafana.example.com/alt",
+		"skip_authorization": true,
+		"summary_fields":     map[string]interface{}{"organization": map[string]interface{}{"name": "Engineering"}},
+	}}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, &models.MigrationPreview{},
+		ImportOptions{}, nil, nil, func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	posts := srv.posts["/api/v2/applications/"]
+	if len(posts) != 1 {
+		t.Fatalf("%d applications created, want 1", len(posts))
+	}
+	want := map[string]interface{}{
+		"name":                     "Grafana",
+		"description":              "dashboards",
+		"organization":             float64(101),
+		"client_type":              "confidential",
+		"authorization_grant_type": "authorization-code",
+		"redirect_uris":            "https://grafana.example.com/login https://grafana.example.com/alt",
+		"skip_authorization":       true,
+	}
+	for k, v := range want {
+		if posts[0][k] != v {
+			t.Errorf("%s = %v, want %v", k, posts[0][k], v)
+		}
+	}
+	if _, ok := posts[0]["client_secret"]; ok {
+		t.Error("client_secret sent to the destination")
+	}
+	if !containsLine(logs, "  CREATED: Grafana (ID 102) [new client secret — update its clients]") {
+		t.Errorf("missing CREATED line in %q", logs)
+	}
+}
+
+func TestImportAll_ApplicationWithoutRedirectURIs(t *testing.T) {
+	srv := &postRecorder{posts: make(map[string][]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := newExportedData()
+	data.Organizations = []models.Resource{{"id": float64(1), "name": "Engineering"}}
+	data.Applications = []models.Resource{{
+		"id": float64(4), "name": "Broken", "client_type": "public", "authorization_grant_type": "authorization-code",
+		"summary_fields": map[string]interface{}{"organization": map[string]interface{}{"name": "Engineering"}},
+	}}
+
+	var logs []string
+	importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, &models.MigrationPreview{},
+		ImportOptions{}, nil, nil, func(s string) { logs = append(logs, s) })
+	if n := len(srv.posts["/api/v2/applications/"]); n != 0 {
+		t.Errorf("%d applications created, want 0", n)
+	}
+	if !containsLine(logs, "  FAIL: Broken: authorization-code applications need redirect_uris") {
+		t.Errorf("missing FAIL line in %q", logs)
+	}
+}
+
+func TestExportApplications_DropsClientSecret(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":1,"next":null,"results":[
+			{"id":4,"name":"Grafana","client_type":"confidential","client_secret":"************"}]}`))
+	}))
+	defer ts.Close()
+
+	var logs []string
+	apps := exportApplications(newTestClient(t, ts), "/api/v2/", func(s string) { logs = append(logs, s) })
+	if len(apps) != 1 {
+		t.Fatalf("exported %d applications, want 1", len(apps))
+	}
+	if _, ok := apps[0]["client_secret"]; ok {
+		t.Error("client_secret kept in export")
+	}
+	if !containsLine(logs, "  Grafana: client secret removed (a new one is issued on the destination)") {
+		t.Errorf("missing redaction warning in %q", logs)
+	}
+
+	data := &ExportedData{Applications: apps}
+	if n := countConfidentialApplications(data); n != 1 {
+		t.Errorf("countConfidentialApplications = %d, want 1", n)
+	}
+}
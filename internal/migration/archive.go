@@ -0,0 +1,173 @@
+package migration
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// Archive entry names.
+const (
+	archiveHeader = "header.json"
+	archiveData   = "data.json"
+)
+
+// ExportToArchive exports all migratable resources from src and writes them
+// to a gzip tarball at path, so they can be imported later with
+// RunFromArchive without the source being reachable.
+func ExportToArchive(ctx context.Context, src *models.Connection, path string, opts ExportOptions, logger func(string)) error {
+	client := platform.NewClient(src).WithContext(ctx)
+	prefix := apiPrefix(src)
+
+	logger("Checking source connectivity...")
+	if _, err := client.Get(prefix+"organizations/", nil); err != nil {
+		return fmt.Errorf("source connection failed: %w", err)
+	}
+	logger("Source OK: " + src.Name)
+
+	logger("")
+	logger("=== Exporting from source ===")
+	data, err := exportAll(ctx, client, prefix, opts, logger)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	logger("")
+	logger("Writing archive: " + path)
+	if err := writeArchive(path, NewBundle(src, data, time.Now())); err != nil {
+		return err
+	}
+	logger("Archive complete")
+	return nil
+}
+
+// RunFromArchive reads an archive written by ExportToArchive (or an
+// ExportStreaming directory, see ReadArchive), checks the
+// destination for existing resources and imports the rest. Progress is
+// passed to report as described for Run.
+func RunFromArchive(ctx context.Context, dst *models.Connection, path string, opts ImportOptions, report func(completed, total int), logger func(string)) error {
+	bundle, err := ReadArchive(path)
+	if err != nil {
+		return err
+	}
+	h := bundle.Header
+	logger(fmt.Sprintf("Archive from %s (%s) exported %s", h.SourceName, h.SourceURL, h.ExportedAt.Format(time.RFC3339)))
+
+	client := platform.NewClient(dst).WithContext(ctx)
+	prefix := apiPrefix(dst)
+
+	logger("")
+	logger("=== Checking destination ===")
+	preview, err := preflightCheck(bundle.Data, client, prefix, nil, opts.Exclude, opts.Transforms, logger)
+	if err != nil {
+		return fmt.Errorf("preflight failed: %w", err)
+	}
+
+	logger("")
+	logger("=== Starting migration to " + dst.Name + " ===")
+	logger("")
+	opts.varsFormat = varsFormat(dst)
+	return importAll(ctx, client, prefix, bundle.Data, preview, opts, nil, report, logger)
+}
+
+// writeArchive stores the bundle header and data as separate JSON entries.
+func writeArchive(path string, bundle *Bundle) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	entries := []struct {
+		name  string
+		value interface{}
+	}{
+		{archiveHeader, bundle.Header},
+		{archiveData, bundle.Data},
+	}
+	for _, e := range entries {
+		body, err := json.MarshalIndent(e.value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", e.name, err)
+		}
+		hdr := &tar.Header{
+			Name:    e.name,
+			Mode:    0644,
+			Size:    int64(len(body)),
+			ModTime: bundle.Header.ExportedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("writing %s: %w", e.name, err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			return fmt.Errorf("writing %s: %w", e.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadArchive loads a bundle from an archive written by ExportToArchive, or
+// from a directory written by ExportStreaming, and rejects it if the data is
+// incomplete or has dangling references.
+func ReadArchive(path string) (*Bundle, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return ReadExportDir(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	bundle := &Bundle{}
+	var seenHeader bool
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		switch hdr.Name {
+		case archiveHeader:
+			err = json.NewDecoder(tr).Decode(&bundle.Header)
+			seenHeader = true
+		case archiveData:
+			bundle.Data = &ExportedData{}
+			err = json.NewDecoder(tr).Decode(bundle.Data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", hdr.Name, err)
+		}
+	}
+	if !seenHeader || bundle.Data == nil {
+		return nil, fmt.Errorf("archive is missing %s or %s", archiveHeader, archiveData)
+	}
+	if err := validateData(bundle.Data); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
@@ -0,0 +1,102 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// createServer fakes an empty destination: lookups find nothing and every
+// POST creates an object with the next free ID.
+type createServer struct {
+	mu     sync.Mutex
+	nextID int
+	posts  map[string]int // path → POST count
+}
+
+func (c *createServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r.Method != "POST" {
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		return
+	}
+	c.nextID++
+	c.posts[r.URL.Path]++
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, `{"id":%d}`, c.nextID)
+}
+
+func (c *createServer) postsMatching(suffix string) int {
+	var n int
+	for path, count := range c.posts {
+		if strings.HasSuffix(path, suffix) {
+			n += count
+		}
+	}
+	return n
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	const n = 5
+	src := inventoryServer(n)
+	defer src.Close()
+
+	path := filepath.Join(t.TempDir(), "export.tar.gz")
+	srcConn := newTestConnection(t, src)
+	srcConn.ID, srcConn.Name = "src-1", "Old AWX"
+	if err := ExportToArchive(context.Background(), srcConn, path, ExportOptions{}, func(string) {}); err != nil {
+		t.Fatalf("ExportToArchive: %v", err)
+	}
+
+	bundle, err := ReadArchive(path)
+	if err != nil {
+		t.Fatalf("ReadArchive: %v", err)
+	}
+	if bundle.Header.SourceID != "src-1" || bundle.Header.SourceName != "Old AWX" {
+		t.Errorf("header = %+v", bundle.Header)
+	}
+	if len(bundle.Data.Inventories) != n || len(bundle.Data.Hosts[3]) != 4 {
+		t.Fatalf("archive holds %d inventories and %d hosts for inventory 3, want %d and 4",
+			len(bundle.Data.Inventories), len(bundle.Data.Hosts[3]), n)
+	}
+	if got := bundle.Data.GroupHosts[10003]; len(got) != 1 || got[0] != 300 {
+		t.Errorf("GroupHosts[10003] = %v, want [300]", got)
+	}
+
+	// Re-import against an empty destination; the source is no longer needed.
+	src.Close()
+	dstSrv := &createServer{posts: make(map[string]int)}
+	dst := httptest.NewServer(dstSrv)
+	defer dst.Close()
+
+	var logs []string
+	err = RunFromArchive(context.Background(), newTestConnection(t, dst), path, ImportOptions{}, nil, func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("RunFromArchive: %v", err)
+	}
+	if got := dstSrv.posts["/api/v2/inventories/"]; got != n {
+		t.Errorf("created %d inventories, want %d", got, n)
+	}
+	var wantHosts int
+	for i := 1; i <= n; i++ {
+		wantHosts += i%5 + 1
+	}
+	if got := dstSrv.postsMatching("/hosts/"); got < wantHosts {
+		t.Errorf("posted %d hosts, want at least %d", got, wantHosts)
+	}
+	if !strings.HasPrefix(logs[0], "Archive from Old AWX") {
+		t.Errorf("first log line = %q", logs[0])
+	}
+}
+
+func TestReadArchive_Missing(t *testing.T) {
+	if _, err := ReadArchive(filepath.Join("testdata", "missing.tar.gz")); err == nil {
+		t.Fatal("expected error for missing archive, got nil")
+	}
+}
@@ -0,0 +1,50 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// BundleHeader identifies where and when an export bundle was captured.
+type BundleHeader struct {
+	SourceID      string    `json:"source_id"`
+	SourceName    string    `json:"source_name"`
+	SourceType    string    `json:"source_type"`
+	SourceURL     string    `json:"source_url"`
+	SourceVersion string    `json:"source_version,omitempty"`
+	ExportedAt    time.Time `json:"exported_at"`
+}
+
+// Bundle is a self-contained JSON snapshot of exported source data.
+type Bundle struct {
+	Header BundleHeader  `json:"header"`
+	Data   *ExportedData `json:"data"`
+}
+
+// NewBundle wraps exported data with its source identity. Credential inputs
+// are replaced with a note, matching the redaction applied by Platform.Export;
+// the original data is left untouched.
+func NewBundle(src *models.Connection, data *ExportedData, exportedAt time.Time) *Bundle {
+	redacted := *data
+	redacted.Credentials = make([]models.Resource, len(data.Credentials))
+	for i, cred := range data.Credentials {
+		c := make(models.Resource, len(cred))
+		for k, v := range cred {
+			c[k] = v
+		}
+		c["inputs"] = map[string]interface{}{"_note": "Sensitive data removed"}
+		redacted.Credentials[i] = c
+	}
+	return &Bundle{
+		Header: BundleHeader{
+			SourceID:      src.ID,
+			SourceName:    src.Name,
+			SourceType:    src.Type,
+			SourceURL:     src.BaseURL(),
+			SourceVersion: src.Version,
+			ExportedAt:    exportedAt.UTC(),
+		},
+		Data: &redacted,
+	}
+}
@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestNewBundle_RedactsCredentialInputs(t *testing.T) {
+	src := &models.Connection{ID: "src-1", Name: "AWX", Type: "awx", Scheme: "http", Host: "awx.local", Port: 80}
+	data := &ExportedData{
+		Credentials: []models.Resource{
+			{"id": float64(3), "name": "Machine", "inputs": map[string]interface{}{"username": "root"}},
+		},
+	}
+
+	b := NewBundle(src, data, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	if b.Header.SourceID != "src-1" || b.Header.SourceURL != "http://awx.local:80" {
+		t.Errorf("header = %+v", b.Header)
+	}
+	inputs, _ := b.Data.Credentials[0]["inputs"].(map[string]interface{})
+	if _, ok := inputs["username"]; ok {
+		t.Error("bundle credential inputs should be redacted")
+	}
+	orig, _ := data.Credentials[0]["inputs"].(map[string]interface{})
+	if orig["username"] != "root" {
+		t.Error("NewBundle must not modify the original export data")
+	}
+}
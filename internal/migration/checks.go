@@ -0,0 +1,155 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// Check statuses.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// Check is the outcome of one pre-migration sanity check.
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // CheckPass, CheckWarn or CheckFail
+	Message string `json:"message"`
+}
+
+// awxControllerVersions pairs AWX major versions with the controller version
+// of the AAP release built from them, so AWX and AAP versions can be
+// compared. An AWX version maps to the last entry it is at least.
+var awxControllerVersions = []struct{ awx, controller string }{
+	{"21", "4.3"},
+	{"22", "4.4"},
+	{"23", "4.5"},
+	{"24", "4.6"},
+}
+
+// SanityChecks runs quick read-only checks before a migration: both
+// connections authenticate, the source is not newer than the destination,
+// and the destination user is an admin. Nothing is exported. Checks that
+// need a connection that failed to authenticate are left out.
+func SanityChecks(src, dst *models.Connection) []Check {
+	var checks []Check
+	srcCheck := checkAuth("source authentication", src)
+	dstCheck := checkAuth("destination authentication", dst)
+	checks = append(checks, srcCheck, dstCheck)
+	if srcCheck.Status == CheckPass && dstCheck.Status == CheckPass {
+		checks = append(checks, checkVersions(src, dst))
+	}
+	if dstCheck.Status == CheckPass {
+		client := platform.NewClient(dst)
+		prefix := apiPrefix(dst)
+		checks = append(checks, checkAdmin(client, prefix), checkOrganizations(client, prefix))
+	}
+	return checks
+}
+
+// checkAuth verifies that conn accepts its credentials.
+func checkAuth(name string, conn *models.Connection) Check {
+	if !conn.HasCredentials() {
+		return Check{name, CheckFail, conn.Name + ": no credentials configured"}
+	}
+	if err := platform.NewPlatform(conn).CheckAuth(); err != nil {
+		return Check{name, CheckFail, fmt.Sprintf("%s: %v", conn.Name, err)}
+	}
+	return Check{name, CheckPass, conn.Name + ": authenticated"}
+}
+
+// checkVersions warns when the source runs a newer version than the
+// destination, whose API may reject fields the source exports.
+func checkVersions(src, dst *models.Connection) Check {
+	const name = "versions"
+	srcVersion, dstVersion := pingVersion(src), pingVersion(dst)
+	if srcVersion == "" || dstVersion == "" {
+		return Check{name, CheckWarn, "could not read the version of both connections"}
+	}
+	a, b := srcVersion, dstVersion
+	if src.Type != dst.Type {
+		a, b = controllerVersion(src.Type, srcVersion), controllerVersion(dst.Type, dstVersion)
+	}
+	desc := fmt.Sprintf("source %s %s, destination %s %s", src.Type, srcVersion, dst.Type, dstVersion)
+	if a != "" && b != "" && platform.CompareVersions(a, b) > 0 {
+		return Check{name, CheckWarn, desc + ": the source is newer, fields it added may be rejected"}
+	}
+	return Check{name, CheckPass, desc}
+}
+
+// pingVersion reads a connection's version from its ping endpoint, or
+// returns "" if no ping endpoint reports one.
+func pingVersion(conn *models.Connection) string {
+	client := platform.NewClient(conn)
+	for _, path := range platform.PingPaths(conn.Type) {
+		if resp, err := client.PingWithVersion(path); err == nil && resp.Version != "" {
+			return resp.Version
+		}
+	}
+	return ""
+}
+
+// controllerVersion returns the controller version matching version of a
+// platform type, or "" for AWX releases older than awxControllerVersions.
+func controllerVersion(connType, version string) string {
+	if connType == "aap" {
+		return version
+	}
+	var controller string
+	for _, v := range awxControllerVersions {
+		if platform.CompareVersions(version, v.awx) >= 0 {
+			controller = v.controller
+		}
+	}
+	return controller
+}
+
+// checkAdmin fails unless the destination user is a superuser, since
+// migrations create organizations, users and role assignments.
+func checkAdmin(client *platform.Client, prefix string) Check {
+	const name = "destination admin access"
+	body, err := client.Get(prefix+"me/", nil)
+	if err != nil {
+		return Check{name, CheckWarn, fmt.Sprintf("reading the current user: %v", err)}
+	}
+	var me struct {
+		Results []struct {
+			Username    string `json:"username"`
+			IsSuperuser bool   `json:"is_superuser"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &me); err != nil || len(me.Results) == 0 {
+		return Check{name, CheckWarn, "could not read the current user"}
+	}
+	user := me.Results[0]
+	if !user.IsSuperuser {
+		return Check{name, CheckFail, user.Username + " is not a superuser"}
+	}
+	return Check{name, CheckPass, user.Username + " is a superuser"}
+}
+
+// checkOrganizations warns when the destination already holds organizations
+// besides Default, whose resources may conflict with migrated ones.
+func checkOrganizations(client *platform.Client, prefix string) Check {
+	const name = "destination organizations"
+	body, err := client.Get(prefix+"organizations/", url.Values{"page_size": {"1"}})
+	if err != nil {
+		return Check{name, CheckWarn, fmt.Sprintf("counting organizations: %v", err)}
+	}
+	var page struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return Check{name, CheckWarn, fmt.Sprintf("counting organizations: %v", err)}
+	}
+	if page.Count > 1 {
+		return Check{name, CheckWarn, fmt.Sprintf("destination already has %d organizations; existing resources will be skipped or updated", page.Count)}
+	}
+	return Check{name, CheckPass, fmt.Sprintf("destination has %d organization(s)", page.Count)}
+}
@@ -0,0 +1,89 @@
+package migration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// checkServer fakes a platform answering the endpoints SanityChecks reads,
+// under prefix.
+func checkServer(prefix, version string, authOK bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case prefix + "ping/":
+			w.Write([]byte(`{"version":"` + version + `"}`))
+		case prefix + "organizations/":
+			if !authOK {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"detail":"Invalid username/password."}`))
+				return
+			}
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Default"}]}`))
+		case prefix + "me/":
+			w.Write([]byte(`{"count":1,"results":[{"username":"admin","is_superuser":true}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func checkByName(checks []Check, name string) *Check {
+	for i := range checks {
+		if checks[i].Name == name {
+			return &checks[i]
+		}
+	}
+	return nil
+}
+
+func TestSanityChecks_AuthFailure(t *testing.T) {
+	src := checkServer("/api/v2/", "24.6.1", true)
+	defer src.Close()
+	dst := checkServer("/api/v2/", "24.6.1", false)
+	defer dst.Close()
+
+	checks := SanityChecks(newTestConnection(t, src), newTestConnection(t, dst))
+
+	if c := checkByName(checks, "source authentication"); c == nil || c.Status != CheckPass {
+		t.Errorf("source authentication = %+v, want pass", c)
+	}
+	c := checkByName(checks, "destination authentication")
+	if c == nil || c.Status != CheckFail || !strings.Contains(c.Message, "401") {
+		t.Errorf("destination authentication = %+v, want fail with HTTP 401", c)
+	}
+	if len(checks) != 2 {
+		t.Errorf("got %d checks, want only the 2 authentication checks: %+v", len(checks), checks)
+	}
+}
+
+func TestSanityChecks_NewerSourceWarns(t *testing.T) {
+	src := checkServer("/api/v2/", "24.6.1", true)
+	defer src.Close()
+	dst := checkServer("/api/controller/v2/", "4.5.12", true)
+	defer dst.Close()
+	dstConn := newTestConnection(t, dst)
+	dstConn.Type = "aap"
+
+	checks := SanityChecks(newTestConnection(t, src), dstConn)
+
+	c := checkByName(checks, "versions")
+	if c == nil || c.Status != CheckWarn || !strings.Contains(c.Message, "source awx 24.6.1, destination aap 4.5.12") {
+		t.Errorf("versions = %+v, want a warning for AWX 24 into controller 4.5", c)
+	}
+	for _, name := range []string{"source authentication", "destination authentication", "destination admin access", "destination organizations"} {
+		if c := checkByName(checks, name); c == nil || c.Status != CheckPass {
+			t.Errorf("%s = %+v, want pass", name, c)
+		}
+	}
+
+	// The same destination on a matching release passes.
+	dst2 := checkServer("/api/controller/v2/", "4.6.8", true)
+	defer dst2.Close()
+	dst2Conn := newTestConnection(t, dst2)
+	dst2Conn.Type = "aap"
+	if c := checkByName(SanityChecks(newTestConnection(t, src), dst2Conn), "versions"); c == nil || c.Status != CheckPass {
+		t.Errorf("versions against 4.6.8 = %+v, want pass", c)
+	}
+}
@@ -0,0 +1,100 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// constructedInventoryFields are the settings of a constructed inventory's
+// built-in source, read from the constructed_inventories endpoint.
+var constructedInventoryFields = []string{"source_vars", "limit", "update_cache_timeout", "verbosity"}
+
+// isConstructed reports whether inv is a constructed inventory, whose hosts
+// and groups are generated from its input inventories.
+func isConstructed(inv models.Resource) bool {
+	return stringField(inv, "kind") == "constructed"
+}
+
+// exportConstructedInventories adds the source settings of every exported
+// constructed inventory to it and records the names of its input
+// inventories, in order.
+func exportConstructedInventories(client *platform.Client, prefix string, data *ExportedData, logger func(string)) {
+	logger("Exporting constructed inventories...")
+	var count int
+	for _, inv := range data.Inventories {
+		if !isConstructed(inv) {
+			continue
+		}
+		invID := resourceID(inv)
+		var ci models.Resource
+		if err := client.GetJSON(fmt.Sprintf("%sconstructed_inventories/%d/", prefix, invID), nil, &ci); err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get constructed inventory %s: %v", resourceName(inv), err))
+		} else {
+			for _, f := range constructedInventoryFields {
+				if v, ok := ci[f]; ok {
+					inv[f] = v
+				}
+			}
+		}
+		inputs, err := client.GetAll(fmt.Sprintf("%sinventories/%d/input_inventories/", prefix, invID))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get input inventories for %s: %v", resourceName(inv), err))
+			continue
+		}
+		for _, in := range inputs {
+			data.InputInventories[invID] = append(data.InputInventories[invID], resourceName(in))
+		}
+		count++
+	}
+	logger(fmt.Sprintf("  %d constructed inventories", count))
+}
+
+// inventoryPayload returns the API path and POST body for a migrated
+// inventory. Constructed inventories are created through their own endpoint
+// with the settings of their source.
+func inventoryPayload(prefix string, inv models.Resource, orgID int) (string, map[string]interface{}) {
+	payload := map[string]interface{}{
+		"name":         resourceName(inv),
+		"description":  stringField(inv, "description"),
+		"organization": orgID,
+		"variables":    stringField(inv, "variables"),
+	}
+	if !isConstructed(inv) {
+		return prefix + "inventories/", payload
+	}
+	for _, f := range constructedInventoryFields {
+		if v, ok := inv[f]; ok && v != nil {
+			payload[f] = v
+		}
+	}
+	return prefix + "constructed_inventories/", payload
+}
+
+// importInputInventories associates migrated constructed inventories with
+// their input inventories, in the source order. Inputs are resolved by name
+// among the migrated inventories first, then on the destination.
+func importInputInventories(dst *platform.Client, prefix string, data *ExportedData, ids *idMap, assoc *associator, logger func(string)) {
+	for _, inv := range data.Inventories {
+		inputs := data.InputInventories[resourceID(inv)]
+		name := resourceName(inv)
+		destID := ids.invs[name]
+		if !isConstructed(inv) || len(inputs) == 0 || destID == 0 {
+			continue
+		}
+		path := fmt.Sprintf("%sinventories/%d/input_inventories/", prefix, destID)
+		for _, input := range inputs {
+			inputID := findMigrated(dst, prefix+"inventories/", ids.invs, input)
+			if inputID == 0 {
+				logger(fmt.Sprintf("  FAIL: %s → %s: input inventory not found", name, input))
+				continue
+			}
+			if _, err := assoc.associate(path, inputID); err != nil {
+				logger(fmt.Sprintf("  FAIL: %s → %s: %v", name, input, err))
+				continue
+			}
+			logger(fmt.Sprintf("  %s → %s (input inventory)", name, input))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// postRecorder fakes a destination with no existing objects that records
+// every POST body by path and answers with increasing IDs.
+type postRecorder struct {
+	mu     sync.Mutex
+	nextID int
+	posts  map[string][]map[string]interface{}
+}
+
+func (s *postRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Method == "GET" {
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		return
+	}
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	s.posts[r.URL.Path] = append(s.posts[r.URL.Path], body)
+	s.nextID++
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": s.nextID})
+}
+
+func TestImportAll_ConstructedInventory(t *testing.T) {
+	srv := &postRecorder{nextID: 100, posts: make(map[string][]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := newExportedData()
+	data.Inventories = []models.Resource{
+		{"id": float64(1), "name": "Prod", "kind": ""},
+		{"id": float64(2), "name": "Edge", "kind": "constructed",
+			"source_vars": "plugin: constructed\nstrict: true", "limit": "webservers"},
+		{"id": float64(3), "name": "Stage", "kind": ""},
+	}
+	data.InputInventories[2] = []string{"Stage", "Prod"}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, &models.MigrationPreview{},
+		ImportOptions{}, nil, nil, func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	if n := len(srv.posts["/api/v2/inventories/"]); n != 2 {
+		t.Errorf("%d regular inventories created, want 2", n)
+	}
+	constructed := srv.posts["/api/v2/constructed_inventories/"]
+	if len(constructed) != 1 {
+		t.Fatalf("%d constructed inventories created, want 1", len(constructed))
+	}
+	if constructed[0]["name"] != "Edge" || constructed[0]["source_vars"] != "plugin: constructed\nstrict: true" ||
+		constructed[0]["limit"] != "webservers" {
+		t.Errorf("constructed inventory payload = %v", constructed[0])
+	}
+
+	// Prod is 101, Edge 102 and Stage 103; inputs keep the source order.
+	var inputs []float64
+	for _, body := range srv.posts["/api/v2/inventories/102/input_inventories/"] {
+		inputs = append(inputs, body["id"].(float64))
+	}
+	if want := []float64{103, 101}; !reflect.DeepEqual(inputs, want) {
+		t.Errorf("input inventories = %v, want %v", inputs, want)
+	}
+	if !containsLine(logs, "  Edge → Stage (input inventory)") {
+		t.Errorf("missing input inventory log line in %q", logs)
+	}
+}
+
+func TestExportConstructedInventories(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/constructed_inventories/2/":
+			w.Write([]byte(`{"id":2,"name":"Edge","source_vars":"plugin: constructed","limit":"web","verbosity":1}`))
+		case "/api/v2/inventories/2/input_inventories/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":3,"name":"Stage"},{"id":1,"name":"Prod"}]}`))
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	data := newExportedData()
+	data.Inventories = []models.Resource{
+		{"id": float64(1), "name": "Prod", "kind": ""},
+		{"id": float64(2), "name": "Edge", "kind": "constructed"},
+	}
+	exportConstructedInventories(newTestClient(t, ts), "/api/v2/", data, func(string) {})
+
+	if got := data.InputInventories[2]; !reflect.DeepEqual(got, []string{"Stage", "Prod"}) {
+		t.Errorf("input inventories = %v, want [Stage Prod]", got)
+	}
+	if edge := data.Inventories[1]; edge["source_vars"] != "plugin: constructed" || edge["limit"] != "web" {
+		t.Errorf("constructed inventory = %v, want source_vars and limit copied", edge)
+	}
+}
@@ -0,0 +1,109 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// exportCredentialInputSources records the input sources of each exported
+// credential: the inputs it looks up from an external secret backend
+// through another ("source") credential.
+func exportCredentialInputSources(client *platform.Client, prefix string, data *ExportedData, logger func(string)) {
+	logger("Exporting credential input sources...")
+	var count int
+	for _, cred := range data.Credentials {
+		credID := resourceID(cred)
+		sources, err := client.GetAll(fmt.Sprintf("%scredentials/%d/input_sources/", prefix, credID))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get input sources for credential %s: %v", resourceName(cred), err))
+			continue
+		}
+		if len(sources) == 0 {
+			continue
+		}
+		data.CredInputSources[credID] = sources
+		count += len(sources)
+	}
+	logger(fmt.Sprintf("  %d credential input sources", count))
+}
+
+// importCredentialInputSources links migrated credentials to their secret
+// backends again. Source credentials are resolved by name among the migrated
+// ones first, then on the destination; input fields that already have a
+// source on the destination are left alone, so reruns are harmless.
+func importCredentialInputSources(dst *platform.Client, prefix string, data *ExportedData, ids *idMap, logger func(string)) {
+	missing := make(map[string]bool)
+	for _, cred := range data.Credentials {
+		sources := data.CredInputSources[resourceID(cred)]
+		if len(sources) == 0 {
+			continue
+		}
+		name := resourceName(cred)
+		destID := ids.creds[name]
+		if destID == 0 {
+			continue
+		}
+		path := fmt.Sprintf("%scredentials/%d/input_sources/", prefix, destID)
+		linked := make(map[string]bool)
+		existing, err := dst.GetAll(path)
+		if err != nil {
+			logger(fmt.Sprintf("  FAIL: %s: listing input sources on destination: %v", name, err))
+			continue
+		}
+		for _, s := range existing {
+			linked[stringField(s, "input_field_name")] = true
+		}
+
+		for _, src := range sources {
+			field := stringField(src, "input_field_name")
+			backend, _ := summaryField(src, "source_credential", "name").(string)
+			if linked[field] {
+				logger(fmt.Sprintf("  SKIP (exists): %s.%s", name, field))
+				continue
+			}
+			backendID := ids.creds[backend]
+			if backendID == 0 {
+				existing, err := dst.FindByName(prefix+"credentials/", backend)
+				if err != nil {
+					logger(fmt.Sprintf("  FAIL: %s.%s: looking up source credential %q: %v", name, field, backend, err))
+					continue
+				}
+				if existing != nil {
+					backendID = resourceID(existing)
+				}
+			}
+			if backendID == 0 {
+				if !missing[backend] {
+					logger(fmt.Sprintf("  WARNING: secret backend credential %q not found on destination", backend))
+					missing[backend] = true
+				}
+				logger(fmt.Sprintf("  SKIP: %s.%s (source credential %q missing)", name, field, backend))
+				continue
+			}
+			payload := map[string]interface{}{
+				"input_field_name":  field,
+				"source_credential": backendID,
+				"description":       stringField(src, "description"),
+			}
+			if metadata, ok := src["metadata"].(map[string]interface{}); ok {
+				payload["metadata"] = metadata
+			}
+			if _, err := createResource(dst, path, payload); err != nil {
+				logger(fmt.Sprintf("  FAIL: %s.%s: %v", name, field, err))
+				continue
+			}
+			logger(fmt.Sprintf("  CREATED: %s.%s ← %s", name, field, backend))
+		}
+	}
+}
+
+// countCredentialInputSources returns the number of exported credential
+// input sources.
+func countCredentialInputSources(data *ExportedData) int {
+	var n int
+	for _, sources := range data.CredInputSources {
+		n += len(sources)
+	}
+	return n
+}
@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+const inputSourcePage = `{"count":1,"next":null,"results":[{"id":9,"input_field_name":"password",
+	"metadata":{"secret_path":"/kv/deploy","secret_key":"password"},"source_credential":3,"target_credential":1,
+	"summary_fields":{"source_credential":{"id":3,"name":"Vault Lookup"}}}]}`
+
+func TestExportCredentialInputSources(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/credentials/1/input_sources/" {
+			w.Write([]byte(inputSourcePage))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := newExportedData()
+	data.Credentials = []models.Resource{
+		{"id": float64(1), "name": "Deploy"},
+		{"id": float64(3), "name": "Vault Lookup"},
+	}
+	exportCredentialInputSources(newTestClient(t, ts), "/api/v2/", data, func(string) {})
+
+	if got := data.CredInputSources[1]; len(got) != 1 || stringField(got[0], "input_field_name") != "password" {
+		t.Errorf("Deploy input sources = %v, want the password lookup", got)
+	}
+	if n := countCredentialInputSources(data); n != 1 {
+		t.Errorf("countCredentialInputSources = %d, want 1", n)
+	}
+}
+
+func TestExportCredentialInputSources_WarnsOnError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	data := newExportedData()
+	data.Credentials = []models.Resource{{"id": float64(1), "name": "Deploy"}}
+	var logs []string
+	exportCredentialInputSources(newTestClient(t, ts), "/api/v2/", data, func(s string) { logs = append(logs, s) })
+
+	if len(logs) != 3 || !strings.HasPrefix(logs[1], "  WARNING: failed to get input sources for credential Deploy: ") {
+		t.Errorf("missing warning in %q", logs)
+	}
+}
+
+func TestImportCredentialInputSources(t *testing.T) {
+	var mu sync.Mutex
+	var posted []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && r.URL.Path == "/api/v2/credentials/10/input_sources/" {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			posted = append(posted, body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":90}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	var page struct{ Results []models.Resource }
+	json.Unmarshal([]byte(inputSourcePage), &page)
+	data := newExportedData()
+	data.Credentials = []models.Resource{{"id": float64(1), "name": "Deploy"}}
+	data.CredInputSources[1] = page.Results
+
+	ids := newIDMap()
+	ids.creds["Deploy"] = 10
+	ids.creds["Vault Lookup"] = 30
+	importCredentialInputSources(newTestClient(t, ts), "/api/v2/", data, ids, func(string) {})
+
+	if len(posted) != 1 {
+		t.Fatalf("posted %d input sources, want 1", len(posted))
+	}
+	p := posted[0]
+	if p["input_field_name"] != "password" || p["source_credential"] != float64(30) {
+		t.Errorf("payload = %v, want password from credential 30", p)
+	}
+	if md, _ := p["metadata"].(map[string]interface{}); md["secret_path"] != "/kv/deploy" {
+		t.Errorf("metadata = %v, want secret_path /kv/deploy", p["metadata"])
+	}
+
+	// Without the backend credential the input source is skipped with a warning.
+	posted = nil
+	delete(ids.creds, "Vault Lookup")
+	var logs []string
+	importCredentialInputSources(newTestClient(t, ts), "/api/v2/", data, ids, func(s string) { logs = append(logs, s) })
+	if len(posted) != 0 {
+		t.Errorf("posted %v without a backend credential", posted)
+	}
+	if !containsLine(logs, `  WARNING: secret backend credential "Vault Lookup" not found on destination`) {
+		t.Errorf("missing warning in %q", logs)
+	}
+}
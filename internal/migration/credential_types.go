@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"fmt"
+)
+
+// credTypeKindRemap translates credential type kinds the destination does not
+// accept for custom types. Only "cloud" and "net" can be created through the
+// API; kinds that older AWX releases allowed on custom types map to "cloud".
+var credTypeKindRemap = map[string]string{
+	"external":   "cloud",
+	"galaxy":     "cloud",
+	"insights":   "cloud",
+	"kubernetes": "cloud",
+	"registry":   "cloud",
+	"token":      "cloud",
+}
+
+// remapCredTypeKind returns the kind to create a custom credential type with,
+// logging any translation.
+func remapCredTypeKind(name, kind string, logger func(string)) string {
+	to, ok := credTypeKindRemap[kind]
+	if !ok {
+		return kind
+	}
+	logger(fmt.Sprintf("  WARNING: %s: kind %q not supported for custom types, using %q", name, kind, to))
+	return to
+}
+
+// missingCredTypeReason explains why a credential's type could not be
+// resolved, naming the expected kind when it is a managed type.
+func missingCredTypeReason(data *ExportedData, ctName string) string {
+	if kind, ok := data.ManagedCredTypeKinds[ctName]; ok {
+		return fmt.Sprintf("managed credential type %q of kind %q not on destination", ctName, kind)
+	}
+	return "credential type not found"
+}
@@ -0,0 +1,71 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestImportAll_RemapsCredentialTypeKind(t *testing.T) {
+	var mu sync.Mutex
+	var posted map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && r.URL.Path == "/api/v2/credential_types/" {
+			json.NewDecoder(r.Body).Decode(&posted)
+			w.Write([]byte(`{"id":40}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		CredentialTypes: []models.Resource{{"id": float64(30), "name": "Legacy Insights", "kind": "insights"}},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+	if got := posted["kind"]; got != "cloud" {
+		t.Errorf("posted kind = %v, want cloud", got)
+	}
+	if !containsLine(logs, `  WARNING: Legacy Insights: kind "insights" not supported for custom types, using "cloud"`) {
+		t.Errorf("missing remap warning in %q", logs)
+	}
+}
+
+func TestImportAll_MissingManagedCredentialType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			t.Errorf("unexpected POST %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		ManagedCredTypeKinds: map[string]string{"Ansible Tower": "cloud"},
+		Credentials: []models.Resource{{"id": float64(5), "name": "Controller", "credential_type": float64(16),
+			"summary_fields": map[string]interface{}{"credential_type": map[string]interface{}{"id": float64(16), "name": "Ansible Tower"}}}},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+	if !containsLine(logs, `  SKIP: Controller (managed credential type "Ansible Tower" of kind "cloud" not on destination)`) {
+		t.Errorf("missing managed type skip in %q", logs)
+	}
+}
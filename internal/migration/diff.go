@@ -0,0 +1,202 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// diffFields lists the plain fields compared for each resource type.
+// These mirror the fields importAll sends when creating the resource.
+var diffFields = map[string][]string{
+	"organizations":          {"description"},
+	"teams":                  {"description"},
+	"users":                  {"first_name", "last_name", "email"},
+	"credential_types":       {"description", "kind", "inputs", "injectors"},
+	"credentials":            {"description"},
+	"execution_environments": {"description", "image", "pull"},
+	"applications":           {"description", "client_type", "authorization_grant_type", "redirect_uris", "skip_authorization"},
+	"projects": {"description", "scm_type", "scm_url", "scm_branch", "scm_clean",
+		"scm_delete_on_update", "scm_track_submodules", "scm_update_on_launch", "scm_update_cache_timeout"},
+	"inventories": {"description", "variables"},
+	"job_templates": {"description", "job_type", "playbook", "forks", "limit", "verbosity", "extra_vars",
+		"ask_variables_on_launch", "ask_limit_on_launch", "ask_tags_on_launch", "ask_diff_mode_on_launch",
+		"ask_skip_tags_on_launch", "ask_job_type_on_launch", "ask_credential_on_launch", "ask_verbosity_on_launch",
+		"ask_inventory_on_launch", "ask_scm_branch_on_launch", "survey_enabled", "become_enabled", "diff_mode",
+		"allow_simultaneous", "job_slice_count", "timeout", "use_fact_cache", "host_config_key", "scm_branch"},
+	"workflow_job_templates": {"description", "survey_enabled", "allow_simultaneous", "ask_variables_on_launch",
+		"ask_inventory_on_launch", "ask_scm_branch_on_launch", "ask_limit_on_launch", "ask_labels_on_launch",
+		"extra_vars", "limit", "scm_branch"},
+	"schedules": {"rrule"},
+}
+
+// diffRefs lists the related objects compared by name (summary_fields.{section}.name)
+// rather than by ID, since IDs never match across instances.
+var diffRefs = map[string][]string{
+	"teams":                  {"organization"},
+	"credentials":            {"organization", "credential_type"},
+	"execution_environments": {"organization", "credential"},
+	"applications":           {"organization"},
+	"projects":               {"organization", "credential"},
+	"inventories":            {"organization"},
+	"job_templates":          {"project", "inventory", "execution_environment"},
+	"workflow_job_templates": {"organization"},
+	"schedules":              {"unified_job_template"},
+}
+
+// SupportsDiff reports whether DiffResource can compare the given resource type.
+func SupportsDiff(typeName string) bool {
+	_, ok := diffFields[typeName]
+	return ok
+}
+
+// DiffResource fetches the named object of the given type from both connections
+// and compares the fields that migration would carry over.
+func DiffResource(src, dst *models.Connection, typeName, name string) (*models.ResourceDiff, error) {
+	if !SupportsDiff(typeName) {
+		return nil, fmt.Errorf("unsupported resource type: %s", typeName)
+	}
+
+	srcObj, err := findNamed(platform.NewClient(src), apiPrefix(src), typeName, name)
+	if err != nil {
+		return nil, fmt.Errorf("source lookup failed: %w", err)
+	}
+	dstObj, err := findNamed(platform.NewClient(dst), apiPrefix(dst), typeName, name)
+	if err != nil {
+		return nil, fmt.Errorf("destination lookup failed: %w", err)
+	}
+
+	diff := &models.ResourceDiff{Type: typeName, Name: name}
+	switch {
+	case srcObj == nil && dstObj == nil:
+		diff.Status = "not_found"
+	case dstObj == nil:
+		diff.Status = "only_in_source"
+	case srcObj == nil:
+		diff.Status = "only_in_destination"
+	default:
+		diff.Differences = diffResources(srcObj, dstObj, typeName)
+		diff.Status = "identical"
+		if len(diff.Differences) > 0 {
+			diff.Status = "different"
+		}
+	}
+	return diff, nil
+}
+
+// DiffConnections exports both connections (limited to types, if given) and
+// compares them by name. Default objects skipped by export are left out on
+// both sides. Nothing is written to either connection or to disk.
+func DiffConnections(ctx context.Context, src, dst *models.Connection, types []string, logger func(string)) (*models.ConnectionDiff, error) {
+	opts := ExportOptions{Types: types}
+	logger("=== Exporting from source ===")
+	srcData, err := exportAll(ctx, platform.NewClient(src).WithContext(ctx), apiPrefix(src), opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("source export failed: %w", err)
+	}
+	logger("")
+	logger("=== Exporting from destination ===")
+	dstData, err := exportAll(ctx, platform.NewClient(dst).WithContext(ctx), apiPrefix(dst), opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("destination export failed: %w", err)
+	}
+
+	diff := &models.ConnectionDiff{
+		SourceID:      src.ID,
+		DestinationID: dst.ID,
+		Types:         make(map[string]models.NameDiff),
+	}
+	want := selectTypes(types)
+	for _, rt := range previewOrder {
+		if want.has(rt) {
+			diff.Types[rt] = diffNames(namesForType(srcData, rt), namesForType(dstData, rt))
+		}
+	}
+	return diff, nil
+}
+
+// namesForType returns the set of names exported for a resource type. Hosts
+// and groups are qualified with their inventory name ("inventory/host"),
+// since the same name may appear in several inventories.
+func namesForType(data *ExportedData, rt string) map[string]bool {
+	names := make(map[string]bool)
+	var nested map[int][]models.Resource
+	switch rt {
+	case "hosts":
+		nested = data.Hosts
+	case "groups":
+		nested = data.Groups
+	default:
+		for _, r := range dataForType(data, rt) {
+			names[resourceName(r)] = true
+		}
+		return names
+	}
+	for _, inv := range data.Inventories {
+		for _, r := range nested[resourceID(inv)] {
+			names[resourceName(inv)+"/"+resourceName(r)] = true
+		}
+	}
+	return names
+}
+
+// diffNames splits two name sets into sorted only-in-source,
+// only-in-destination and in-both lists.
+func diffNames(src, dst map[string]bool) models.NameDiff {
+	d := models.NameDiff{
+		OnlyInSource:      []string{},
+		OnlyInDestination: []string{},
+		InBoth:            []string{},
+	}
+	for name := range src {
+		if dst[name] {
+			d.InBoth = append(d.InBoth, name)
+		} else {
+			d.OnlyInSource = append(d.OnlyInSource, name)
+		}
+	}
+	for name := range dst {
+		if !src[name] {
+			d.OnlyInDestination = append(d.OnlyInDestination, name)
+		}
+	}
+	sort.Strings(d.OnlyInSource)
+	sort.Strings(d.OnlyInDestination)
+	sort.Strings(d.InBoth)
+	return d
+}
+
+// findNamed looks up a single resource by name (or username for users).
+func findNamed(client *platform.Client, prefix, typeName, name string) (models.Resource, error) {
+	if typeName == "users" {
+		return client.FindByUsername(prefix+"users/", name)
+	}
+	return client.FindByName(prefix+typeName+"/", name)
+}
+
+// diffResources compares two objects of the same type and returns the
+// migration-relevant fields whose values differ, sorted by field name.
+func diffResources(src, dst models.Resource, typeName string) []models.FieldDiff {
+	var diffs []models.FieldDiff
+	for _, field := range diffFields[typeName] {
+		if field == "extra_vars" && reflect.DeepEqual(varsValue(src[field]), varsValue(dst[field])) {
+			continue
+		}
+		if !reflect.DeepEqual(src[field], dst[field]) {
+			diffs = append(diffs, models.FieldDiff{Field: field, Source: src[field], Destination: dst[field]})
+		}
+	}
+	for _, section := range diffRefs[typeName] {
+		srcName, _ := summaryField(src, section, "name").(string)
+		dstName, _ := summaryField(dst, section, "name").(string)
+		if srcName != dstName {
+			diffs = append(diffs, models.FieldDiff{Field: section, Source: srcName, Destination: dstName})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+	return diffs
+}
@@ -0,0 +1,116 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestDiffResources(t *testing.T) {
+	src := models.Resource{
+		"id":        float64(10),
+		"name":      "Deploy",
+		"playbook":  "deploy.yml",
+		"forks":     float64(5),
+		"verbosity": float64(0),
+		"summary_fields": map[string]interface{}{
+			"project":   map[string]interface{}{"name": "Playbooks"},
+			"inventory": map[string]interface{}{"name": "Prod"},
+		},
+	}
+	dst := models.Resource{
+		"id":        float64(99),
+		"name":      "Deploy",
+		"playbook":  "site.yml",
+		"forks":     float64(5),
+		"verbosity": float64(0),
+		"summary_fields": map[string]interface{}{
+			"project":   map[string]interface{}{"name": "Playbooks"},
+			"inventory": map[string]interface{}{"name": "Staging"},
+		},
+	}
+
+	diffs := diffResources(src, dst, "job_templates")
+	if len(diffs) != 2 {
+		t.Fatalf("got %d diffs, want 2: %+v", len(diffs), diffs)
+	}
+	if diffs[0].Field != "inventory" || diffs[0].Source != "Prod" || diffs[0].Destination != "Staging" {
+		t.Errorf("diffs[0] = %+v, want inventory Prod → Staging", diffs[0])
+	}
+	if diffs[1].Field != "playbook" || diffs[1].Source != "deploy.yml" || diffs[1].Destination != "site.yml" {
+		t.Errorf("diffs[1] = %+v, want playbook deploy.yml → site.yml", diffs[1])
+	}
+}
+
+func TestDiffResources_IgnoresIDs(t *testing.T) {
+	src := models.Resource{"id": float64(1), "name": "Ops", "description": "x"}
+	dst := models.Resource{"id": float64(2), "name": "Ops", "description": "x"}
+	if diffs := diffResources(src, dst, "organizations"); len(diffs) != 0 {
+		t.Errorf("expected no diffs, got %+v", diffs)
+	}
+}
+
+// namedServer fakes an instance whose list endpoints return objects with the
+// given names. Every other list is empty.
+func namedServer(names map[string][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := []map[string]interface{}{}
+		for i, name := range names[r.URL.Path] {
+			results = append(results, map[string]interface{}{"id": i + 1, "name": name})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count": len(results), "next": nil, "results": results,
+		})
+	}))
+}
+
+func TestDiffResources_ComparesParsedExtraVars(t *testing.T) {
+	src := models.Resource{"name": "Deploy", "extra_vars": `{"env": "prod", "debug": true}`}
+	dst := models.Resource{"name": "Deploy", "extra_vars": "---\nenv: prod\ndebug: yes"}
+	if diffs := diffResources(src, dst, "job_templates"); len(diffs) != 0 {
+		t.Errorf("expected no diffs, got %+v", diffs)
+	}
+	dst["extra_vars"] = "---\nenv: staging\ndebug: yes"
+	if diffs := diffResources(src, dst, "job_templates"); len(diffs) != 1 || diffs[0].Field != "extra_vars" {
+		t.Errorf("diffs = %+v, want extra_vars", diffs)
+	}
+}
+
+func TestDiffConnections(t *testing.T) {
+	src := namedServer(map[string][]string{
+		"/api/v2/organizations/": {"Default", "Eng", "Ops"},
+		"/api/v2/job_templates/": {"Deploy", "Backup"},
+	})
+	defer src.Close()
+	dst := namedServer(map[string][]string{
+		"/api/v2/organizations/": {"Default", "Eng", "Sales"},
+		"/api/v2/job_templates/": {"Deploy"},
+	})
+	defer dst.Close()
+
+	diff, err := DiffConnections(context.Background(), newTestConnection(t, src), newTestConnection(t, dst),
+		[]string{"organizations", "job_templates"}, func(string) {})
+	if err != nil {
+		t.Fatalf("DiffConnections returned error: %v", err)
+	}
+
+	want := map[string]models.NameDiff{
+		// "Default" is skipped by export on both sides.
+		"organizations": {OnlyInSource: []string{"Ops"}, OnlyInDestination: []string{"Sales"}, InBoth: []string{"Eng"}},
+		"job_templates": {OnlyInSource: []string{"Backup"}, OnlyInDestination: []string{}, InBoth: []string{"Deploy"}},
+		"projects":      {OnlyInSource: []string{}, OnlyInDestination: []string{}, InBoth: []string{}},
+	}
+	for rt, w := range want {
+		if got := diff.Types[rt]; !reflect.DeepEqual(got, w) {
+			t.Errorf("%s = %+v, want %+v", rt, got, w)
+		}
+	}
+	if _, ok := diff.Types["schedules"]; ok {
+		t.Error("schedules compared but not selected")
+	}
+}
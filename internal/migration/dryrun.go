@@ -0,0 +1,128 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// referenceFields are payload fields holding the destination ID of another
+// resource. A zero value means the reference was not resolved.
+var referenceFields = []string{
+	"organization", "project", "inventory", "credential", "credential_type",
+	"execution_environment", "default_environment", "source_project",
+	"source_credential", "unified_job_template", "webhook_credential",
+}
+
+// requiredReferences lists, per endpoint, the references the destination
+// rejects a resource without.
+var requiredReferences = map[string][]string{
+	"inventories":             {"organization"},
+	"constructed_inventories": {"organization"},
+	"projects":                {"organization"},
+	"teams":                   {"organization"},
+	"credentials":             {"credential_type"},
+	"job_templates":           {"project"},
+	"notification_templates":  {"organization"},
+	"workflow_job_templates":  {"organization"},
+}
+
+// dryRunMask replaces credential input values in dry-run payloads.
+const dryRunMask = "$encrypted$"
+
+// DryRun goes through the same steps as Run against dst but sends nothing
+// that would change it: the body of every write is logged instead, and
+// references that did not resolve to a destination ID are reported as
+// warnings. Resources that would be created are given made-up IDs, which
+// appear in the log in place of real ones.
+func DryRun(ctx context.Context, dst *models.Connection, data *ExportedData, preview *models.MigrationPreview, opts ImportOptions, report func(completed, total int), logger func(string)) error {
+	var mu sync.Mutex // hosts are written from several workers
+	dstClient := platform.NewClient(dst).WithContext(ctx).WithDryRun(func(method, p string, payload map[string]interface{}) {
+		lines := dryRunLines(method, p, payload)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, line := range lines {
+			logger(line)
+		}
+	})
+	dstPrefix := apiPrefix(dst)
+
+	logger("=== Starting dry run against " + dst.Name + " ===")
+	logger("Nothing will be written to the destination.")
+	logger("")
+	opts.varsFormat = varsFormat(dst)
+
+	return importAll(ctx, dstClient, dstPrefix, data, preview, opts, NewResumeState().ids, report, logger)
+}
+
+// dryRunLines returns the log lines for a write a dry run did not send: the
+// request with its body, then a warning per unresolved reference.
+func dryRunLines(method, p string, payload map[string]interface{}) []string {
+	maskInputs(payload)
+	body, _ := json.Marshal(payload)
+	lines := []string{fmt.Sprintf("  DRY RUN: %s %s %s", method, p, body)}
+	if method != "POST" && method != "PATCH" {
+		return lines
+	}
+
+	label := p
+	if name := stringField(payload, "name"); name != "" {
+		label = name
+	}
+	var missing []string
+	required, known := requiredReferences[path.Base(strings.TrimSuffix(p, "/"))]
+	if known && method == "POST" && !isSubresource(p) {
+		for _, f := range required {
+			if _, ok := payload[f]; !ok {
+				missing = append(missing, f)
+			}
+		}
+	}
+	for _, f := range referenceFields {
+		if v, ok := payload[f]; ok && isZeroID(v) {
+			missing = append(missing, f)
+		}
+	}
+	for _, f := range missing {
+		lines = append(lines, fmt.Sprintf("  WARNING: %s: unresolved %s", label, f))
+	}
+	return lines
+}
+
+// isSubresource reports whether p lies below a resource ID, such as
+// job_templates/5/credentials/, rather than being a top-level list.
+func isSubresource(p string) bool {
+	for _, seg := range strings.Split(p, "/") {
+		if _, err := strconv.Atoi(seg); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// maskInputs hides the credential input values of payload, which may hold
+// secrets that job logs do not know to redact.
+func maskInputs(payload map[string]interface{}) {
+	inputs, ok := payload["inputs"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range inputs {
+		if s, ok := v.(string); ok && s != "" {
+			inputs[k] = dryRunMask
+		}
+	}
+}
+
+// isZeroID reports whether v, a decoded JSON reference, is the zero ID.
+func isZeroID(v interface{}) bool {
+	n, ok := v.(float64)
+	return ok && n == 0
+}
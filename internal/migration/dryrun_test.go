@@ -0,0 +1,111 @@
+package migration
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestDryRun_SendsNoWrites(t *testing.T) {
+	srv := &postRecorder{posts: make(map[string][]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	orgRef := func(name string) map[string]interface{} {
+		return map[string]interface{}{"organization": map[string]interface{}{"name": name}}
+	}
+	data := newExportedData()
+	data.Organizations = []models.Resource{{"id": float64(1), "name": "Default"}}
+	data.Inventories = []models.Resource{
+		{"id": float64(2), "name": "Prod", "summary_fields": orgRef("Default")},
+		{"id": float64(3), "name": "Edge", "summary_fields": orgRef("Gone")},
+	}
+	data.Hosts[2] = []models.Resource{{"id": float64(4), "name": "web1", "variables": ""}}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{
+		"organizations": {{Name: "Default", Action: "skip", DestID: 7}},
+	}}
+
+	var mu sync.Mutex
+	var logs []string
+	err := DryRun(context.Background(), newTestConnection(t, ts), data, preview, ImportOptions{}, nil, func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, s)
+	})
+	if err != nil {
+		t.Fatalf("DryRun returned error: %v", err)
+	}
+
+	if len(srv.posts) != 0 {
+		t.Errorf("dry run sent writes: %v", srv.posts)
+	}
+	want := []string{
+		`  DRY RUN: POST /api/v2/inventories/ {"description":"","name":"Prod","organization":7,"variables":""}`,
+		`  DRY RUN: POST /api/v2/inventories/ {"description":"","name":"Edge","organization":0,"variables":""}`,
+		"  WARNING: Edge: unresolved organization",
+	}
+	for _, line := range want {
+		if !containsLine(logs, line) {
+			t.Errorf("log is missing %q:\n%s", line, strings.Join(logs, "\n"))
+		}
+	}
+	if containsLine(logs, "  WARNING: Prod: unresolved organization") {
+		t.Error("resolved organization reported as unresolved")
+	}
+	var hostLogged bool
+	for _, l := range logs {
+		if strings.HasPrefix(l, "  DRY RUN: POST /api/v2/inventories/1000000001/hosts/ ") && strings.Contains(l, `"name":"web1"`) {
+			hostLogged = true
+		}
+	}
+	if !hostLogged {
+		t.Errorf("host creation under the made-up inventory ID not logged:\n%s", strings.Join(logs, "\n"))
+	}
+}
+
+func TestDryRunLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		payload map[string]interface{}
+		want    []string
+	}{
+		{
+			name:    "job template without project",
+			method:  "POST",
+			path:    "/api/v2/job_templates/",
+			payload: map[string]interface{}{"name": "Deploy", "inventory": float64(3)},
+			want: []string{
+				`  DRY RUN: POST /api/v2/job_templates/ {"inventory":3,"name":"Deploy"}`,
+				"  WARNING: Deploy: unresolved project",
+			},
+		},
+		{
+			name:    "credential inputs are masked",
+			method:  "POST",
+			path:    "/api/v2/credentials/",
+			payload: map[string]interface{}{"name": "SSH", "credential_type": float64(1), "inputs": map[string]interface{}{"password": "hunter2", "username": ""}},
+			want:    []string{`  DRY RUN: POST /api/v2/credentials/ {"credential_type":1,"inputs":{"password":"$encrypted$","username":""},"name":"SSH"}`},
+		},
+		{
+			name:    "association",
+			method:  "POST",
+			path:    "/api/v2/job_templates/5/credentials/",
+			payload: map[string]interface{}{"id": float64(9)},
+			want:    []string{`  DRY RUN: POST /api/v2/job_templates/5/credentials/ {"id":9}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dryRunLines(tt.method, tt.path, tt.payload)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// exportExecutionEnvironments fetches the custom execution environments.
+// Managed EEs and the platform defaults skipped by cleanup already exist on
+// every destination and are left out.
+func exportExecutionEnvironments(client *platform.Client, prefix string, logger func(string)) ([]models.Resource, error) {
+	logger("Exporting execution_environments...")
+	all, err := client.GetAll(prefix + "execution_environments/")
+	if err != nil {
+		return nil, fmt.Errorf("execution_environments: %w", err)
+	}
+	defaults := make(map[string]bool)
+	for _, name := range platform.CleanupExclusions()["aap"]["execution_environments"] {
+		defaults[name] = true
+	}
+	var custom []models.Resource
+	for _, ee := range all {
+		if boolField(ee, "managed") || defaults[resourceName(ee)] {
+			continue
+		}
+		custom = append(custom, ee)
+	}
+	logger(fmt.Sprintf("  %d execution_environments (skipped %d managed/defaults)", len(custom), len(all)-len(custom)))
+	return custom, nil
+}
+
+// importExecutionEnvironments recreates custom EEs in their organizations
+// with the same image and pull policy. Every EE already on the destination
+// is recorded by name first, so job templates using a default EE resolve too.
+func importExecutionEnvironments(dst *platform.Client, prefix string, data *ExportedData, preview *models.MigrationPreview, exclude map[string][]string, tf *Transforms, ids *idMap, progress *progressTracker, logger func(string)) {
+	existing, err := dst.GetAll(prefix + "execution_environments/")
+	if err != nil {
+		logger(fmt.Sprintf("  WARNING: listing execution environments: %v", err))
+	}
+	for _, ee := range existing {
+		ids.ees[resourceName(ee)] = resourceID(ee)
+	}
+
+	for _, ee := range data.ExecutionEnvironments {
+		progress.step()
+		name := resourceName(ee)
+		if isExcluded(exclude, "execution_environments", name) {
+			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
+			continue
+		}
+		if ids.migrated(ids.ees, name, logger) {
+			continue
+		}
+		action, destID := actionFor(preview, "execution_environments", name)
+		if action != "create" {
+			ids.ees[name] = destID
+			logger(fmt.Sprintf("  SKIP (exists): %s", name))
+			continue
+		}
+
+		payload := map[string]interface{}{
+			"name":        name,
+			"description": stringField(ee, "description"),
+			"image":       stringField(ee, "image"),
+			"pull":        stringField(ee, "pull"),
+		}
+		if orgID := ids.orgs[extractOrgName(ee)]; orgID != 0 {
+			payload["organization"] = orgID
+		}
+		if credID := ids.creds[extractSCMCredName(ee)]; credID != 0 {
+			payload["credential"] = credID
+		}
+
+		tf.apply("execution_environments", name, payload, logger)
+		id, err := createResource(dst, prefix+"execution_environments/", payload)
+		if err != nil {
+			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
+			continue
+		}
+		ids.ees[name] = id
+		logger(fmt.Sprintf("  CREATED: %s (ID %d)", name, id))
+	}
+}
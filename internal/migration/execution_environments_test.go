@@ -0,0 +1,123 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// eeServer fakes a destination that already has the "Default execution
+// environment" (2). New EEs are created as ID 8 and job templates as ID 5;
+// POST bodies are recorded by path.
+type eeServer struct {
+	mu    sync.Mutex
+	posts map[string]map[string]interface{}
+}
+
+func (s *eeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/api/v2/execution_environments/":
+		w.Write([]byte(`{"count":1,"next":null,"results":[{"id":2,"name":"Default execution environment"}]}`))
+	case r.Method == "GET":
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	default:
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		s.posts[r.URL.Path] = body
+		w.WriteHeader(http.StatusCreated)
+		switch r.URL.Path {
+		case "/api/v2/execution_environments/":
+			w.Write([]byte(`{"id":8}`))
+		case "/api/v2/job_templates/":
+			w.Write([]byte(`{"id":5}`))
+		default:
+			w.Write([]byte(`{"id":1}`))
+		}
+	}
+}
+
+func eeRef(name string) map[string]interface{} {
+	return map[string]interface{}{"execution_environment": map[string]interface{}{"name": name}}
+}
+
+func TestImportAll_JobTemplateCustomExecutionEnvironment(t *testing.T) {
+	srv := &eeServer{posts: make(map[string]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := &ExportedData{
+		ExecutionEnvironments: []models.Resource{{
+			"id": float64(30), "name": "Network EE", "image": "quay.io/acme/network-ee:2.1", "pull": "always",
+		}},
+		JobTemplates: []models.Resource{{
+			"id": float64(12), "name": "Backup switches", "summary_fields": eeRef("Network EE"),
+		}},
+	}
+	preview := &models.MigrationPreview{}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	ee := srv.posts["/api/v2/execution_environments/"]
+	if ee["image"] != "quay.io/acme/network-ee:2.1" || ee["pull"] != "always" {
+		t.Errorf("created EE = %v, want image and pull policy from source", ee)
+	}
+	if got := srv.posts["/api/v2/job_templates/"]["execution_environment"]; got != float64(8) {
+		t.Errorf("job template execution_environment = %v, want 8", got)
+	}
+	if !containsLine(logs, "  CREATED: Network EE (ID 8)") {
+		t.Errorf("missing CREATED line for the EE in %q", logs)
+	}
+}
+
+func TestImportAll_JobTemplateDefaultExecutionEnvironment(t *testing.T) {
+	srv := &eeServer{posts: make(map[string]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := &ExportedData{
+		JobTemplates: []models.Resource{{
+			"id": float64(12), "name": "Ping", "summary_fields": eeRef("Default execution environment"),
+		}},
+	}
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, &models.MigrationPreview{}, ImportOptions{}, nil, nil,
+		func(string) {})
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+	if _, ok := srv.posts["/api/v2/execution_environments/"]; ok {
+		t.Error("default EE should not be created")
+	}
+	if got := srv.posts["/api/v2/job_templates/"]["execution_environment"]; got != float64(2) {
+		t.Errorf("job template execution_environment = %v, want existing default 2", got)
+	}
+}
+
+func TestExportExecutionEnvironments_SkipsDefaults(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":3,"next":null,"results":[
+			{"id":1,"name":"Control Plane Execution Environment","managed":true},
+			{"id":2,"name":"Default execution environment"},
+			{"id":30,"name":"Network EE"}]}`))
+	}))
+	defer ts.Close()
+
+	ees, err := exportExecutionEnvironments(newTestClient(t, ts), "/api/v2/", func(string) {})
+	if err != nil {
+		t.Fatalf("exportExecutionEnvironments returned error: %v", err)
+	}
+	if len(ees) != 1 || resourceName(ees[0]) != "Network EE" {
+		t.Errorf("exported %v, want only Network EE", ees)
+	}
+}
@@ -1,7 +1,9 @@
 package migration
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
@@ -14,7 +16,7 @@ var skipNames = map[string]map[string]bool{
 	"credentials":   {"Demo Credential": true, "Ansible Galaxy": true},
 	"projects":      {"Demo Project": true},
 	"inventories":   {"Demo Inventory": true},
-	"job_templates":  {"Demo Job Template": true},
+	"job_templates": {"Demo Job Template": true},
 }
 
 // DefaultExclusions returns the default resource names skipped during migration export.
@@ -28,129 +30,205 @@ func DefaultExclusions() map[string][]string {
 	return result
 }
 
+// newExportedData returns an ExportedData with every map allocated.
+func newExportedData() *ExportedData {
+	return &ExportedData{
+		ManagedCredTypeKinds:  make(map[string]string),
+		Hosts:                 make(map[int][]models.Resource),
+		Groups:                make(map[int][]models.Resource),
+		GroupHosts:            make(map[int][]int),
+		InventorySources:      make(map[int][]models.Resource),
+		InputInventories:      make(map[int][]string),
+		Surveys:               make(map[int]models.Resource),
+		Labels:                make(map[int][]models.Resource),
+		WorkflowLabels:        make(map[int][]models.Resource),
+		Notifications:         make(map[int]map[string][]int),
+		WorkflowNotifications: make(map[int]map[string][]int),
+		InstanceGroups:        make(map[string]map[int][]string),
+		GalaxyCredentials:     make(map[int][]string),
+		CredInputSources:      make(map[int][]models.Resource),
+		WorkflowNodes:         make(map[int][]models.Resource),
+		NodeCredentials:       make(map[int][]string),
+		OrgUsers:              make(map[int][]string),
+		TeamUsers:             make(map[int][]string),
+		Disabled:              make(map[string]int),
+	}
+}
+
 // exportAll fetches all migratable resource types from the source into memory.
-func exportAll(client *platform.Client, prefix string, logger func(string)) (*ExportedData, error) {
-	data := &ExportedData{
-		Hosts:         make(map[int][]models.Resource),
-		Groups:        make(map[int][]models.Resource),
-		GroupHosts:    make(map[int][]int),
-		Surveys:       make(map[int]models.Resource),
-		WorkflowNodes: make(map[int][]models.Resource),
-		OrgUsers:      make(map[int][]string),
-		TeamUsers:     make(map[int][]string),
+func exportAll(ctx context.Context, client *platform.Client, prefix string, opts ExportOptions, logger func(string)) (*ExportedData, error) {
+	data := newExportedData()
+	want := selectTypes(opts.Types)
+	data.Types = want.list()
+	if want != nil {
+		logger("Selected types: " + strings.Join(data.Types, ", "))
 	}
 
 	var err error
 
 	// 1. Organizations
-	data.Organizations, err = fetchFiltered(client, prefix+"organizations/", "organizations", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "organizations", logger); err != nil {
 		return nil, err
 	}
+	if want.has("organizations") {
+		data.Organizations, err = fetchFiltered(client, prefix+"organizations/", "organizations", logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// 2. Teams
-	data.Teams, err = fetchFiltered(client, prefix+"teams/", "teams", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "teams", logger); err != nil {
 		return nil, err
 	}
+	if want.has("teams") {
+		data.Teams, err = fetchFiltered(client, prefix+"teams/", "teams", logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// 3. Users
-	data.Users, err = fetchFiltered(client, prefix+"users/", "users", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "users", logger); err != nil {
 		return nil, err
 	}
+	if want.has("users") {
+		data.Users, err = fetchFiltered(client, prefix+"users/", "users", logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// 4. Credential types (custom only — skip managed)
-	logger("Exporting credential_types...")
-	allCredTypes, err := client.GetAll(prefix + "credential_types/")
-	if err != nil {
-		return nil, fmt.Errorf("credential_types: %w", err)
+	if err := exportCancelled(ctx, "credential types", logger); err != nil {
+		return nil, err
 	}
-	for _, ct := range allCredTypes {
-		if boolField(ct, "managed") {
-			continue
+	if want.has("credential_types") {
+		logger("Exporting credential_types...")
+		allCredTypes, err := client.GetAll(prefix + "credential_types/")
+		if err != nil {
+			return nil, fmt.Errorf("credential_types: %w", err)
+		}
+		for _, ct := range allCredTypes {
+			if boolField(ct, "managed") {
+				data.ManagedCredTypeKinds[resourceName(ct)] = stringField(ct, "kind")
+				continue
+			}
+			data.CredentialTypes = append(data.CredentialTypes, ct)
 		}
-		data.CredentialTypes = append(data.CredentialTypes, ct)
+		logger(fmt.Sprintf("  %d custom credential types", len(data.CredentialTypes)))
 	}
-	logger(fmt.Sprintf("  %d custom credential types", len(data.CredentialTypes)))
 
 	// 5. Credentials
-	data.Credentials, err = fetchFiltered(client, prefix+"credentials/", "credentials", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "credentials", logger); err != nil {
 		return nil, err
 	}
+	if want.has("credentials") {
+		data.Credentials, err = fetchFiltered(client, prefix+"credentials/", "credentials", logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// 6. Projects
-	data.Projects, err = fetchFiltered(client, prefix+"projects/", "projects", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "projects", logger); err != nil {
 		return nil, err
 	}
+	if want.has("projects") {
+		data.Projects, err = fetchFiltered(client, prefix+"projects/", "projects", logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// 7. Inventories
-	data.Inventories, err = fetchFiltered(client, prefix+"inventories/", "inventories", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "inventories", logger); err != nil {
 		return nil, err
 	}
-
-	// 8. Hosts and groups per inventory
-	for _, inv := range data.Inventories {
-		invID := resourceID(inv)
-		invName := resourceName(inv)
-
-		hosts, err := client.GetAll(fmt.Sprintf("%sinventories/%d/hosts/", prefix, invID))
+	if want.has("inventories") {
+		data.Inventories, err = fetchFiltered(client, prefix+"inventories/", "inventories", logger)
 		if err != nil {
-			logger(fmt.Sprintf("  WARNING: failed to get hosts for inventory %s: %v", invName, err))
-			continue
+			return nil, err
 		}
-		data.Hosts[invID] = hosts
+	}
 
-		groups, err := client.GetAll(fmt.Sprintf("%sinventories/%d/groups/", prefix, invID))
-		if err != nil {
-			logger(fmt.Sprintf("  WARNING: failed to get groups for inventory %s: %v", invName, err))
-			continue
-		}
-		data.Groups[invID] = groups
+	// 7b. Constructed inventory settings and input inventories
+	if err := exportCancelled(ctx, "constructed inventories", logger); err != nil {
+		return nil, err
+	}
+	if want.has("inventories") {
+		exportConstructedInventories(client, prefix, data, logger)
+	}
 
-		// Group-host associations
-		for _, g := range groups {
-			gID := resourceID(g)
-			gHosts, err := client.GetAll(fmt.Sprintf("%sgroups/%d/hosts/", prefix, gID))
-			if err != nil {
-				continue
-			}
-			for _, h := range gHosts {
-				data.GroupHosts[gID] = append(data.GroupHosts[gID], resourceID(h))
-			}
+	// 8. Hosts and groups per inventory
+	if err := exportCancelled(ctx, "hosts and groups", logger); err != nil {
+		return nil, err
+	}
+	if want.has("hosts") {
+		if err := exportInventoryContents(ctx, client, prefix, opts, want.has("groups"), data, logger); err != nil {
+			return nil, err
 		}
-
-		logger(fmt.Sprintf("  Inventory %s: %d hosts, %d groups", invName, len(hosts), len(groups)))
 	}
 
 	// 9. Job templates
-	data.JobTemplates, err = fetchFiltered(client, prefix+"job_templates/", "job_templates", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "job templates", logger); err != nil {
 		return nil, err
 	}
+	if want.has("job_templates") {
+		data.JobTemplates, err = fetchFiltered(client, prefix+"job_templates/", "job_templates", logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// 10. Surveys for JTs
+	// 10. Surveys, labels and notification attachments for JTs
+	if err := exportCancelled(ctx, "job template surveys and labels", logger); err != nil {
+		return nil, err
+	}
 	for _, jt := range data.JobTemplates {
+		if err := exportCancelled(ctx, "job template surveys and labels", logger); err != nil {
+			return nil, err
+		}
+		jtID := resourceID(jt)
 		if boolField(jt, "survey_enabled") {
-			jtID := resourceID(jt)
 			var survey models.Resource
 			if err := client.GetJSON(fmt.Sprintf("%sjob_templates/%d/survey_spec/", prefix, jtID), nil, &survey); err == nil && survey != nil {
 				data.Surveys[jtID] = survey
 			}
 		}
+		labels, err := client.GetAll(fmt.Sprintf("%sjob_templates/%d/labels/", prefix, jtID))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get labels for job template %s: %v", resourceName(jt), err))
+		} else if len(labels) > 0 {
+			data.Labels[jtID] = labels
+		}
+		if !want.has("notification_templates") {
+			continue
+		}
+		if attached := fetchNotificationAttachments(client, fmt.Sprintf("%sjob_templates/%d/", prefix, jtID)); attached != nil {
+			data.Notifications[jtID] = attached
+		}
 	}
 
 	// 11. Workflow job templates
-	data.WorkflowJTs, err = fetchFiltered(client, prefix+"workflow_job_templates/", "workflow_job_templates", logger)
-	if err != nil {
+	if err := exportCancelled(ctx, "workflow job templates", logger); err != nil {
 		return nil, err
 	}
+	if want.has("workflow_job_templates") {
+		data.WorkflowJTs, err = fetchFiltered(client, prefix+"workflow_job_templates/", "workflow_job_templates", logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// 12. Workflow nodes and surveys
+	// 12. Workflow nodes, surveys, labels and notification attachments
+	if err := exportCancelled(ctx, "workflow nodes", logger); err != nil {
+		return nil, err
+	}
 	for _, wf := range data.WorkflowJTs {
+		if err := exportCancelled(ctx, "workflow nodes", logger); err != nil {
+			return nil, err
+		}
 		wfID := resourceID(wf)
 		wfName := resourceName(wf)
 
@@ -160,6 +238,7 @@ func exportAll(client *platform.Client, prefix string, logger func(string)) (*Ex
 			continue
 		}
 		data.WorkflowNodes[wfID] = nodes
+		exportNodeCredentials(client, prefix, wfName, nodes, data, logger)
 		logger(fmt.Sprintf("  Workflow %s: %d nodes", wfName, len(nodes)))
 
 		if boolField(wf, "survey_enabled") {
@@ -168,61 +247,306 @@ func exportAll(client *platform.Client, prefix string, logger func(string)) (*Ex
 				data.Surveys[wfID] = survey
 			}
 		}
+
+		labels, err := client.GetAll(fmt.Sprintf("%sworkflow_job_templates/%d/labels/", prefix, wfID))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get labels for workflow %s: %v", wfName, err))
+		} else if len(labels) > 0 {
+			data.WorkflowLabels[wfID] = labels
+		}
+		if !want.has("notification_templates") {
+			continue
+		}
+		if attached := fetchNotificationAttachments(client, fmt.Sprintf("%sworkflow_job_templates/%d/", prefix, wfID)); attached != nil {
+			data.WorkflowNotifications[wfID] = attached
+		}
 	}
 
 	// 13. Schedules (skip system-managed ones)
-	logger("Exporting schedules...")
-	allSchedules, err := client.GetAll(prefix + "schedules/")
-	if err != nil {
-		return nil, fmt.Errorf("schedules: %w", err)
+	if err := exportCancelled(ctx, "schedules", logger); err != nil {
+		return nil, err
 	}
-	// Build set of exported JT/WFJT names for schedule filtering
-	exportedJTs := make(map[string]bool)
-	for _, jt := range data.JobTemplates {
-		exportedJTs[resourceName(jt)] = true
+	if want.has("schedules") {
+		logger("Exporting schedules...")
+		allSchedules, err := client.GetAll(prefix + "schedules/")
+		if err != nil {
+			return nil, fmt.Errorf("schedules: %w", err)
+		}
+		// Build set of exported JT/WFJT names for schedule filtering
+		exportedJTs := make(map[string]bool)
+		for _, jt := range data.JobTemplates {
+			exportedJTs[resourceName(jt)] = true
+		}
+		for _, wf := range data.WorkflowJTs {
+			exportedJTs[resourceName(wf)] = true
+		}
+		for _, sched := range allSchedules {
+			parentName := extractUnifiedJTName(sched)
+			if parentName == "" || !exportedJTs[parentName] {
+				continue
+			}
+			if opts.ExcludeDisabled && isDisabled(sched) {
+				data.Disabled["schedules"]++
+				continue
+			}
+			data.Schedules = append(data.Schedules, sched)
+		}
+		logger(fmt.Sprintf("  %d schedules", len(data.Schedules)))
 	}
-	for _, wf := range data.WorkflowJTs {
-		exportedJTs[resourceName(wf)] = true
+	if opts.ExcludeDisabled {
+		logger(fmt.Sprintf("  Excluded disabled: %d hosts, %d schedules", data.Disabled["hosts"], data.Disabled["schedules"]))
 	}
-	for _, sched := range allSchedules {
-		parentName := extractUnifiedJTName(sched)
-		if parentName == "" || !exportedJTs[parentName] {
-			continue
+
+	// 14. Org-user and team-user associations
+	if err := exportCancelled(ctx, "user associations", logger); err != nil {
+		return nil, err
+	}
+	if want.has("users") {
+		logger("Exporting user associations...")
+		for _, org := range data.Organizations {
+			orgID := resourceID(org)
+			users, err := client.GetAll(fmt.Sprintf("%sorganizations/%d/users/", prefix, orgID))
+			if err != nil {
+				continue
+			}
+			for _, u := range users {
+				username := stringField(u, "username")
+				if username != "" && username != "admin" {
+					data.OrgUsers[orgID] = append(data.OrgUsers[orgID], username)
+				}
+			}
+		}
+		for _, team := range data.Teams {
+			teamID := resourceID(team)
+			users, err := client.GetAll(fmt.Sprintf("%steams/%d/users/", prefix, teamID))
+			if err != nil {
+				continue
+			}
+			for _, u := range users {
+				username := stringField(u, "username")
+				if username != "" && username != "admin" {
+					data.TeamUsers[teamID] = append(data.TeamUsers[teamID], username)
+				}
+			}
 		}
-		data.Schedules = append(data.Schedules, sched)
 	}
-	logger(fmt.Sprintf("  %d schedules", len(data.Schedules)))
 
-	// 14. Org-user and team-user associations
-	logger("Exporting user associations...")
-	for _, org := range data.Organizations {
-		orgID := resourceID(org)
-		users, err := client.GetAll(fmt.Sprintf("%sorganizations/%d/users/", prefix, orgID))
+	// 15. Notification templates (secrets blanked — the API only returns them encrypted)
+	if err := exportCancelled(ctx, "notification templates", logger); err != nil {
+		return nil, err
+	}
+	if want.has("notification_templates") {
+		data.NotificationTemplates, err = fetchFiltered(client, prefix+"notification_templates/", "notification_templates", logger)
 		if err != nil {
-			continue
+			return nil, err
 		}
-		for _, u := range users {
-			username := stringField(u, "username")
-			if username != "" && username != "admin" {
-				data.OrgUsers[orgID] = append(data.OrgUsers[orgID], username)
+		for _, nt := range data.NotificationTemplates {
+			if redacted := redactNotificationConfig(nt); len(redacted) > 0 {
+				logger(fmt.Sprintf("  %s: secrets removed (%s)", resourceName(nt), strings.Join(redacted, ", ")))
 			}
 		}
 	}
-	for _, team := range data.Teams {
-		teamID := resourceID(team)
-		users, err := client.GetAll(fmt.Sprintf("%steams/%d/users/", prefix, teamID))
+
+	// 16. Instance group assignments
+	if err := exportCancelled(ctx, "instance groups", logger); err != nil {
+		return nil, err
+	}
+	exportInstanceGroups(client, prefix, data, logger)
+
+	// 17. Execution environments (custom only — skip managed and platform defaults)
+	if err := exportCancelled(ctx, "execution environments", logger); err != nil {
+		return nil, err
+	}
+	if want.has("execution_environments") {
+		data.ExecutionEnvironments, err = exportExecutionEnvironments(client, prefix, logger)
 		if err != nil {
-			continue
+			return nil, err
 		}
-		for _, u := range users {
-			username := stringField(u, "username")
-			if username != "" && username != "admin" {
-				data.TeamUsers[teamID] = append(data.TeamUsers[teamID], username)
+	}
+
+	// 17b. OAuth2 applications (client secrets dropped)
+	if err := exportCancelled(ctx, "applications", logger); err != nil {
+		return nil, err
+	}
+	if want.has("applications") {
+		data.Applications = exportApplications(client, prefix, logger)
+	}
+
+	// 18. Organization galaxy credentials
+	if err := exportCancelled(ctx, "galaxy credentials", logger); err != nil {
+		return nil, err
+	}
+	if want.has("organizations") {
+		exportGalaxyCredentials(client, prefix, data, logger)
+	}
+
+	// 18b. Credential input sources (external secret lookups)
+	if err := exportCancelled(ctx, "credential input sources", logger); err != nil {
+		return nil, err
+	}
+	if want.has("credentials") {
+		exportCredentialInputSources(client, prefix, data, logger)
+	}
+
+	// 19. Inventory sources
+	if err := exportCancelled(ctx, "inventory sources", logger); err != nil {
+		return nil, err
+	}
+	if want.has("inventories") {
+		exportInventorySources(client, prefix, data, logger)
+	}
+
+	// 20. Team and user role assignments
+	if err := exportCancelled(ctx, "role assignments", logger); err != nil {
+		return nil, err
+	}
+	if want.has("teams") || want.has("users") {
+		exportRoleAssignments(client, prefix, data, logger)
+	}
+
+	return data, nil
+}
+
+// exportCancelled returns ctx's error, logging where the export stopped,
+// once the export has been cancelled.
+func exportCancelled(ctx context.Context, step string, logger func(string)) error {
+	if err := ctx.Err(); err != nil {
+		logger("CANCELLED: export stopped at " + step)
+		return err
+	}
+	return nil
+}
+
+// inventoryContents is what exportInventoryContents fetches for one inventory.
+type inventoryContents struct {
+	hosts      []models.Resource
+	groups     []models.Resource
+	groupHosts map[int][]int
+	dropped    int
+	hostsErr   error
+	groupsErr  error
+}
+
+// exportInventoryContents fetches hosts, groups and group memberships for
+// every exported inventory using a bounded pool of opts.Concurrency workers.
+// Groups are left out unless withGroups is set. Results are handled in
+// inventory order, so output is the same as a sequential export, and at most
+// opts.Concurrency inventories are held at once before being merged into data
+// or, when set, passed to opts.inventorySink.
+func exportInventoryContents(ctx context.Context, client *platform.Client, prefix string, opts ExportOptions, withGroups bool, data *ExportedData, logger func(string)) error {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultExportConcurrency
+	}
+
+	results := make([]inventoryContents, len(data.Inventories))
+	done := make([]chan struct{}, len(data.Inventories))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	slots := make(chan struct{}, workers) // released once a result is handled
+	go func() {
+		for i := range data.Inventories {
+			slots <- struct{}{}
+			// Constructed inventories generate their hosts and groups
+			if ctx.Err() != nil || isConstructed(data.Inventories[i]) {
+				close(done[i])
+				continue
 			}
+			go func(i int) {
+				results[i] = fetchInventoryContents(ctx, client, prefix, resourceID(data.Inventories[i]), opts, withGroups)
+				close(done[i])
+			}(i)
+		}
+	}()
+
+	var sinkErr error
+	for i, inv := range data.Inventories {
+		<-done[i]
+		if ctx.Err() == nil && sinkErr == nil && !isConstructed(inv) {
+			sinkErr = storeInventoryContents(inv, results[i], opts, data, logger)
 		}
+		results[i] = inventoryContents{}
+		<-slots
 	}
+	if err := exportCancelled(ctx, "hosts and groups", logger); err != nil {
+		return err
+	}
+	return sinkErr
+}
 
-	return data, nil
+// storeInventoryContents merges one inventory's contents into data, or hands
+// them to opts.inventorySink.
+func storeInventoryContents(inv models.Resource, res inventoryContents, opts ExportOptions, data *ExportedData, logger func(string)) error {
+	invID := resourceID(inv)
+	invName := resourceName(inv)
+
+	if res.hostsErr != nil {
+		logger(fmt.Sprintf("  WARNING: failed to get hosts for inventory %s: %v", invName, res.hostsErr))
+		return nil
+	}
+	data.Disabled["hosts"] += res.dropped
+	if res.groupsErr != nil {
+		logger(fmt.Sprintf("  WARNING: failed to get groups for inventory %s: %v", invName, res.groupsErr))
+		res.groups, res.groupHosts = nil, nil
+	}
+
+	if opts.inventorySink != nil {
+		if err := opts.inventorySink(invID, res); err != nil {
+			return fmt.Errorf("inventory %s: %w", invName, err)
+		}
+	} else {
+		data.Hosts[invID] = res.hosts
+		if res.groupsErr == nil {
+			data.Groups[invID] = res.groups
+		}
+		for gID, hostIDs := range res.groupHosts {
+			data.GroupHosts[gID] = append(data.GroupHosts[gID], hostIDs...)
+		}
+	}
+	if res.groupsErr == nil {
+		logger(fmt.Sprintf("  Inventory %s: %d hosts, %d groups", invName, len(res.hosts), len(res.groups)))
+	}
+	return nil
+}
+
+// fetchInventoryContents fetches the hosts, groups and group-host
+// associations of a single inventory.
+func fetchInventoryContents(ctx context.Context, client *platform.Client, prefix string, invID int, opts ExportOptions, withGroups bool) inventoryContents {
+	var res inventoryContents
+
+	res.hosts, res.hostsErr = client.GetAll(fmt.Sprintf("%sinventories/%d/hosts/", prefix, invID))
+	if res.hostsErr != nil {
+		return res
+	}
+	if opts.ExcludeDisabled {
+		res.hosts, res.dropped = dropDisabled(res.hosts)
+	}
+	if !withGroups {
+		return res
+	}
+
+	res.groups, res.groupsErr = client.GetAll(fmt.Sprintf("%sinventories/%d/groups/", prefix, invID))
+	if res.groupsErr != nil {
+		return res
+	}
+
+	// Group-host associations
+	res.groupHosts = make(map[int][]int)
+	for _, g := range res.groups {
+		if ctx.Err() != nil {
+			return res
+		}
+		gID := resourceID(g)
+		gHosts, err := client.GetAll(fmt.Sprintf("%sgroups/%d/hosts/", prefix, gID))
+		if err != nil {
+			continue
+		}
+		for _, h := range gHosts {
+			res.groupHosts[gID] = append(res.groupHosts[gID], resourceID(h))
+		}
+	}
+	return res
 }
 
 // fetchFiltered fetches all resources of a type and filters out defaults by name.
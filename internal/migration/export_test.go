@@ -0,0 +1,201 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+var (
+	invHostsPath   = regexp.MustCompile(`^/api/v2/inventories/(\d+)/hosts/$`)
+	invGroupsPath  = regexp.MustCompile(`^/api/v2/inventories/(\d+)/groups/$`)
+	groupHostsPath = regexp.MustCompile(`^/api/v2/groups/(\d+)/hosts/$`)
+)
+
+// inventoryServer fakes a source with n inventories. Inventory i has i%5+1
+// hosts (IDs i*100+h) and one group (ID i+10000) containing its first host.
+// Every other list endpoint is empty.
+func inventoryServer(n int) *httptest.Server {
+	list := func(w http.ResponseWriter, results []map[string]interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count": len(results), "next": nil, "results": results,
+		})
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if m := invHostsPath.FindStringSubmatch(path); m != nil {
+			i, _ := strconv.Atoi(m[1])
+			var hosts []map[string]interface{}
+			for h := 0; h < i%5+1; h++ {
+				hosts = append(hosts, map[string]interface{}{"id": i*100 + h, "name": fmt.Sprintf("host-%d-%d", i, h), "enabled": true})
+			}
+			list(w, hosts)
+			return
+		}
+		if m := invGroupsPath.FindStringSubmatch(path); m != nil {
+			i, _ := strconv.Atoi(m[1])
+			list(w, []map[string]interface{}{{"id": i + 10000, "name": fmt.Sprintf("group-%d", i)}})
+			return
+		}
+		if m := groupHostsPath.FindStringSubmatch(path); m != nil {
+			g, _ := strconv.Atoi(m[1])
+			list(w, []map[string]interface{}{{"id": (g - 10000) * 100}})
+			return
+		}
+		if path == "/api/v2/inventories/" {
+			var invs []map[string]interface{}
+			for i := 1; i <= n; i++ {
+				invs = append(invs, map[string]interface{}{"id": i, "name": fmt.Sprintf("inv-%d", i)})
+			}
+			list(w, invs)
+			return
+		}
+		list(w, nil)
+	}))
+}
+
+func TestExportAll_ConcurrentInventories(t *testing.T) {
+	const n = 60
+	ts := inventoryServer(n)
+	defer ts.Close()
+
+	var logs []string
+	data, err := exportAll(context.Background(), newTestClient(t, ts), "/api/v2/", ExportOptions{Concurrency: 8},
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("exportAll returned error: %v", err)
+	}
+
+	if len(data.Hosts) != n || len(data.Groups) != n {
+		t.Fatalf("got hosts for %d and groups for %d inventories, want %d", len(data.Hosts), len(data.Groups), n)
+	}
+	for i := 1; i <= n; i++ {
+		if got := len(data.Hosts[i]); got != i%5+1 {
+			t.Errorf("inventory %d: %d hosts, want %d", i, got, i%5+1)
+		}
+		if got := data.GroupHosts[i+10000]; len(got) != 1 || got[0] != i*100 {
+			t.Errorf("group %d hosts = %v, want [%d]", i+10000, got, i*100)
+		}
+	}
+
+	// Per-inventory log lines must come out in inventory order.
+	next := 1
+	for _, l := range logs {
+		var id, hosts, groups int
+		if _, err := fmt.Sscanf(l, "  Inventory inv-%d: %d hosts, %d groups", &id, &hosts, &groups); err != nil {
+			continue
+		}
+		if id != next {
+			t.Fatalf("inventory log out of order: got inv-%d, want inv-%d", id, next)
+		}
+		next++
+	}
+	if next != n+1 {
+		t.Errorf("logged %d inventories, want %d", next-1, n)
+	}
+}
+
+func TestExportAll_Cancelled(t *testing.T) {
+	ts := inventoryServer(10)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := exportAll(ctx, newTestClient(t, ts), "/api/v2/", ExportOptions{}, func(string) {})
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestExportAll_CancelledBetweenTypes(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	inv := inventoryServer(3)
+	defer inv.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		inv.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logs []string
+	_, err := exportAll(ctx, newTestClient(t, ts), "/api/v2/", ExportOptions{}, func(line string) {
+		logs = append(logs, line)
+		if line == "Exporting credentials..." {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if last := logs[len(logs)-1]; last != "CANCELLED: export stopped at projects" {
+		t.Errorf("last log = %q, want the CANCELLED line", last)
+	}
+	if !requested["/api/v2/credentials/"] {
+		t.Error("credentials were not fetched before the cancellation took effect")
+	}
+	for _, path := range []string{"/api/v2/projects/", "/api/v2/inventories/", "/api/v2/job_templates/"} {
+		if requested[path] {
+			t.Errorf("%s was fetched after cancellation", path)
+		}
+	}
+}
+
+func TestExportAll_SelectedTypes(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data, err := exportAll(context.Background(), newTestClient(t, ts), "/api/v2/",
+		ExportOptions{Types: []string{"organizations", "job_templates"}}, func(string) {})
+	if err != nil {
+		t.Fatalf("exportAll returned error: %v", err)
+	}
+
+	// Job templates pull in their projects, inventories and credentials.
+	for _, path := range []string{"organizations", "job_templates", "projects", "inventories", "credentials", "credential_types"} {
+		if !requested["/api/v2/"+path+"/"] {
+			t.Errorf("%s not exported", path)
+		}
+	}
+	for _, path := range []string{"schedules", "users", "teams", "workflow_job_templates", "notification_templates", "execution_environments"} {
+		if requested["/api/v2/"+path+"/"] {
+			t.Errorf("%s exported but not selected", path)
+		}
+	}
+	if len(data.Types) == 0 || data.Types[0] != "organizations" {
+		t.Errorf("data.Types = %v, want the expanded selection", data.Types)
+	}
+}
+
+func BenchmarkExportAll_Inventories(b *testing.B) {
+	ts := inventoryServer(200)
+	defer ts.Close()
+	client := newTestClient(b, ts)
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := exportAll(context.Background(), client, "/api/v2/", ExportOptions{Concurrency: workers}, func(string) {}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
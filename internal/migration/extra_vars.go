@@ -0,0 +1,161 @@
+package migration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// Formats extra_vars are sent in.
+const (
+	varsYAML = "yaml"
+	varsJSON = "json"
+)
+
+// varsFormat returns the extra_vars format a destination prefers: YAML for
+// AAP, whose UI edits variables as YAML, and JSON for AWX, whose CLI and
+// API write them as JSON.
+func varsFormat(conn *models.Connection) string {
+	if conn.Type == "awx" {
+		return varsJSON
+	}
+	return varsYAML
+}
+
+// normalizeExtraVars parses extra_vars written as JSON or YAML and returns
+// them in format: block-style YAML keeping key order and comments, or JSON,
+// which is passed through unchanged when the source already was JSON.
+// Empty documents, "---", null and {} become "".
+func normalizeExtraVars(raw, format string) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+		return "", err
+	}
+	if len(doc.Content) == 0 {
+		return "", nil
+	}
+	root := doc.Content[0]
+	switch {
+	case root.Kind == yaml.ScalarNode && root.Tag == "!!null":
+		return "", nil
+	case root.Kind != yaml.MappingNode:
+		return "", fmt.Errorf("extra_vars must be a mapping")
+	case len(root.Content) == 0:
+		return "", nil
+	}
+
+	if format == varsJSON {
+		if json.Valid([]byte(raw)) {
+			return strings.TrimSpace(raw), nil
+		}
+		var compact, out bytes.Buffer
+		if err := nodeJSON(&compact, root); err != nil {
+			return "", err
+		}
+		if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+			return "", err
+		}
+		return out.String(), nil
+	}
+
+	platform.BlockStyle(root)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return "", err
+	}
+	enc.Close()
+	return "---\n" + strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// nodeJSON writes a YAML node as JSON, keeping key order. Plain scalars are
+// read as PyYAML reads them on the source (YAML 1.1), so "debug: yes" stays
+// a boolean.
+func nodeJSON(buf *bytes.Buffer, n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.AliasNode:
+		return nodeJSON(buf, n.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(n.Content[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := nodeJSON(buf, n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, c := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := nodeJSON(buf, c); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+
+	var v interface{}
+	quoted := n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle|yaml.TaggedStyle) != 0
+	if b, ok := platform.YAML11Bool(n.Value); ok && !quoted {
+		v = b
+	} else if err := n.Decode(&v); err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// varsValue parses extra_vars for comparison, so the same variables compare
+// equal whether they are written as JSON or YAML. Values that do not parse
+// are returned as they are.
+func varsValue(raw interface{}) interface{} {
+	s, ok := raw.(string)
+	if !ok {
+		return raw
+	}
+	vars, err := normalizeExtraVars(s, varsJSON)
+	if err != nil {
+		return raw
+	}
+	if vars == "" {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(vars), &v); err != nil {
+		return raw
+	}
+	return v
+}
+
+// extraVarsField returns the extra_vars of a job template or workflow in the
+// given format. Values that do not parse are sent unchanged with a warning.
+func extraVarsField(r models.Resource, format string, logger func(string)) string {
+	raw := stringField(r, "extra_vars")
+	vars, err := normalizeExtraVars(raw, format)
+	if err != nil {
+		logger(fmt.Sprintf("  WARNING: %s: extra_vars kept as is: %v", resourceName(r), err))
+		return raw
+	}
+	return vars
+}
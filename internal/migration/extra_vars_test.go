@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeExtraVars(t *testing.T) {
+	tests := []struct {
+		name, in, format, want string
+	}{
+		{"json to yaml", `{"env": "prod", "port": 8080, "debug": "true", "flag": "yes", "window": "1:20", "hosts": ["a", "b"]}`, varsYAML,
+			"---\nenv: prod\nport: 8080\ndebug: \"true\"\nflag: \"yes\"\nwindow: \"1:20\"\nhosts:\n  - a\n  - b"},
+		{"yaml", "---\nenv: prod # target\n", varsYAML, "---\nenv: prod # target"},
+		{"json kept", `{"env": "prod", "flag": "yes"}`, varsJSON, `{"env": "prod", "flag": "yes"}`},
+		{"yaml to json", "---\nenv: prod\ndebug: yes\nport: 8080\nquoted: \"on\"\nhosts: [a]\n", varsJSON,
+			"{\n  \"env\": \"prod\",\n  \"debug\": true,\n  \"port\": 8080,\n  \"quoted\": \"on\",\n  \"hosts\": [\n    \"a\"\n  ]\n}"},
+		{"empty", "", varsYAML, ""},
+		{"document marker", "---", varsJSON, ""},
+		{"null", "null", varsYAML, ""},
+		{"empty object", "{}", varsJSON, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeExtraVars(tt.in, tt.format)
+			if err != nil {
+				t.Fatalf("normalizeExtraVars(%q) error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeExtraVars(%q, %s) = %q, want %q", tt.in, tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtraVarsField_KeepsInvalidInput(t *testing.T) {
+	var logs []string
+	raw := `{"env": "prod"`
+	got := extraVarsField(map[string]interface{}{"name": "Deploy", "extra_vars": raw},
+		varsYAML, func(line string) { logs = append(logs, line) })
+	if got != raw {
+		t.Errorf("extraVarsField = %q, want %q unchanged", got, raw)
+	}
+	if len(logs) != 1 || !strings.HasPrefix(logs[0], "  WARNING: Deploy: extra_vars kept as is") {
+		t.Errorf("logs = %q, want one extra_vars warning", logs)
+	}
+}
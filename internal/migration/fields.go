@@ -2,6 +2,7 @@ package migration
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 )
@@ -96,6 +97,14 @@ func extractSCMCredName(r models.Resource) string {
 	return ""
 }
 
+// extractEEName returns summary_fields.execution_environment.name.
+func extractEEName(r models.Resource) string {
+	if v, ok := summaryField(r, "execution_environment", "name").(string); ok {
+		return v
+	}
+	return ""
+}
+
 // extractUnifiedJTName returns summary_fields.unified_job_template.name.
 func extractUnifiedJTName(r models.Resource) string {
 	if v, ok := summaryField(r, "unified_job_template", "name").(string); ok {
@@ -125,6 +134,25 @@ func extractCredentialNames(r models.Resource) []string {
 	return names
 }
 
+// isDisabled reports whether a resource carries an explicit enabled=false flag.
+// Resources without an "enabled" field are never considered disabled.
+func isDisabled(r models.Resource) bool {
+	enabled, ok := r["enabled"].(bool)
+	return ok && !enabled
+}
+
+// dropDisabled returns the resources that are not disabled, plus the number removed.
+func dropDisabled(resources []models.Resource) ([]models.Resource, int) {
+	var kept []models.Resource
+	for _, r := range resources {
+		if isDisabled(r) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept, len(resources) - len(kept)
+}
+
 // toInt converts various numeric types to int.
 func toInt(v interface{}) int {
 	switch n := v.(type) {
@@ -138,3 +166,14 @@ func toInt(v interface{}) int {
 	}
 	return 0
 }
+
+// sortedInventoryIDs returns the keys of a per-inventory map in ascending
+// order, so hosts and groups are handled and logged the same way every run.
+func sortedInventoryIDs(m map[int][]models.Resource) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -152,3 +152,23 @@ func TestExtractCredentialNames(t *testing.T) {
 		t.Errorf("extractCredentialNames(bad) = %v, want nil", got)
 	}
 }
+
+func TestDropDisabled(t *testing.T) {
+	resources := []models.Resource{
+		{"name": "on", "enabled": true},
+		{"name": "off", "enabled": false},
+		{"name": "no-flag"},
+	}
+	kept, dropped := dropDisabled(resources)
+	if dropped != 1 {
+		t.Errorf("dropped = %d, want 1", dropped)
+	}
+	if len(kept) != 2 {
+		t.Fatalf("kept %d resources, want 2", len(kept))
+	}
+	for _, r := range kept {
+		if resourceName(r) == "off" {
+			t.Error("disabled resource should have been dropped")
+		}
+	}
+}
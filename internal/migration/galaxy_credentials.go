@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// exportGalaxyCredentials records, in priority order, the names of the
+// Galaxy/Automation Hub credentials attached to each exported organization.
+// Platforms without the endpoint are skipped silently.
+func exportGalaxyCredentials(client *platform.Client, prefix string, data *ExportedData, logger func(string)) {
+	logger("Exporting organization galaxy credentials...")
+	var count int
+	for _, org := range data.Organizations {
+		orgID := resourceID(org)
+		creds, err := client.GetAll(fmt.Sprintf("%sorganizations/%d/galaxy_credentials/", prefix, orgID))
+		if err != nil || len(creds) == 0 {
+			continue
+		}
+		for _, c := range creds {
+			data.GalaxyCredentials[orgID] = append(data.GalaxyCredentials[orgID], resourceName(c))
+		}
+		count += len(creds)
+	}
+	logger(fmt.Sprintf("  %d galaxy credential associations", count))
+}
+
+// importGalaxyCredentials re-attaches Galaxy credentials to migrated
+// organizations, in the source's priority order. Credentials are resolved by
+// name among the migrated ones first, then on the destination, which covers
+// defaults such as "Ansible Galaxy" that are never exported.
+func importGalaxyCredentials(dst *platform.Client, prefix string, data *ExportedData, ids *idMap, assoc *associator, logger func(string)) {
+	for _, org := range data.Organizations {
+		names := data.GalaxyCredentials[resourceID(org)]
+		if len(names) == 0 {
+			continue
+		}
+		orgName := resourceName(org)
+		destOrgID := ids.orgs[orgName]
+		if destOrgID == 0 {
+			continue
+		}
+		for _, credName := range names {
+			credID := ids.creds[credName]
+			if credID == 0 {
+				if existing, err := dst.FindByName(prefix+"credentials/", credName); err == nil && existing != nil {
+					credID = resourceID(existing)
+				}
+			}
+			if credID == 0 {
+				logger(fmt.Sprintf("  SKIP: %s → %s (credential not found)", orgName, credName))
+				continue
+			}
+			if _, err := assoc.associate(fmt.Sprintf("%sorganizations/%d/galaxy_credentials/", prefix, destOrgID), credID); err != nil {
+				logger(fmt.Sprintf("  FAIL: %s → %s: %v", orgName, credName, err))
+				continue
+			}
+			logger(fmt.Sprintf("  %s → %s", orgName, credName))
+		}
+	}
+}
+
+// countGalaxyCredentials returns the number of exported org → galaxy
+// credential associations.
+func countGalaxyCredentials(data *ExportedData) int {
+	var n int
+	for _, names := range data.GalaxyCredentials {
+		n += len(names)
+	}
+	return n
+}
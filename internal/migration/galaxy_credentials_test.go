@@ -0,0 +1,85 @@
+package migration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestExportGalaxyCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/organizations/1/galaxy_credentials/" {
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":4,"name":"Automation Hub"}]}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		Organizations: []models.Resource{
+			{"id": float64(1), "name": "Eng"},
+			{"id": float64(2), "name": "Ops"},
+		},
+		GalaxyCredentials: make(map[int][]string),
+	}
+	exportGalaxyCredentials(newTestClient(t, ts), "/api/v2/", data, func(string) {})
+
+	if got := data.GalaxyCredentials[1]; len(got) != 1 || got[0] != "Automation Hub" {
+		t.Errorf("Eng galaxy credentials = %v, want [Automation Hub]", got)
+	}
+	if _, ok := data.GalaxyCredentials[2]; ok {
+		t.Error("Ops has no galaxy credentials but got an entry")
+	}
+	if n := countGalaxyCredentials(data); n != 1 {
+		t.Errorf("countGalaxyCredentials = %d, want 1", n)
+	}
+}
+
+func TestImportGalaxyCredentials(t *testing.T) {
+	var mu sync.Mutex
+	var associated []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v2/organizations/10/galaxy_credentials/":
+			var body struct {
+				ID int `json:"id"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			associated = append(associated, body.ID)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Query().Get("name") == "Ansible Galaxy":
+			// Default credential present on the destination but never exported.
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":2,"name":"Ansible Galaxy"}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		Organizations:     []models.Resource{{"id": float64(1), "name": "Eng"}},
+		GalaxyCredentials: map[int][]string{1: {"Automation Hub", "Ansible Galaxy", "Missing"}},
+	}
+	ids := newIDMap()
+	ids.orgs["Eng"] = 10
+	ids.creds["Automation Hub"] = 40
+
+	client := newTestClient(t, ts)
+	var logs []string
+	importGalaxyCredentials(client, "/api/v2/", data, ids, newAssociator(client), func(s string) { logs = append(logs, s) })
+
+	// Priority order from the source is kept.
+	if len(associated) != 2 || associated[0] != 40 || associated[1] != 2 {
+		t.Errorf("associated = %v, want [40 2]", associated)
+	}
+	if !containsLine(logs, "  SKIP: Eng → Missing (credential not found)") {
+		t.Errorf("missing skip line for unknown credential in %q", logs)
+	}
+}
@@ -0,0 +1,57 @@
+package migration
+
+// groupSelection holds the source groups and hosts left out by group
+// membership.
+type groupSelection struct {
+	skipGroups map[int]bool
+	skipHosts  map[int]bool
+}
+
+// selectGroups works out which groups and hosts of each inventory are
+// migrated. An inventory in include (inventory name → group names) keeps
+// only those groups and their hosts. Otherwise groups excluded under
+// "groups", by name or as "inventory/group", are dropped together with
+// hosts that belong to no other group; hosts outside every group are kept.
+func selectGroups(data *ExportedData, invNames map[int]string, exclude, include map[string][]string) *groupSelection {
+	sel := &groupSelection{skipGroups: make(map[int]bool), skipHosts: make(map[int]bool)}
+	included := make(map[string]map[string]bool) // inventory → group names
+	for inv, groups := range include {
+		included[inv] = make(map[string]bool)
+		for _, g := range groups {
+			included[inv][g] = true
+		}
+	}
+
+	kept := make(map[int]bool)    // hosts in a migrated group
+	grouped := make(map[int]bool) // hosts in any group
+	for invID, groups := range data.Groups {
+		invName := invNames[invID]
+		for _, g := range groups {
+			name := resourceName(g)
+			gID := resourceID(g)
+			skip := isExcluded(exclude, "groups", name) || isExcluded(exclude, "groups", invName+"/"+name)
+			if only := included[invName]; only != nil {
+				skip = !only[name]
+			}
+			for _, hostID := range data.GroupHosts[gID] {
+				grouped[hostID] = true
+				if !skip {
+					kept[hostID] = true
+				}
+			}
+			if skip {
+				sel.skipGroups[gID] = true
+			}
+		}
+	}
+	for invID, hosts := range data.Hosts {
+		limited := included[invNames[invID]] != nil
+		for _, h := range hosts {
+			hostID := resourceID(h)
+			if !kept[hostID] && (limited || grouped[hostID]) {
+				sel.skipHosts[hostID] = true
+			}
+		}
+	}
+	return sel
+}
@@ -0,0 +1,83 @@
+package migration
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// groupedInventory returns an export of inventory Prod with groups web
+// (web1, web2) and db (db1, web1), and host lone outside any group.
+func groupedInventory() *ExportedData {
+	data := newExportedData()
+	data.Inventories = []models.Resource{{"id": float64(1), "name": "Prod"}}
+	data.Hosts[1] = []models.Resource{
+		{"id": float64(11), "name": "web1"}, {"id": float64(12), "name": "web2"},
+		{"id": float64(13), "name": "db1"}, {"id": float64(14), "name": "lone"},
+	}
+	data.Groups[1] = []models.Resource{{"id": float64(21), "name": "web"}, {"id": float64(22), "name": "db"}}
+	data.GroupHosts[21] = []int{11, 12}
+	data.GroupHosts[22] = []int{13, 11}
+	return data
+}
+
+// migratedNames runs importAll on data and returns the names of the hosts
+// and groups POSTed to the destination inventory.
+func migratedNames(t *testing.T, data *ExportedData, opts ImportOptions) (hosts, groups []string) {
+	t.Helper()
+	srv := &postRecorder{nextID: 100, posts: make(map[string][]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, &models.MigrationPreview{},
+		opts, nil, nil, func(string) {})
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+	for _, body := range srv.posts["/api/v2/inventories/101/hosts/"] {
+		hosts = append(hosts, body["name"].(string))
+	}
+	for _, body := range srv.posts["/api/v2/inventories/101/groups/"] {
+		groups = append(groups, body["name"].(string))
+	}
+	sort.Strings(hosts)
+	sort.Strings(groups)
+	return hosts, groups
+}
+
+func TestImportAll_IncludeGroups(t *testing.T) {
+	hosts, groups := migratedNames(t, groupedInventory(), ImportOptions{IncludeGroups: map[string][]string{"Prod": {"web"}}})
+	if want := []string{"web1", "web2"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+	if want := []string{"web"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v", groups, want)
+	}
+}
+
+func TestImportAll_ExcludeGroups(t *testing.T) {
+	// web1 is also in web, and lone is in no group, so both are kept.
+	hosts, groups := migratedNames(t, groupedInventory(), ImportOptions{Exclude: map[string][]string{"groups": {"Prod/db"}}})
+	if want := []string{"lone", "web1", "web2"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+	if want := []string{"web"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v", groups, want)
+	}
+}
+
+func TestImportAll_IncludeGroupsSlashInNames(t *testing.T) {
+	data := groupedInventory()
+	data.Inventories[0]["name"] = "Prod/EU"
+	data.Groups[1][0]["name"] = "web/frontend"
+	hosts, groups := migratedNames(t, data, ImportOptions{IncludeGroups: map[string][]string{"Prod/EU": {"web/frontend"}}})
+	if want := []string{"web1", "web2"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+	if want := []string{"web/frontend"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v", groups, want)
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
@@ -12,36 +18,45 @@ import (
 
 // idMap tracks source name → destination ID mappings for reference resolution.
 type idMap struct {
-	orgs         map[string]int
-	teams        map[string]int
-	users        map[string]int
-	credTypes    map[string]int
-	creds        map[string]int
-	projects     map[string]int
-	invs         map[string]int
-	hosts        map[string]int // "invName/hostName" → dest ID
-	groups       map[string]int // "invName/groupName" → dest ID
-	jts          map[string]int
-	wfjts        map[string]int
-	credTypeByID map[int]int // source cred type ID → dest cred type ID
-	nodes        map[int]int // source node ID → dest node ID
+	orgs          map[string]int
+	teams         map[string]int
+	users         map[string]int
+	credTypes     map[string]int
+	creds         map[string]int
+	projects      map[string]int
+	invs          map[string]int
+	hosts         map[string]int // "invName/hostName" → dest ID
+	groups        map[string]int // "invName/groupName" → dest ID
+	jts           map[string]int
+	wfjts         map[string]int
+	ees           map[string]int
+	apps          map[string]int
+	schedules     map[string]int // "parentName/scheduleName" → dest ID
+	notifications map[int]int    // source notification template ID → dest ID
+	credTypeByID  map[int]int    // source cred type ID → dest cred type ID
+	nodes         map[int]int    // source node ID → dest node ID
+	resumed       bool           // set when continuing an interrupted run
 }
 
 func newIDMap() *idMap {
 	return &idMap{
-		orgs:         make(map[string]int),
-		teams:        make(map[string]int),
-		users:        make(map[string]int),
-		credTypes:    make(map[string]int),
-		creds:        make(map[string]int),
-		projects:     make(map[string]int),
-		invs:         make(map[string]int),
-		hosts:        make(map[string]int),
-		groups:       make(map[string]int),
-		jts:          make(map[string]int),
-		wfjts:        make(map[string]int),
-		credTypeByID: make(map[int]int),
-		nodes:        make(map[int]int),
+		orgs:          make(map[string]int),
+		teams:         make(map[string]int),
+		users:         make(map[string]int),
+		credTypes:     make(map[string]int),
+		creds:         make(map[string]int),
+		projects:      make(map[string]int),
+		invs:          make(map[string]int),
+		hosts:         make(map[string]int),
+		groups:        make(map[string]int),
+		jts:           make(map[string]int),
+		wfjts:         make(map[string]int),
+		ees:           make(map[string]int),
+		apps:          make(map[string]int),
+		schedules:     make(map[string]int),
+		notifications: make(map[int]int),
+		credTypeByID:  make(map[int]int),
+		nodes:         make(map[int]int),
 	}
 }
 
@@ -69,12 +84,56 @@ func isExcluded(exclude map[string][]string, typeName, name string) bool {
 	return false
 }
 
+// MergeExclusions combines exclusion maps (resource type → names), keeping
+// each name once.
+func MergeExclusions(sets ...map[string][]string) map[string][]string {
+	out := make(map[string][]string)
+	seen := make(map[string]map[string]bool)
+	for _, set := range sets {
+		for typeName, names := range set {
+			if seen[typeName] == nil {
+				seen[typeName] = make(map[string]bool)
+			}
+			for _, n := range names {
+				if !seen[typeName][n] {
+					seen[typeName][n] = true
+					out[typeName] = append(out[typeName], n)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// previewExclusions returns the resources a preview marked "skip_excluded".
+func previewExclusions(preview *models.MigrationPreview) map[string][]string {
+	out := make(map[string][]string)
+	for typeName, items := range preview.Resources {
+		for _, mr := range items {
+			if mr.Action == "skip_excluded" {
+				out[typeName] = append(out[typeName], mr.Name)
+			}
+		}
+	}
+	return out
+}
+
 // importAll creates resources on the destination in strict dependency order.
-func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string, data *ExportedData, preview *models.MigrationPreview, exclude map[string][]string, logger func(string)) error {
-	if exclude == nil {
-		exclude = make(map[string][]string)
+// Payloads are rewritten by opts.Transforms just before they are sent.
+// Mappings are recorded in ids, which may hold the state of an earlier,
+// interrupted run (nil starts fresh). If report is non-nil it is called with
+// the completed and total step counts as the import advances through its
+// phases and resources.
+func importAll(ctx context.Context, dst *platform.Client, prefix string, data *ExportedData, preview *models.MigrationPreview, opts ImportOptions, ids *idMap, report func(completed, total int), logger func(string)) error {
+	// Defaults were already dropped at export; add what the preview excluded.
+	exclude := MergeExclusions(opts.Exclude, previewExclusions(preview))
+	secrets, tf, users := opts.Secrets, opts.Transforms, opts.Users
+	if ids == nil {
+		ids = newIDMap()
 	}
-	ids := newIDMap()
+	assoc := newAssociator(dst)
+	labels := newLabeler(dst, prefix, assoc)
+	progress := newProgressTracker(data, report)
 
 	// Pre-populate credential type name→ID from destination (for both managed and custom types)
 	allDestCT, _ := dst.GetAll(prefix + "credential_types/")
@@ -88,28 +147,35 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		return ctx.Err()
 	}
 	logger("=== Importing organizations ===")
+	progress.step()
 	for _, org := range data.Organizations {
+		progress.step()
 		name := resourceName(org)
 		if isExcluded(exclude, "organizations", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.orgs, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "organizations", name)
-		if action != "create" {
+		if action != "create" && action != "update" {
 			ids.orgs[name] = destID
 			logger(fmt.Sprintf("  SKIP (exists): %s", name))
 			continue
 		}
-		id, err := createResource(dst, prefix+"organizations/", map[string]interface{}{
+		payload := map[string]interface{}{
 			"name":        name,
 			"description": stringField(org, "description"),
-		})
+		}
+		tf.apply("organizations", name, payload, logger)
+		id, verb, err := applyResource(dst, prefix+"organizations/", action, destID, payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
 		}
 		ids.orgs[name] = id
-		logger(fmt.Sprintf("  CREATED: %s (ID %d)", name, id))
+		logger(fmt.Sprintf("  %s: %s (ID %d)", verb, name, id))
 	}
 
 	// 2. Credential types (custom only)
@@ -119,12 +185,17 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 	}
 	logger("")
 	logger("=== Importing credential types ===")
+	progress.step()
 	for _, ct := range data.CredentialTypes {
+		progress.step()
 		name := resourceName(ct)
 		if isExcluded(exclude, "credential_types", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.credTypes, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "credential_types", name)
 		if action != "create" {
 			ids.credTypes[name] = destID
@@ -132,13 +203,15 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			logger(fmt.Sprintf("  SKIP (exists): %s", name))
 			continue
 		}
-		id, err := createResource(dst, prefix+"credential_types/", map[string]interface{}{
+		payload := map[string]interface{}{
 			"name":        name,
 			"description": stringField(ct, "description"),
-			"kind":        stringField(ct, "kind"),
+			"kind":        remapCredTypeKind(name, stringField(ct, "kind"), logger),
 			"inputs":      ct["inputs"],
 			"injectors":   ct["injectors"],
-		})
+		}
+		tf.apply("credential_types", name, payload, logger)
+		id, err := createResource(dst, prefix+"credential_types/", payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
@@ -155,26 +228,32 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 	}
 	logger("")
 	logger("=== Importing users ===")
+	progress.step()
 	for _, user := range data.Users {
+		progress.step()
 		name := stringField(user, "username")
 		if isExcluded(exclude, "users", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.users, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "users", name)
 		if action != "create" {
 			ids.users[name] = destID
 			logger(fmt.Sprintf("  SKIP (exists): %s", name))
 			continue
 		}
-		id, err := createResource(dst, prefix+"users/", map[string]interface{}{
-			"username":     name,
-			"first_name":   stringField(user, "first_name"),
-			"last_name":    stringField(user, "last_name"),
-			"email":        stringField(user, "email"),
-			"is_superuser": false,
-			"password":     "changeme!",
-		})
+		payload := map[string]interface{}{
+			"username":   name,
+			"first_name": stringField(user, "first_name"),
+			"last_name":  stringField(user, "last_name"),
+			"email":      stringField(user, "email"),
+		}
+		users.apply(user, name, payload, logger)
+		tf.apply("users", name, payload, logger)
+		id, err := createResource(dst, prefix+"users/", payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
@@ -190,12 +269,17 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 	}
 	logger("")
 	logger("=== Importing teams ===")
+	progress.step()
 	for _, team := range data.Teams {
+		progress.step()
 		name := resourceName(team)
 		if isExcluded(exclude, "teams", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.teams, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "teams", name)
 		if action != "create" {
 			ids.teams[name] = destID
@@ -208,11 +292,13 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			logger(fmt.Sprintf("  SKIP: %s (org %q not found)", name, orgName))
 			continue
 		}
-		id, err := createResource(dst, prefix+"teams/", map[string]interface{}{
+		payload := map[string]interface{}{
 			"name":         name,
 			"description":  stringField(team, "description"),
 			"organization": orgID,
-		})
+		}
+		tf.apply("teams", name, payload, logger)
+		id, err := createResource(dst, prefix+"teams/", payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
@@ -228,12 +314,18 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 	}
 	logger("")
 	logger("=== Importing credentials ===")
+	progress.step()
+	var secretsFilled, secretsEmpty int
 	for _, cred := range data.Credentials {
+		progress.step()
 		name := resourceName(cred)
 		if isExcluded(exclude, "credentials", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.creds, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "credentials", name)
 		if action != "create" {
 			ids.creds[name] = destID
@@ -245,48 +337,108 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 
 		// Resolve credential type: try by source ID first, then by name
 		srcCtID := intField(cred, "credential_type")
+		ctName := extractCredTypeName(cred)
 		destCtID := ids.credTypeByID[srcCtID]
 		if destCtID == 0 {
-			ctName := extractCredTypeName(cred)
 			destCtID = ids.credTypes[ctName]
 		}
 		if destCtID == 0 {
-			logger(fmt.Sprintf("  SKIP: %s (credential type not found)", name))
+			logger(fmt.Sprintf("  SKIP: %s (%s)", name, missingCredTypeReason(data, ctName)))
 			continue
 		}
 
-		id, err := createResource(dst, prefix+"credentials/", map[string]interface{}{
+		inputs, filled, empty := secrets.resolve(name)
+		payload := map[string]interface{}{
 			"name":            name,
 			"description":     stringField(cred, "description"),
 			"organization":    orgID,
 			"credential_type": destCtID,
-			"inputs":          map[string]interface{}{},
-		})
+			"inputs":          inputs,
+		}
+		tf.apply("credentials", name, payload, logger)
+		id, err := createResource(dst, prefix+"credentials/", payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
 		}
 		ids.creds[name] = id
-		logger(fmt.Sprintf("  CREATED: %s (ID %d) [inputs empty — set secrets manually]", name, id))
+		if len(filled) > 0 {
+			secretsFilled++
+		} else {
+			secretsEmpty++
+		}
+		logger(fmt.Sprintf("  CREATED: %s (ID %d) %s", name, id, secretsNote(filled, empty)))
+	}
+	if secretsFilled+secretsEmpty > 0 {
+		logger(fmt.Sprintf("  Secrets: %d credentials filled, %d left empty", secretsFilled, secretsEmpty))
+	}
+
+	// 6. Organization galaxy credentials (orgs and credentials must exist)
+	if ctx.Err() != nil {
+		logger("Migration cancelled by user")
+		return ctx.Err()
+	}
+	if len(data.GalaxyCredentials) > 0 {
+		logger("")
+		logger("=== Importing organization galaxy credentials ===")
+		progress.step()
+		importGalaxyCredentials(dst, prefix, data, ids, assoc, logger)
+	}
+
+	// 6b. Credential input sources (all credentials must exist)
+	if ctx.Err() != nil {
+		logger("Migration cancelled by user")
+		return ctx.Err()
+	}
+	if len(data.CredInputSources) > 0 {
+		logger("")
+		logger("=== Importing credential input sources ===")
+		progress.step()
+		importCredentialInputSources(dst, prefix, data, ids, logger)
 	}
 
-	// 6. Projects
+	// 7. Execution environments (custom only; defaults are resolved by name)
+	if ctx.Err() != nil {
+		logger("Migration cancelled by user")
+		return ctx.Err()
+	}
+	logger("")
+	logger("=== Importing execution environments ===")
+	progress.step()
+	importExecutionEnvironments(dst, prefix, data, preview, exclude, tf, ids, progress, logger)
+
+	// 7b. OAuth2 applications (organizations must exist)
+	if ctx.Err() != nil {
+		logger("Migration cancelled by user")
+		return ctx.Err()
+	}
+	logger("")
+	logger("=== Importing applications ===")
+	progress.step()
+	importApplications(dst, prefix, data, preview, exclude, tf, ids, progress, logger)
+
+	// 8. Projects
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing projects ===")
+	progress.step()
 	var projectWaitList []struct {
 		name string
 		id   int
 	}
 	for _, proj := range data.Projects {
+		progress.step()
 		name := resourceName(proj)
 		if isExcluded(exclude, "projects", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.projects, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "projects", name)
 		if action != "create" {
 			ids.projects[name] = destID
@@ -317,6 +469,7 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			}
 		}
 
+		tf.apply("projects", name, payload, logger)
 		id, err := createResource(dst, prefix+"projects/", payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
@@ -324,21 +477,24 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		}
 		ids.projects[name] = id
 		logger(fmt.Sprintf("  CREATED: %s (ID %d)", name, id))
-		projectWaitList = append(projectWaitList, struct {
-			name string
-			id   int
-		}{name, id})
+		// Manual projects never sync
+		if stringField(proj, "scm_type") != "" {
+			projectWaitList = append(projectWaitList, struct {
+				name string
+				id   int
+			}{name, id})
+		}
 	}
 
-	// Wait for project syncs on AAP
-	if dstType == "aap" && len(projectWaitList) > 0 {
+	// Wait for project syncs, so job templates can reference their playbooks
+	if len(projectWaitList) > 0 {
 		logger("  Waiting for project syncs...")
 		for _, pw := range projectWaitList {
 			if ctx.Err() != nil {
 				logger("Migration cancelled by user")
 				return ctx.Err()
 			}
-			if err := waitForProjectCtx(ctx, dst, prefix, pw.id, 120*time.Second); err != nil {
+			if err := dst.WaitForProject(ctx, fmt.Sprintf("%sprojects/%d/", prefix, pw.id), 120*time.Second); err != nil {
 				logger(fmt.Sprintf("  WARNING: project %s sync: %v", pw.name, err))
 			} else {
 				logger(fmt.Sprintf("  Project %s sync complete", pw.name))
@@ -346,53 +502,60 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		}
 	}
 
-	// 7. Inventories
+	// 9. Inventories
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing inventories ===")
+	progress.step()
 	// Map source inv ID → name for host/group import
 	srcInvNames := make(map[int]string)
 	for _, inv := range data.Inventories {
+		progress.step()
 		name := resourceName(inv)
 		srcInvNames[resourceID(inv)] = name
 		if isExcluded(exclude, "inventories", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.invs, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "inventories", name)
-		if action != "create" {
+		if action != "create" && action != "update" {
 			ids.invs[name] = destID
 			logger(fmt.Sprintf("  SKIP (exists): %s", name))
 			continue
 		}
 		orgName := extractOrgName(inv)
-		orgID := ids.orgs[orgName]
-		id, err := createResource(dst, prefix+"inventories/", map[string]interface{}{
-			"name":         name,
-			"description":  stringField(inv, "description"),
-			"organization": orgID,
-			"variables":    stringField(inv, "variables"),
-		})
+		path, payload := inventoryPayload(prefix, inv, ids.orgs[orgName])
+		tf.apply("inventories", name, payload, logger)
+		id, verb, err := applyResource(dst, path, action, destID, payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
 		}
 		ids.invs[name] = id
-		logger(fmt.Sprintf("  CREATED: %s (ID %d)", name, id))
+		logger(fmt.Sprintf("  %s: %s (ID %d)", verb, name, id))
 	}
+	importInputInventories(dst, prefix, data, ids, assoc, logger)
 
-	// 8. Hosts per inventory
+	// 10. Hosts per inventory
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing hosts ===")
+	progress.step()
 	srcHostNames := make(map[int]string) // source host ID → name
-	for srcInvID, hosts := range data.Hosts {
+	selection := selectGroups(data, srcInvNames, exclude, opts.IncludeGroups)
+	workers := dst.WriteConcurrency()
+	var progressMu sync.Mutex // progress is stepped from host workers
+	for _, srcInvID := range sortedInventoryIDs(data.Hosts) {
+		hosts := data.Hosts[srcInvID]
 		invName := srcInvNames[srcInvID]
 		destInvID := ids.invs[invName]
 		if destInvID == 0 {
@@ -406,47 +569,75 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			}
 			continue
 		}
-		for _, host := range hosts {
-			if ctx.Err() != nil {
-				logger("Migration cancelled by user")
-				return ctx.Err()
+		// Hosts of one inventory are independent, so up to workers are
+		// created at once. Each host's log lines are buffered and written in
+		// source order afterwards to keep the log deterministic.
+		hostsPath := fmt.Sprintf("%sinventories/%d/hosts/", prefix, destInvID)
+		results := make([]hostResult, len(hosts))
+		forEachLimit(ctx, len(hosts), workers, func(i int) {
+			host, r := hosts[i], &results[i]
+			defer func() {
+				progressMu.Lock()
+				progress.step()
+				progressMu.Unlock()
+			}()
+			r.name = resourceName(host)
+			log := func(line string) { r.lines = append(r.lines, line) }
+			if isExcluded(exclude, "hosts", r.name) {
+				log(fmt.Sprintf("  EXCLUDED: %s/%s (user exclusion)", invName, r.name))
+				return
 			}
-			name := resourceName(host)
-			srcHostNames[resourceID(host)] = name
-			key := invName + "/" + name
-			if isExcluded(exclude, "hosts", name) {
-				logger(fmt.Sprintf("  EXCLUDED: %s/%s (user exclusion)", invName, name))
-				continue
+			if selection.skipHosts[resourceID(host)] {
+				log(fmt.Sprintf("  EXCLUDED: %s/%s (group selection)", invName, r.name))
+				return
 			}
 			// Check if host already exists
-			existing, _ := dst.FindByName(fmt.Sprintf("%sinventories/%d/hosts/", prefix, destInvID), name)
+			existing, _ := dst.FindByName(hostsPath, tf.name("hosts", r.name))
 			if existing != nil {
-				ids.hosts[key] = resourceID(existing)
-				continue
+				r.id = resourceID(existing)
+				return
 			}
-			id, err := createResource(dst, fmt.Sprintf("%sinventories/%d/hosts/", prefix, destInvID), map[string]interface{}{
-				"name":        name,
+			key := invName + "/" + r.name
+			payload := map[string]interface{}{
+				"name":        r.name,
 				"description": stringField(host, "description"),
 				"variables":   stringField(host, "variables"),
 				"enabled":     host["enabled"],
-			})
+			}
+			tf.apply("hosts", key, payload, log)
+			id, err := createResource(dst, hostsPath, payload)
 			if err != nil {
-				logger(fmt.Sprintf("  FAIL: %s/%s: %v", invName, name, err))
-				continue
+				log(fmt.Sprintf("  FAIL: %s/%s: %v", invName, r.name, err))
+				return
 			}
-			ids.hosts[key] = id
+			r.id = id
+		})
+		for i, r := range results {
+			srcHostNames[resourceID(hosts[i])] = resourceName(hosts[i])
+			for _, line := range r.lines {
+				logger(line)
+			}
+			if r.id != 0 {
+				ids.hosts[invName+"/"+r.name] = r.id
+			}
+		}
+		if ctx.Err() != nil {
+			logger("Migration cancelled by user")
+			return ctx.Err()
 		}
 		logger(fmt.Sprintf("  %s: %d hosts", invName, len(hosts)))
 	}
 
-	// 9. Groups per inventory + host associations
+	// 11. Groups per inventory + host associations
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing groups ===")
-	for srcInvID, groups := range data.Groups {
+	progress.step()
+	for _, srcInvID := range sortedInventoryIDs(data.Groups) {
+		groups := data.Groups[srcInvID]
 		invName := srcInvNames[srcInvID]
 		destInvID := ids.invs[invName]
 		if destInvID == 0 {
@@ -457,6 +648,7 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			continue
 		}
 		for _, group := range groups {
+			progress.step()
 			if ctx.Err() != nil {
 				logger("Migration cancelled by user")
 				return ctx.Err()
@@ -464,18 +656,24 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			name := resourceName(group)
 			key := invName + "/" + name
 			srcGroupID := resourceID(group)
+			if selection.skipGroups[srcGroupID] {
+				logger(fmt.Sprintf("  EXCLUDED: %s (group selection)", key))
+				continue
+			}
 
-			existing, _ := dst.FindByName(fmt.Sprintf("%sinventories/%d/groups/", prefix, destInvID), name)
+			existing, _ := dst.FindByName(fmt.Sprintf("%sinventories/%d/groups/", prefix, destInvID), tf.name("groups", name))
 			var destGroupID int
 			if existing != nil {
 				destGroupID = resourceID(existing)
 				ids.groups[key] = destGroupID
 			} else {
-				id, err := createResource(dst, fmt.Sprintf("%sinventories/%d/groups/", prefix, destInvID), map[string]interface{}{
+				payload := map[string]interface{}{
 					"name":        name,
 					"description": stringField(group, "description"),
 					"variables":   stringField(group, "variables"),
-				})
+				}
+				tf.apply("groups", key, payload, logger)
+				id, err := createResource(dst, fmt.Sprintf("%sinventories/%d/groups/", prefix, destInvID), payload)
 				if err != nil {
 					logger(fmt.Sprintf("  FAIL: %s/%s: %v", invName, name, err))
 					continue
@@ -484,34 +682,103 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 				ids.groups[key] = id
 			}
 
-			// Associate hosts to group
+			// Associate hosts to group, up to workers at once
+			var destHostIDs []int
 			for _, srcHostID := range data.GroupHosts[srcGroupID] {
 				hostName := srcHostNames[srcHostID]
 				hostKey := invName + "/" + hostName
 				if destHostID, ok := ids.hosts[hostKey]; ok {
-					dst.Post(fmt.Sprintf("%sgroups/%d/hosts/", prefix, destGroupID),
-						map[string]interface{}{"id": destHostID})
+					destHostIDs = append(destHostIDs, destHostID)
 				}
 			}
+			groupHostsPath := fmt.Sprintf("%sgroups/%d/hosts/", prefix, destGroupID)
+			forEachLimit(ctx, len(destHostIDs), workers, func(i int) {
+				assoc.associate(groupHostsPath, destHostIDs[i])
+			})
 		}
 		logger(fmt.Sprintf("  %s: %d groups", invName, len(groups)))
 	}
 
-	// 10. Job templates
+	// 12. Inventory sources (inventories, credentials and projects must exist)
+	if ctx.Err() != nil {
+		logger("Migration cancelled by user")
+		return ctx.Err()
+	}
+	if len(data.InventorySources) > 0 {
+		logger("")
+		logger("=== Importing inventory sources ===")
+		progress.step()
+		importInventorySources(dst, prefix, data, exclude, ids, progress, logger)
+	}
+
+	// 13. Notification templates
+	if ctx.Err() != nil {
+		logger("Migration cancelled by user")
+		return ctx.Err()
+	}
+	logger("")
+	logger("=== Importing notification templates ===")
+	progress.step()
+	for _, nt := range data.NotificationTemplates {
+		progress.step()
+		name := resourceName(nt)
+		if isExcluded(exclude, "notification_templates", name) {
+			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
+			continue
+		}
+		if ids.resumed && ids.notifications[resourceID(nt)] != 0 {
+			logger(fmt.Sprintf("  SKIP (already migrated): %s", name))
+			continue
+		}
+		action, destID := actionFor(preview, "notification_templates", name)
+		if action != "create" {
+			ids.notifications[resourceID(nt)] = destID
+			logger(fmt.Sprintf("  SKIP (exists): %s", name))
+			continue
+		}
+		orgID := ids.orgs[extractOrgName(nt)]
+		if orgID == 0 {
+			logger(fmt.Sprintf("  SKIP: %s (organization not found)", name))
+			continue
+		}
+		payload := map[string]interface{}{
+			"name":                       name,
+			"description":                stringField(nt, "description"),
+			"organization":               orgID,
+			"notification_type":          stringField(nt, "notification_type"),
+			"notification_configuration": nt["notification_configuration"],
+			"messages":                   nt["messages"],
+		}
+		tf.apply("notification_templates", name, payload, logger)
+		id, err := createResource(dst, prefix+"notification_templates/", payload)
+		if err != nil {
+			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
+			continue
+		}
+		ids.notifications[resourceID(nt)] = id
+		logger(fmt.Sprintf("  CREATED: %s (ID %d) [secrets empty — re-enter manually]", name, id))
+	}
+
+	// 14. Job templates
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing job templates ===")
+	progress.step()
 	for _, jt := range data.JobTemplates {
+		progress.step()
 		name := resourceName(jt)
 		if isExcluded(exclude, "job_templates", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.jts, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "job_templates", name)
-		if action != "create" {
+		if action != "create" && action != "update" {
 			ids.jts[name] = destID
 			logger(fmt.Sprintf("  SKIP (exists): %s", name))
 			continue
@@ -521,38 +788,38 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		invName := extractInventoryName(jt)
 
 		payload := map[string]interface{}{
-			"name":                                 name,
-			"description":                          stringField(jt, "description"),
-			"job_type":                             stringField(jt, "job_type"),
-			"playbook":                             stringField(jt, "playbook"),
-			"forks":                                jt["forks"],
-			"limit":                                stringField(jt, "limit"),
-			"verbosity":                            jt["verbosity"],
-			"extra_vars":                           stringField(jt, "extra_vars"),
-			"ask_variables_on_launch":              jt["ask_variables_on_launch"],
-			"ask_limit_on_launch":                  jt["ask_limit_on_launch"],
-			"ask_tags_on_launch":                   jt["ask_tags_on_launch"],
-			"ask_diff_mode_on_launch":              jt["ask_diff_mode_on_launch"],
-			"ask_skip_tags_on_launch":              jt["ask_skip_tags_on_launch"],
-			"ask_job_type_on_launch":               jt["ask_job_type_on_launch"],
-			"ask_credential_on_launch":             jt["ask_credential_on_launch"],
-			"ask_verbosity_on_launch":              jt["ask_verbosity_on_launch"],
-			"ask_inventory_on_launch":              jt["ask_inventory_on_launch"],
-			"ask_scm_branch_on_launch":             jt["ask_scm_branch_on_launch"],
-			"ask_execution_environment_on_launch":  jt["ask_execution_environment_on_launch"],
-			"ask_labels_on_launch":                 jt["ask_labels_on_launch"],
-			"ask_forks_on_launch":                  jt["ask_forks_on_launch"],
-			"ask_job_slice_count_on_launch":        jt["ask_job_slice_count_on_launch"],
-			"ask_timeout_on_launch":                jt["ask_timeout_on_launch"],
-			"survey_enabled":                       jt["survey_enabled"],
-			"become_enabled":                       jt["become_enabled"],
-			"diff_mode":                            jt["diff_mode"],
-			"allow_simultaneous":                   jt["allow_simultaneous"],
-			"job_slice_count":                      jt["job_slice_count"],
-			"timeout":                              jt["timeout"],
-			"use_fact_cache":                       jt["use_fact_cache"],
-			"host_config_key":                      stringField(jt, "host_config_key"),
-			"scm_branch":                           stringField(jt, "scm_branch"),
+			"name":                                name,
+			"description":                         stringField(jt, "description"),
+			"job_type":                            stringField(jt, "job_type"),
+			"playbook":                            stringField(jt, "playbook"),
+			"forks":                               jt["forks"],
+			"limit":                               stringField(jt, "limit"),
+			"verbosity":                           jt["verbosity"],
+			"extra_vars":                          extraVarsField(jt, opts.varsFormat, logger),
+			"ask_variables_on_launch":             jt["ask_variables_on_launch"],
+			"ask_limit_on_launch":                 jt["ask_limit_on_launch"],
+			"ask_tags_on_launch":                  jt["ask_tags_on_launch"],
+			"ask_diff_mode_on_launch":             jt["ask_diff_mode_on_launch"],
+			"ask_skip_tags_on_launch":             jt["ask_skip_tags_on_launch"],
+			"ask_job_type_on_launch":              jt["ask_job_type_on_launch"],
+			"ask_credential_on_launch":            jt["ask_credential_on_launch"],
+			"ask_verbosity_on_launch":             jt["ask_verbosity_on_launch"],
+			"ask_inventory_on_launch":             jt["ask_inventory_on_launch"],
+			"ask_scm_branch_on_launch":            jt["ask_scm_branch_on_launch"],
+			"ask_execution_environment_on_launch": jt["ask_execution_environment_on_launch"],
+			"ask_labels_on_launch":                jt["ask_labels_on_launch"],
+			"ask_forks_on_launch":                 jt["ask_forks_on_launch"],
+			"ask_job_slice_count_on_launch":       jt["ask_job_slice_count_on_launch"],
+			"ask_timeout_on_launch":               jt["ask_timeout_on_launch"],
+			"survey_enabled":                      jt["survey_enabled"],
+			"become_enabled":                      jt["become_enabled"],
+			"diff_mode":                           jt["diff_mode"],
+			"allow_simultaneous":                  jt["allow_simultaneous"],
+			"job_slice_count":                     jt["job_slice_count"],
+			"timeout":                             jt["timeout"],
+			"use_fact_cache":                      jt["use_fact_cache"],
+			"host_config_key":                     stringField(jt, "host_config_key"),
+			"scm_branch":                          stringField(jt, "scm_branch"),
 		}
 
 		if projID := ids.projects[projName]; projID != 0 {
@@ -561,20 +828,23 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		if invID := ids.invs[invName]; invID != 0 {
 			payload["inventory"] = invID
 		}
+		if eeID := ids.ees[extractEEName(jt)]; eeID != 0 {
+			payload["execution_environment"] = eeID
+		}
 
-		id, err := createResource(dst, prefix+"job_templates/", payload)
+		tf.apply("job_templates", name, payload, logger)
+		id, verb, err := applyResource(dst, prefix+"job_templates/", action, destID, payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
 		}
 		ids.jts[name] = id
-		logger(fmt.Sprintf("  CREATED: %s (ID %d)", name, id))
+		logger(fmt.Sprintf("  %s: %s (ID %d)", verb, name, id))
 
 		// Associate credentials
 		for _, credName := range extractCredentialNames(jt) {
 			if credID := ids.creds[credName]; credID != 0 {
-				dst.Post(fmt.Sprintf("%sjob_templates/%d/credentials/", prefix, id),
-					map[string]interface{}{"id": credID})
+				assoc.associate(fmt.Sprintf("%sjob_templates/%d/credentials/", prefix, id), credID)
 			}
 		}
 
@@ -583,22 +853,31 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		if survey, ok := data.Surveys[srcJTID]; ok {
 			dst.Post(fmt.Sprintf("%sjob_templates/%d/survey_spec/", prefix, id), survey)
 		}
+
+		labels.attach(fmt.Sprintf("%sjob_templates/%d/labels/", prefix, id), data.Labels[srcJTID], ids.orgs[extractOrgName(jt)], ids, logger)
+		attachNotifications(assoc, fmt.Sprintf("%sjob_templates/%d/", prefix, id), data.Notifications[srcJTID], ids, logger)
 	}
 
-	// 11. Schedules
+	// 15. Schedules
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing schedules ===")
+	progress.step()
 	for _, sched := range data.Schedules {
+		progress.step()
 		name := resourceName(sched)
 		if isExcluded(exclude, "schedules", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
 		parentName := extractUnifiedJTName(sched)
+		key := parentName + "/" + name
+		if ids.migrated(ids.schedules, key, logger) {
+			continue
+		}
 		destParentID := ids.jts[parentName]
 		if destParentID == 0 {
 			destParentID = ids.wfjts[parentName]
@@ -614,30 +893,35 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			parentEndpoint = "workflow_job_templates"
 		}
 
-		_, err := createResource(dst, fmt.Sprintf("%s%s/%d/schedules/", prefix, parentEndpoint, destParentID), map[string]interface{}{
-			"name":  name,
-			"rrule": stringField(sched, "rrule"),
-		})
+		payload := schedulePayload(sched, logger)
+		tf.apply("schedules", name, payload, logger)
+		newID, err := createResource(dst, fmt.Sprintf("%s%s/%d/schedules/", prefix, parentEndpoint, destParentID), payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
 		}
+		ids.schedules[key] = newID
 		logger(fmt.Sprintf("  CREATED: %s", name))
 	}
 
-	// 12. Workflow job templates
+	// 16. Workflow job templates
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing workflow job templates ===")
+	progress.step()
 	for _, wf := range data.WorkflowJTs {
+		progress.step()
 		name := resourceName(wf)
 		if isExcluded(exclude, "workflow_job_templates", name) {
 			logger(fmt.Sprintf("  EXCLUDED: %s (user exclusion)", name))
 			continue
 		}
+		if ids.migrated(ids.wfjts, name, logger) {
+			continue
+		}
 		action, destID := actionFor(preview, "workflow_job_templates", name)
 		if action != "create" {
 			ids.wfjts[name] = destID
@@ -647,7 +931,7 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		orgName := extractOrgName(wf)
 		orgID := ids.orgs[orgName]
 
-		id, err := createResource(dst, prefix+"workflow_job_templates/", map[string]interface{}{
+		payload := map[string]interface{}{
 			"name":                     name,
 			"description":              stringField(wf, "description"),
 			"organization":             orgID,
@@ -658,25 +942,31 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			"ask_scm_branch_on_launch": wf["ask_scm_branch_on_launch"],
 			"ask_limit_on_launch":      wf["ask_limit_on_launch"],
 			"ask_labels_on_launch":     wf["ask_labels_on_launch"],
-			"extra_vars":               stringField(wf, "extra_vars"),
+			"extra_vars":               extraVarsField(wf, opts.varsFormat, logger),
 			"limit":                    stringField(wf, "limit"),
-			"scm_branch":              stringField(wf, "scm_branch"),
-		})
+			"scm_branch":               stringField(wf, "scm_branch"),
+		}
+		tf.apply("workflow_job_templates", name, payload, logger)
+		id, err := createResource(dst, prefix+"workflow_job_templates/", payload)
 		if err != nil {
 			logger(fmt.Sprintf("  FAIL: %s: %v", name, err))
 			continue
 		}
 		ids.wfjts[name] = id
 		logger(fmt.Sprintf("  CREATED: %s (ID %d)", name, id))
+
+		labels.attach(fmt.Sprintf("%sworkflow_job_templates/%d/labels/", prefix, id), data.WorkflowLabels[resourceID(wf)], orgID, ids, logger)
+		attachNotifications(assoc, fmt.Sprintf("%sworkflow_job_templates/%d/", prefix, id), data.WorkflowNotifications[resourceID(wf)], ids, logger)
 	}
 
-	// 13. Workflow nodes — two passes: create nodes, then wire edges
+	// 17. Workflow nodes — two passes: create nodes, then wire edges
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing workflow nodes ===")
+	progress.step()
 	for _, wf := range data.WorkflowJTs {
 		wfName := resourceName(wf)
 		srcWFID := resourceID(wf)
@@ -689,9 +979,21 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 			continue
 		}
 
-		// Pass 1: create all nodes
+		// Pass 1: create all nodes (a resumed run keeps those it already made)
 		for _, node := range nodes {
+			if ids.nodes[resourceID(node)] != 0 {
+				continue
+			}
 			ujtName := extractUnifiedJTName(node)
+			if isApprovalNode(node) {
+				nodeID, err := createApprovalNode(dst, prefix, destWFID, node)
+				if err != nil {
+					logger(fmt.Sprintf("  FAIL approval node %s: %v", ujtName, err))
+					continue
+				}
+				ids.nodes[resourceID(node)] = nodeID
+				continue
+			}
 			destUJTID := ids.jts[ujtName]
 			if destUJTID == 0 {
 				destUJTID = ids.wfjts[ujtName]
@@ -703,12 +1005,13 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 
 			nodeID, err := createResource(dst,
 				fmt.Sprintf("%sworkflow_job_templates/%d/workflow_nodes/", prefix, destWFID),
-				map[string]interface{}{"unified_job_template": destUJTID})
+				workflowNodePayload(node, destUJTID, ids, logger))
 			if err != nil {
 				logger(fmt.Sprintf("  FAIL node for %s: %v", ujtName, err))
 				continue
 			}
 			ids.nodes[resourceID(node)] = nodeID
+			attachNodeCredentials(dst, prefix, assoc, nodeID, data.NodeCredentials[resourceID(node)], ids, logger)
 		}
 
 		// Pass 2: wire edges
@@ -732,13 +1035,14 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		}
 	}
 
-	// 14. User-org associations
+	// 18. User-org associations
 	if ctx.Err() != nil {
 		logger("Migration cancelled by user")
 		return ctx.Err()
 	}
 	logger("")
 	logger("=== Importing user-org associations ===")
+	progress.step()
 	for _, org := range data.Organizations {
 		srcOrgID := resourceID(org)
 		orgName := resourceName(org)
@@ -746,19 +1050,25 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		if destOrgID == 0 {
 			continue
 		}
+		var skipped int
 		for _, username := range data.OrgUsers[srcOrgID] {
 			if destUserID := ids.users[username]; destUserID != 0 {
-				dst.Post(fmt.Sprintf("%sorganizations/%d/users/", prefix, destOrgID),
-					map[string]interface{}{"id": destUserID})
+				added, err := assoc.associate(fmt.Sprintf("%sorganizations/%d/users/", prefix, destOrgID), destUserID)
+				if err != nil {
+					logger(fmt.Sprintf("  FAIL: %s/%s: %v", orgName, username, err))
+				} else if !added {
+					skipped++
+				}
 			}
 		}
 		if len(data.OrgUsers[srcOrgID]) > 0 {
-			logger(fmt.Sprintf("  %s: %d users", orgName, len(data.OrgUsers[srcOrgID])))
+			logger(fmt.Sprintf("  %s: %d users (%d already associated)", orgName, len(data.OrgUsers[srcOrgID]), skipped))
 		}
 	}
 
-	// 15. User-team associations
+	// 19. User-team associations
 	logger("=== Importing user-team associations ===")
+	progress.step()
 	for _, team := range data.Teams {
 		srcTeamID := resourceID(team)
 		teamName := resourceName(team)
@@ -766,17 +1076,42 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 		if destTeamID == 0 {
 			continue
 		}
+		var skipped int
 		for _, username := range data.TeamUsers[srcTeamID] {
 			if destUserID := ids.users[username]; destUserID != 0 {
-				dst.Post(fmt.Sprintf("%steams/%d/users/", prefix, destTeamID),
-					map[string]interface{}{"id": destUserID})
+				added, err := assoc.associate(fmt.Sprintf("%steams/%d/users/", prefix, destTeamID), destUserID)
+				if err != nil {
+					logger(fmt.Sprintf("  FAIL: %s/%s: %v", teamName, username, err))
+				} else if !added {
+					skipped++
+				}
 			}
 		}
 		if len(data.TeamUsers[srcTeamID]) > 0 {
-			logger(fmt.Sprintf("  %s: %d users", teamName, len(data.TeamUsers[srcTeamID])))
+			logger(fmt.Sprintf("  %s: %d users (%d already associated)", teamName, len(data.TeamUsers[srcTeamID]), skipped))
 		}
 	}
 
+	// 20. Instance group assignments (groups must already exist on the destination)
+	if len(data.InstanceGroups) > 0 {
+		logger("=== Importing instance group assignments ===")
+		progress.step()
+		importInstanceGroups(dst, prefix, data, ids, assoc, logger)
+	}
+
+	// 21. Role assignments (every object, team and user must exist)
+	if ctx.Err() != nil {
+		logger("Migration cancelled by user")
+		return ctx.Err()
+	}
+	if len(data.RoleAssignments) > 0 {
+		logger("")
+		logger("=== Importing role assignments ===")
+		progress.step()
+		importRoleAssignments(dst, prefix, data, ids, assoc, logger)
+	}
+
+	progress.done()
 	logger("")
 	logger("=== Migration complete ===")
 	return nil
@@ -786,9 +1121,8 @@ func importAll(ctx context.Context, dst *platform.Client, prefix, dstType string
 func createResource(client *platform.Client, path string, payload map[string]interface{}) (int, error) {
 	body, statusCode, err := client.Post(path, payload)
 	if err != nil {
-		return 0, err
+		return 0, rejectionError(path, statusCode, body, payload, err)
 	}
-	_ = statusCode
 
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
@@ -797,6 +1131,94 @@ func createResource(client *platform.Client, path string, payload map[string]int
 	return toInt(result["id"]), nil
 }
 
+// rejectionError names the resource type and the payload fields a 400
+// response complained about, e.g. "job_templates rejected inventory: POST ...".
+// Other errors are returned unchanged.
+func rejectionError(path string, statusCode int, body []byte, payload map[string]interface{}, err error) error {
+	if statusCode != http.StatusBadRequest {
+		return err
+	}
+	var detail map[string]interface{}
+	if json.Unmarshal(body, &detail) != nil {
+		return err
+	}
+	var fields []string
+	for k := range detail {
+		if _, ok := payload[k]; ok {
+			fields = append(fields, k)
+		}
+	}
+	if len(fields) == 0 {
+		return err
+	}
+	sort.Strings(fields)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	return fmt.Errorf("%s rejected %s: %w", segments[len(segments)-1], strings.Join(fields, ", "), err)
+}
+
+// applyResource creates a resource, or PATCHes the existing destination
+// object when the preview action is "update". It returns the destination ID
+// and the verb to log.
+func applyResource(client *platform.Client, path, action string, destID int, payload map[string]interface{}) (int, string, error) {
+	if action == "update" {
+		if body, statusCode, err := client.Patch(fmt.Sprintf("%s%d/", path, destID), payload); err != nil {
+			return 0, "", rejectionError(path, statusCode, body, payload, err)
+		}
+		return destID, "UPDATED", nil
+	}
+	id, err := createResource(client, path, payload)
+	return id, "CREATED", err
+}
+
+// associator POSTs sub-resource associations ({"id": N} on a list endpoint),
+// skipping members that are already present so re-running a migration does
+// not repeat associations.
+type associator struct {
+	client  *platform.Client
+	mu      sync.Mutex              // guards members; associate may run concurrently
+	members map[string]map[int]bool // list path → member IDs
+}
+
+func newAssociator(client *platform.Client) *associator {
+	return &associator{client: client, members: make(map[string]map[int]bool)}
+}
+
+// associate adds id to the list at path unless it is already a member.
+// Returns true if a POST was made, false if the association already existed.
+func (a *associator) associate(path string, id int) (bool, error) {
+	a.mu.Lock()
+	existing, ok := a.members[path]
+	if !ok {
+		existing = make(map[int]bool)
+		current, err := a.client.GetAll(path)
+		if err == nil {
+			for _, m := range current {
+				existing[resourceID(m)] = true
+			}
+		}
+		a.members[path] = existing
+	}
+	member := existing[id]
+	a.mu.Unlock()
+	if member {
+		return false, nil
+	}
+	if _, _, err := a.client.Post(path, map[string]interface{}{"id": id}); err != nil {
+		return false, err
+	}
+	a.mu.Lock()
+	existing[id] = true
+	a.mu.Unlock()
+	return true, nil
+}
+
+// hostResult is the outcome of creating or finding one host.
+type hostResult struct {
+	name  string
+	id    int      // destination ID, 0 if excluded or failed
+	lines []string // log lines, written once the inventory is done
+}
+
 // wireEdges connects workflow node edges (success_nodes, failure_nodes, always_nodes).
 func wireEdges(dst *platform.Client, prefix string, destNodeID int, node models.Resource, edgeType string, ids *idMap) {
 	edges, ok := node[edgeType].([]interface{})
@@ -819,51 +1241,74 @@ func wireEdges(dst *platform.Client, prefix string, destNodeID int, node models.
 	}
 }
 
-// waitForProject polls project status until sync completes or times out.
-func waitForProject(client *platform.Client, prefix string, id int, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		var proj map[string]interface{}
-		err := client.GetJSON(fmt.Sprintf("%sprojects/%d/", prefix, id), nil, &proj)
+// labeler finds or creates labels on the destination and attaches them to
+// job templates and workflows. Labels are scoped to an organization, so an
+// existing label with the same name in the same organization is reused.
+type labeler struct {
+	client *platform.Client
+	prefix string
+	assoc  *associator
+	known  map[string]int // "orgID/name" → dest label ID
+}
+
+func newLabeler(client *platform.Client, prefix string, assoc *associator) *labeler {
+	return &labeler{client: client, prefix: prefix, assoc: assoc, known: make(map[string]int)}
+}
+
+// labelID returns the destination ID of the named label in orgID, creating
+// the label if it does not exist yet.
+func (l *labeler) labelID(orgID int, name string) (int, error) {
+	key := fmt.Sprintf("%d/%s", orgID, name)
+	if id, ok := l.known[key]; ok {
+		return id, nil
+	}
+	var page struct {
+		Results []models.Resource `json:"results"`
+	}
+	params := url.Values{"name": {name}, "organization": {strconv.Itoa(orgID)}}
+	if err := l.client.GetJSON(l.prefix+"labels/", params, &page); err != nil {
+		return 0, err
+	}
+	var id int
+	if len(page.Results) > 0 {
+		id = resourceID(page.Results[0])
+	} else {
+		created, err := createResource(l.client, l.prefix+"labels/", map[string]interface{}{
+			"name":         name,
+			"organization": orgID,
+		})
 		if err != nil {
-			return err
-		}
-		status, _ := proj["status"].(string)
-		switch status {
-		case "successful":
-			return nil
-		case "failed", "error", "canceled":
-			return fmt.Errorf("project sync status: %s", status)
+			return 0, err
 		}
-		time.Sleep(3 * time.Second)
+		id = created
 	}
-	return fmt.Errorf("timeout waiting for project sync")
+	l.known[key] = id
+	return id, nil
 }
 
-// waitForProjectCtx is like waitForProject but respects context cancellation.
-func waitForProjectCtx(ctx context.Context, client *platform.Client, prefix string, id int, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		if ctx.Err() != nil {
-			return ctx.Err()
+// attach associates the given source labels with the object at listPath.
+// Each label keeps its own organization when that was migrated, otherwise
+// it is created in the parent's organization (defaultOrg).
+func (l *labeler) attach(listPath string, labels []models.Resource, defaultOrg int, ids *idMap, logger func(string)) {
+	for _, label := range labels {
+		name := resourceName(label)
+		orgID := ids.orgs[extractOrgName(label)]
+		if orgID == 0 {
+			orgID = defaultOrg
 		}
-		var proj map[string]interface{}
-		err := client.GetJSON(fmt.Sprintf("%sprojects/%d/", prefix, id), nil, &proj)
-		if err != nil {
-			return err
+		if orgID == 0 {
+			logger(fmt.Sprintf("    SKIP label %s: organization not found", name))
+			continue
 		}
-		status, _ := proj["status"].(string)
-		switch status {
-		case "successful":
-			return nil
-		case "failed", "error", "canceled":
-			return fmt.Errorf("project sync status: %s", status)
+		labelID, err := l.labelID(orgID, name)
+		if err != nil {
+			logger(fmt.Sprintf("    FAIL label %s: %v", name, err))
+			continue
 		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(3 * time.Second):
+		if _, err := l.assoc.associate(listPath, labelID); err != nil {
+			logger(fmt.Sprintf("    FAIL label %s: %v", name, err))
+			continue
 		}
+		logger(fmt.Sprintf("    LABEL: %s", name))
 	}
-	return fmt.Errorf("timeout waiting for project sync")
 }
@@ -0,0 +1,653 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// newTestConnection returns an AWX connection pointed at the given test server.
+func newTestConnection(t testing.TB, ts *httptest.Server) *models.Connection {
+	t.Helper()
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	return &models.Connection{
+		Type:     "awx",
+		Scheme:   u.Scheme,
+		Host:     host,
+		Port:     port,
+		Username: "admin",
+		Password: "secret",
+	}
+}
+
+// newTestClient returns a platform client pointed at the given test server.
+func newTestClient(t testing.TB, ts *httptest.Server) *platform.Client {
+	t.Helper()
+	return platform.NewClient(newTestConnection(t, ts))
+}
+
+// membershipServer fakes a single association list endpoint that records
+// every POST and rejects duplicates the way some controller endpoints do.
+type membershipServer struct {
+	mu      sync.Mutex
+	members map[int]bool
+	posts   int
+}
+
+func (m *membershipServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch r.Method {
+	case "GET":
+		var results []map[string]interface{}
+		for id := range m.members {
+			results = append(results, map[string]interface{}{"id": id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count": len(results), "next": nil, "results": results,
+		})
+	case "POST":
+		var body struct {
+			ID int `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		m.posts++
+		if m.members[body.ID] {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"msg":"already associated"}`))
+			return
+		}
+		m.members[body.ID] = true
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestAssociator_SkipsExistingMembers(t *testing.T) {
+	srv := &membershipServer{members: map[int]bool{7: true}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	a := newAssociator(newTestClient(t, ts))
+	path := "/api/v2/teams/1/users/"
+
+	added, err := a.associate(path, 7)
+	if err != nil {
+		t.Fatalf("associate(existing) returned error: %v", err)
+	}
+	if added {
+		t.Error("associate(existing) should report no POST")
+	}
+
+	added, err = a.associate(path, 8)
+	if err != nil {
+		t.Fatalf("associate(new) returned error: %v", err)
+	}
+	if !added {
+		t.Error("associate(new) should report a POST")
+	}
+	if srv.posts != 1 {
+		t.Errorf("posts = %d, want 1", srv.posts)
+	}
+}
+
+func TestAssociator_RerunIsIdempotent(t *testing.T) {
+	srv := &membershipServer{members: map[int]bool{}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := newTestClient(t, ts)
+	path := "/api/v2/organizations/1/users/"
+
+	// Two independent runs, as when a migration is executed twice.
+	for run := 0; run < 2; run++ {
+		a := newAssociator(client)
+		for _, id := range []int{1, 2, 3, 2} {
+			if _, err := a.associate(path, id); err != nil {
+				t.Fatalf("run %d: associate(%d) returned error: %v", run, id, err)
+			}
+		}
+	}
+	if srv.posts != 3 {
+		t.Errorf("posts = %d, want 3 (one per distinct member)", srv.posts)
+	}
+}
+
+// patchServer answers every GET with an empty list and records PATCH bodies.
+type patchServer struct {
+	mu      sync.Mutex
+	patches map[string]map[string]interface{} // path → body
+	posts   int
+}
+
+func (p *patchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch r.Method {
+	case "GET":
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	case "PATCH":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		p.patches[r.URL.Path] = body
+		w.Write([]byte(`{}`))
+	case "POST":
+		p.posts++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":99}`))
+	}
+}
+
+func TestImportAll_UpdatesExistingJobTemplate(t *testing.T) {
+	srv := &patchServer{patches: make(map[string]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := &ExportedData{
+		JobTemplates: []models.Resource{
+			{"id": float64(12), "name": "Deploy", "playbook": "deploy_v2.yml", "job_type": "run"},
+		},
+	}
+	preview := &models.MigrationPreview{
+		Resources: map[string][]models.MigrationResource{
+			"job_templates": {{SourceID: 12, Name: "Deploy", Type: "job_templates", Action: "update", DestID: 5}},
+		},
+	}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	body, ok := srv.patches["/api/v2/job_templates/5/"]
+	if !ok {
+		t.Fatalf("expected PATCH on existing job template, got %v", srv.patches)
+	}
+	if body["playbook"] != "deploy_v2.yml" {
+		t.Errorf("patched playbook = %v, want deploy_v2.yml", body["playbook"])
+	}
+	if srv.posts != 0 {
+		t.Errorf("got %d POSTs, want none for an updated resource", srv.posts)
+	}
+	if !containsLine(logs, "  UPDATED: Deploy (ID 5)") {
+		t.Errorf("missing UPDATED log line in %q", logs)
+	}
+}
+
+func TestImportAll_ReportsProgress(t *testing.T) {
+	srv := &patchServer{patches: make(map[string]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := &ExportedData{
+		Organizations: []models.Resource{{"id": float64(1), "name": "Eng"}},
+		Projects:      []models.Resource{{"id": float64(2), "name": "Playbooks"}},
+		JobTemplates:  []models.Resource{{"id": float64(3), "name": "Deploy"}},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+
+	var reports [][2]int
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil,
+		func(completed, total int) { reports = append(reports, [2]int{completed, total}) },
+		func(string) {})
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	want := importPhases + 3
+	if len(reports) == 0 {
+		t.Fatal("no progress reported")
+	}
+	prev := -1
+	for _, r := range reports {
+		if r[1] != want {
+			t.Fatalf("total = %d, want %d", r[1], want)
+		}
+		if r[0] < prev {
+			t.Fatalf("progress went backwards: %v", reports)
+		}
+		prev = r[0]
+	}
+	if last := reports[len(reports)-1]; last[0] != want {
+		t.Errorf("final progress = %d/%d, want %d/%d", last[0], last[1], want, want)
+	}
+}
+
+func TestPreflightCheck_ConflictStrategy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every name lookup finds an existing object with ID 5.
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": 1, "next": nil,
+			"results": []map[string]interface{}{{"id": 5, "name": r.URL.Query().Get("name")}}})
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		Organizations: []models.Resource{{"id": float64(1), "name": "Eng"}},
+		Teams:         []models.Resource{{"id": float64(2), "name": "Ops"}},
+		JobTemplates:  []models.Resource{{"id": float64(3), "name": "Deploy"}},
+	}
+	conflicts := map[string]string{"job_templates": "update", "teams": "update"}
+	preview, err := preflightCheck(data, newTestClient(t, ts), "/api/v2/", conflicts, nil, nil, func(string) {})
+	if err != nil {
+		t.Fatalf("preflightCheck returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"organizations": "skip_exists", // no strategy given
+		"teams":         "skip_exists", // not an updatable type
+		"job_templates": "update",
+	}
+	for rt, action := range want {
+		if got := preview.Resources[rt][0].Action; got != action {
+			t.Errorf("%s action = %q, want %q", rt, got, action)
+		}
+	}
+}
+
+func TestValidateConflicts(t *testing.T) {
+	if err := ValidateConflicts(map[string]string{"job_templates": "update", "projects": "skip"}); err != nil {
+		t.Errorf("valid conflicts rejected: %v", err)
+	}
+	for _, conflicts := range []map[string]string{
+		{"job_templates": "overwrite"},
+		{"projects": "update"},
+		{"widgets": "skip"},
+	} {
+		if err := ValidateConflicts(conflicts); err == nil {
+			t.Errorf("ValidateConflicts(%v) accepted", conflicts)
+		}
+	}
+}
+
+func TestPreflightCheck_Counts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	host := func(id int) models.Resource {
+		return models.Resource{"id": float64(id), "name": "h" + strconv.Itoa(id)}
+	}
+	data := &ExportedData{
+		Inventories: []models.Resource{
+			{"id": float64(1), "name": "Prod"},
+			{"id": float64(2), "name": "Lab"},
+		},
+		Hosts: map[int][]models.Resource{
+			1: {host(10), host(11), host(12)},
+			2: {host(20)},
+		},
+		Groups: map[int][]models.Resource{
+			1: {{"id": float64(30), "name": "web"}, {"id": float64(31), "name": "db"}},
+		},
+		Disabled: map[string]int{"hosts": 2},
+	}
+	preview, err := preflightCheck(data, newTestClient(t, ts), "/api/v2/", nil, nil, nil, func(string) {})
+	if err != nil {
+		t.Fatalf("preflightCheck returned error: %v", err)
+	}
+
+	for inv, want := range map[string]int{"Prod": 3, "Lab": 1} {
+		if got := preview.HostCounts[inv]; got != want {
+			t.Errorf("HostCounts[%s] = %d, want %d", inv, got, want)
+		}
+	}
+	if got := preview.GroupCounts["Prod"]; got != 2 {
+		t.Errorf("GroupCounts[Prod] = %d, want 2", got)
+	}
+	if _, ok := preview.GroupCounts["Lab"]; ok {
+		t.Error("Lab has no groups but has a group count")
+	}
+	// Disabled hosts were dropped at export and are not in the totals.
+	for rt, want := range map[string]int{"inventories": 2, "hosts": 4, "groups": 2} {
+		if got := preview.Totals[rt]; got != want {
+			t.Errorf("Totals[%s] = %d, want %d", rt, got, want)
+		}
+	}
+	if preview.Excluded["hosts"] != 2 {
+		t.Errorf("Excluded[hosts] = %d, want 2", preview.Excluded["hosts"])
+	}
+}
+
+func TestPreflightCheck_DuplicateNames(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	jt := func(id int, name, org string) models.Resource {
+		return models.Resource{"id": float64(id), "name": name,
+			"summary_fields": map[string]interface{}{"organization": map[string]interface{}{"name": org}}}
+	}
+	data := &ExportedData{
+		JobTemplates: []models.Resource{jt(1, "deploy", "Ops"), jt(2, "Backup", "Ops"), jt(3, "deploy", "Eng")},
+		// Hosts are mapped per inventory, so shared names are fine.
+		Inventories: []models.Resource{{"id": float64(1), "name": "Prod"}, {"id": float64(2), "name": "Lab"}},
+		Hosts: map[int][]models.Resource{
+			1: {{"id": float64(10), "name": "web1"}},
+			2: {{"id": float64(20), "name": "web1"}},
+		},
+	}
+	var logs []string
+	preview, err := preflightCheck(data, newTestClient(t, ts), "/api/v2/", nil, nil, nil, func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("preflightCheck returned error: %v", err)
+	}
+
+	want := `job_templates "deploy" (Eng, Ops)`
+	if got := duplicateNames(data); len(got) != 1 || got[0] != want {
+		t.Errorf("duplicateNames = %q, want [%q]", got, want)
+	}
+	if !containsLine(logs, "  WARNING: duplicate name: "+want) {
+		t.Errorf("missing duplicate warning in log %q", logs)
+	}
+	found := false
+	for _, w := range preview.Warnings {
+		found = found || strings.Contains(w, want)
+	}
+	if !found {
+		t.Errorf("preview warnings %q do not mention %s", preview.Warnings, want)
+	}
+}
+
+func TestPreflightCheck_Exclude(t *testing.T) {
+	var mu sync.Mutex
+	var lookups []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lookups = append(lookups, r.URL.Path+" "+r.URL.Query().Get("name"))
+		mu.Unlock()
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{Projects: []models.Resource{
+		{"id": float64(1), "name": "Playbooks"},
+		{"id": float64(2), "name": "Scratch"},
+	}}
+	exclude := map[string][]string{"projects": {"Scratch"}}
+	preview, err := preflightCheck(data, newTestClient(t, ts), "/api/v2/", nil, exclude, nil, func(string) {})
+	if err != nil {
+		t.Fatalf("preflightCheck returned error: %v", err)
+	}
+
+	actions := make(map[string]string)
+	for _, mr := range preview.Resources["projects"] {
+		actions[mr.Name] = mr.Action
+	}
+	if actions["Playbooks"] != "create" || actions["Scratch"] != "skip_excluded" {
+		t.Errorf("actions = %v, want Playbooks create and Scratch skip_excluded", actions)
+	}
+	if preview.Totals["projects"] != 1 || preview.Excluded["projects"] != 1 {
+		t.Errorf("Totals/Excluded[projects] = %d/%d, want 1/1", preview.Totals["projects"], preview.Excluded["projects"])
+	}
+	for _, l := range lookups {
+		if l == "/api/v2/projects/ Scratch" {
+			t.Error("excluded project was looked up on the destination")
+		}
+	}
+}
+
+func TestImportAll_PreviewExclusion(t *testing.T) {
+	srv := &postRecorder{nextID: 100, posts: make(map[string][]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	org := map[string]interface{}{"organization": map[string]interface{}{"name": "Eng"}}
+	data := newExportedData()
+	data.Organizations = []models.Resource{{"id": float64(1), "name": "Eng"}}
+	data.Projects = []models.Resource{
+		{"id": float64(2), "name": "Playbooks", "scm_type": "", "summary_fields": org},
+		{"id": float64(3), "name": "Scratch", "scm_type": "", "summary_fields": org},
+	}
+	// The run is given no exclusions; the preview's are applied.
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{
+		"projects": {{Name: "Playbooks", Action: "create"}, {Name: "Scratch", Action: "skip_excluded"}},
+	}}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview,
+		ImportOptions{}, nil, nil, func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	posts := srv.posts["/api/v2/projects/"]
+	if len(posts) != 1 || posts[0]["name"] != "Playbooks" {
+		t.Errorf("projects created = %v, want only Playbooks", posts)
+	}
+	if !containsLine(logs, "  EXCLUDED: Scratch (user exclusion)") {
+		t.Errorf("missing EXCLUDED line in %q", logs)
+	}
+}
+
+func TestMergeExclusions(t *testing.T) {
+	got := MergeExclusions(
+		map[string][]string{"projects": {"Demo Project"}},
+		nil,
+		map[string][]string{"projects": {"Scratch", "Demo Project"}, "hosts": {"web1"}},
+	)
+	want := map[string][]string{"projects": {"Demo Project", "Scratch"}, "hosts": {"web1"}}
+	if len(got) != len(want) {
+		t.Fatalf("MergeExclusions = %v, want %v", got, want)
+	}
+	for typeName, names := range want {
+		if strings.Join(got[typeName], ",") != strings.Join(names, ",") {
+			t.Errorf("%s = %v, want %v", typeName, got[typeName], names)
+		}
+	}
+}
+
+func TestPreflightCheck_TotalsIncludeEmptyHostsAndGroups(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		Types:       []string{"organizations", "inventories", "hosts", "groups"},
+		Inventories: []models.Resource{{"id": float64(1), "name": "Empty"}},
+	}
+	preview, err := preflightCheck(data, newTestClient(t, ts), "/api/v2/", nil, nil, nil, func(string) {})
+	if err != nil {
+		t.Fatalf("preflightCheck returned error: %v", err)
+	}
+	for _, rt := range []string{"hosts", "groups"} {
+		if n, ok := preview.Totals[rt]; !ok || n != 0 {
+			t.Errorf("Totals[%s] = %d (present %v), want 0", rt, n, ok)
+		}
+	}
+	if _, ok := preview.Totals["job_templates"]; ok {
+		t.Error("unselected job_templates are in the totals")
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, l := range lines {
+		if l == want {
+			return true
+		}
+	}
+	return false
+}
+
+// labelServer fakes a destination with organization 1 that already has a
+// "prod" label (ID 50). New labels get ID 51+, the job template gets ID 5.
+type labelServer struct {
+	mu       sync.Mutex
+	nextID   int
+	created  []string // label names POSTed to /labels/
+	attached []int    // label IDs POSTed to /job_templates/5/labels/
+}
+
+func (l *labelServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	empty := `{"count":0,"next":null,"results":[]}`
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/api/v2/labels/":
+		if r.URL.Query().Get("name") == "prod" && r.URL.Query().Get("organization") == "1" {
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":50,"name":"prod"}]}`))
+			return
+		}
+		w.Write([]byte(empty))
+	case r.Method == "GET":
+		w.Write([]byte(empty))
+	case r.URL.Path == "/api/v2/labels/":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		l.created = append(l.created, body["name"].(string))
+		l.nextID++
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]int{"id": 50 + l.nextID})
+	case r.URL.Path == "/api/v2/job_templates/":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":5}`))
+	case r.URL.Path == "/api/v2/job_templates/5/labels/":
+		var body struct {
+			ID int `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		l.attached = append(l.attached, body.ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}
+}
+
+func TestImportAll_JobTemplateLabels(t *testing.T) {
+	srv := &labelServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	org := map[string]interface{}{"organization": map[string]interface{}{"name": "Eng"}}
+	data := &ExportedData{
+		Organizations: []models.Resource{{"id": float64(1), "name": "Eng"}},
+		JobTemplates: []models.Resource{
+			{"id": float64(12), "name": "Deploy", "summary_fields": org},
+		},
+		Labels: map[int][]models.Resource{
+			12: {
+				{"id": float64(3), "name": "prod", "summary_fields": org},
+				{"id": float64(4), "name": "web", "summary_fields": org},
+			},
+		},
+	}
+	preview := &models.MigrationPreview{
+		Resources: map[string][]models.MigrationResource{
+			"organizations": {{Name: "Eng", Action: "skip_exists", DestID: 1}},
+		},
+	}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	if len(srv.created) != 1 || srv.created[0] != "web" {
+		t.Errorf("created labels = %v, want [web] (prod already exists)", srv.created)
+	}
+	if len(srv.attached) != 2 || srv.attached[0] != 50 || srv.attached[1] != 51 {
+		t.Errorf("attached label IDs = %v, want [50 51]", srv.attached)
+	}
+	for _, want := range []string{"    LABEL: prod", "    LABEL: web"} {
+		if !containsLine(logs, want) {
+			t.Errorf("missing %q in logs %q", want, logs)
+		}
+	}
+}
+
+func TestImportAll_LogsRejectedFields(t *testing.T) {
+	detail := strings.Repeat("x", 300)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && r.URL.Path == "/api/v2/organizations/" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"description":["` + detail + `"],"max_hosts":["Ensure this value is greater than or equal to 0."]}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		Organizations: []models.Resource{{"id": float64(1), "name": "Eng", "description": "Engineering"}},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	var fail string
+	for _, l := range logs {
+		if strings.HasPrefix(l, "  FAIL: Eng:") {
+			fail = l
+		}
+	}
+	if !strings.HasPrefix(fail, "  FAIL: Eng: organizations rejected description: POST /api/v2/organizations/: HTTP 400") {
+		t.Errorf("FAIL line = %q, want the rejected description field", fail)
+	}
+	if !strings.Contains(fail, detail) {
+		t.Errorf("FAIL line truncated the validation detail: %q", fail)
+	}
+}
+
+func TestImportAll_StableLogOrder(t *testing.T) {
+	data := &ExportedData{Hosts: make(map[int][]models.Resource), Groups: make(map[int][]models.Resource)}
+	for i := 1; i <= 8; i++ {
+		name := "inv" + strconv.Itoa(i)
+		data.Inventories = append(data.Inventories, models.Resource{"id": float64(i), "name": name})
+		data.Hosts[i] = []models.Resource{{"id": float64(100 + i), "name": "host" + strconv.Itoa(i)}}
+		data.Groups[i] = []models.Resource{{"id": float64(200 + i), "name": "group" + strconv.Itoa(i)}}
+	}
+
+	run := func() []string {
+		srv := newCollectionServer()
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+		var logs []string
+		err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+			func(s string) { logs = append(logs, s) })
+		if err != nil {
+			t.Fatalf("importAll returned error: %v", err)
+		}
+		return logs
+	}
+
+	first := run()
+	for i := 0; i < 5; i++ {
+		if got := run(); strings.Join(got, "\n") != strings.Join(first, "\n") {
+			t.Fatalf("run %d logged in a different order:\n%s\n\nfirst run:\n%s", i+2, strings.Join(got, "\n"), strings.Join(first, "\n"))
+		}
+	}
+	var hostLines []string
+	for _, l := range first {
+		if strings.HasSuffix(l, ": 1 hosts") {
+			hostLines = append(hostLines, l)
+		}
+	}
+	if len(hostLines) != 8 || hostLines[0] != "  inv1: 1 hosts" || hostLines[7] != "  inv8: 1 hosts" {
+		t.Errorf("host lines = %q, want inv1..inv8 in order", hostLines)
+	}
+}
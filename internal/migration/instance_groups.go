@@ -0,0 +1,116 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// instanceGroupTypes are the resource types whose instance group
+// assignments are migrated, in import order.
+var instanceGroupTypes = []string{"organizations", "inventories", "job_templates", "workflow_job_templates"}
+
+// instanceGroupOwners returns the exported resources of an instance group type.
+func instanceGroupOwners(data *ExportedData, typeName string) []models.Resource {
+	switch typeName {
+	case "organizations":
+		return data.Organizations
+	case "inventories":
+		return data.Inventories
+	case "job_templates":
+		return data.JobTemplates
+	case "workflow_job_templates":
+		return data.WorkflowJTs
+	}
+	return nil
+}
+
+// destIDFor returns the migrated destination ID of a named resource.
+func (m *idMap) destIDFor(typeName, name string) int {
+	switch typeName {
+	case "organizations":
+		return m.orgs[name]
+	case "credentials":
+		return m.creds[name]
+	case "projects":
+		return m.projects[name]
+	case "inventories":
+		return m.invs[name]
+	case "job_templates":
+		return m.jts[name]
+	case "workflow_job_templates":
+		return m.wfjts[name]
+	}
+	return 0
+}
+
+// exportInstanceGroups records, in preference order, the names of the
+// instance groups assigned to each exported org, inventory, JT and WFJT.
+func exportInstanceGroups(client *platform.Client, prefix string, data *ExportedData, logger func(string)) {
+	logger("Exporting instance group assignments...")
+	var count int
+	for _, rt := range instanceGroupTypes {
+		for _, r := range instanceGroupOwners(data, rt) {
+			srcID := resourceID(r)
+			igs, err := client.GetAll(fmt.Sprintf("%s%s/%d/instance_groups/", prefix, rt, srcID))
+			if err != nil || len(igs) == 0 {
+				continue
+			}
+			if data.InstanceGroups[rt] == nil {
+				data.InstanceGroups[rt] = make(map[int][]string)
+			}
+			for _, ig := range igs {
+				data.InstanceGroups[rt][srcID] = append(data.InstanceGroups[rt][srcID], resourceName(ig))
+			}
+			count++
+		}
+	}
+	logger(fmt.Sprintf("  %d resources with instance groups", count))
+}
+
+// importInstanceGroups assigns migrated resources to the instance groups they
+// used on the source. Instance groups are infrastructure and are never
+// created; names missing on the destination are reported and skipped.
+func importInstanceGroups(dst *platform.Client, prefix string, data *ExportedData, ids *idMap, assoc *associator, logger func(string)) {
+	destGroups := make(map[string]int)
+	all, err := dst.GetAll(prefix + "instance_groups/")
+	if err != nil {
+		logger(fmt.Sprintf("  FAIL: listing instance groups: %v", err))
+		return
+	}
+	for _, ig := range all {
+		destGroups[resourceName(ig)] = resourceID(ig)
+	}
+
+	missing := make(map[string]bool)
+	for _, rt := range instanceGroupTypes {
+		for _, r := range instanceGroupOwners(data, rt) {
+			names := data.InstanceGroups[rt][resourceID(r)]
+			if len(names) == 0 {
+				continue
+			}
+			name := resourceName(r)
+			destID := ids.destIDFor(rt, name)
+			if destID == 0 {
+				continue
+			}
+			for _, igName := range names {
+				igID, ok := destGroups[igName]
+				if !ok {
+					if !missing[igName] {
+						logger(fmt.Sprintf("  WARNING: instance group %q not found on destination", igName))
+						missing[igName] = true
+					}
+					logger(fmt.Sprintf("  SKIP: %s %s → %s (instance group missing)", rt, name, igName))
+					continue
+				}
+				if _, err := assoc.associate(fmt.Sprintf("%s%s/%d/instance_groups/", prefix, rt, destID), igID); err != nil {
+					logger(fmt.Sprintf("  FAIL: %s %s → %s: %v", rt, name, igName, err))
+					continue
+				}
+				logger(fmt.Sprintf("  %s %s → %s", rt, name, igName))
+			}
+		}
+	}
+}
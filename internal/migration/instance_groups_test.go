@@ -0,0 +1,92 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// instanceGroupServer fakes a destination with the "default" (1) and
+// "gpu-nodes" (4) instance groups, recording assignments on job template 5.
+type instanceGroupServer struct {
+	mu       sync.Mutex
+	assigned []int
+}
+
+func (s *instanceGroupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/api/v2/instance_groups/":
+		w.Write([]byte(`{"count":2,"next":null,"results":[{"id":1,"name":"default"},{"id":4,"name":"gpu-nodes"}]}`))
+	case r.Method == "GET":
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	case r.URL.Path == "/api/v2/job_templates/":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":5}`))
+	case r.URL.Path == "/api/v2/job_templates/5/instance_groups/":
+		var body struct {
+			ID int `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		s.assigned = append(s.assigned, body.ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}
+}
+
+func TestImportAll_JobTemplateInstanceGroups(t *testing.T) {
+	srv := &instanceGroupServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	data := &ExportedData{
+		JobTemplates: []models.Resource{{"id": float64(12), "name": "Train model"}},
+		InstanceGroups: map[string]map[int][]string{
+			"job_templates": {12: {"gpu-nodes", "edge"}},
+		},
+	}
+	preview := &models.MigrationPreview{}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	if len(srv.assigned) != 1 || srv.assigned[0] != 4 {
+		t.Errorf("assigned instance groups = %v, want [4]", srv.assigned)
+	}
+	for _, want := range []string{
+		"  job_templates Train model → gpu-nodes",
+		`  WARNING: instance group "edge" not found on destination`,
+	} {
+		if !containsLine(logs, want) {
+			t.Errorf("missing %q in logs %q", want, logs)
+		}
+	}
+}
+
+func TestMissingInstanceGroups(t *testing.T) {
+	ts := httptest.NewServer(&instanceGroupServer{})
+	defer ts.Close()
+
+	data := &ExportedData{
+		InstanceGroups: map[string]map[int][]string{
+			"job_templates": {12: {"gpu-nodes", "edge"}},
+			"inventories":   {3: {"edge", "default"}},
+		},
+	}
+	missing := missingInstanceGroups(data, newTestClient(t, ts), "/api/v2/")
+	if len(missing) != 1 || missing[0] != "edge" {
+		t.Errorf("missing = %v, want [edge]", missing)
+	}
+}
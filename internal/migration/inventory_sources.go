@@ -0,0 +1,123 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// inventorySourceFields are copied verbatim from source to destination.
+var inventorySourceFields = []string{
+	"description", "source", "source_path", "source_vars", "scm_branch",
+	"overwrite", "overwrite_vars", "update_on_launch", "update_cache_timeout",
+	"timeout", "verbosity", "enabled_var", "enabled_value", "host_filter",
+}
+
+// exportInventorySources fetches the inventory sources (cloud, SCM and
+// other dynamic inventory definitions) of every exported inventory.
+func exportInventorySources(client *platform.Client, prefix string, data *ExportedData, logger func(string)) {
+	logger("Exporting inventory sources...")
+	var count int
+	for _, inv := range data.Inventories {
+		// The source of a constructed inventory is created along with it
+		if isConstructed(inv) {
+			continue
+		}
+		invID := resourceID(inv)
+		sources, err := client.GetAll(fmt.Sprintf("%sinventories/%d/inventory_sources/", prefix, invID))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get inventory sources for %s: %v", resourceName(inv), err))
+			continue
+		}
+		if len(sources) > 0 {
+			data.InventorySources[invID] = sources
+			count += len(sources)
+		}
+	}
+	logger(fmt.Sprintf("  %d inventory sources", count))
+}
+
+// importInventorySources recreates inventory sources on the migrated
+// inventories. The credential and, for SCM sources, the project are resolved
+// by name among migrated resources first, then on the destination. Sources
+// are not synced; the first update runs on launch or when started manually.
+func importInventorySources(dst *platform.Client, prefix string, data *ExportedData, exclude map[string][]string, ids *idMap, progress *progressTracker, logger func(string)) {
+	for _, inv := range data.Inventories {
+		sources := data.InventorySources[resourceID(inv)]
+		invName := resourceName(inv)
+		destInvID := ids.invs[invName]
+		if destInvID == 0 || isExcluded(exclude, "inventories", invName) {
+			continue
+		}
+		listPath := fmt.Sprintf("%sinventories/%d/inventory_sources/", prefix, destInvID)
+		for _, src := range sources {
+			progress.step()
+			name := resourceName(src)
+			label := invName + "/" + name
+			if existing, err := dst.FindByName(listPath, name); err == nil && existing != nil {
+				logger(fmt.Sprintf("  SKIP (exists): %s", label))
+				continue
+			}
+
+			payload := map[string]interface{}{"name": name}
+			for _, f := range inventorySourceFields {
+				if v, ok := src[f]; ok && v != nil {
+					payload[f] = v
+				}
+			}
+			if credName := extractSCMCredName(src); credName != "" {
+				credID := findMigrated(dst, prefix+"credentials/", ids.creds, credName)
+				if credID == 0 {
+					logger(fmt.Sprintf("  WARNING: %s: credential %q not found, source created without it", label, credName))
+				} else {
+					payload["credential"] = credID
+				}
+			}
+			if projName := stringOf(summaryField(src, "source_project", "name")); projName != "" {
+				projID := findMigrated(dst, prefix+"projects/", ids.projects, projName)
+				if projID == 0 {
+					logger(fmt.Sprintf("  FAIL: %s: source project %q not found", label, projName))
+					continue
+				}
+				payload["source_project"] = projID
+			}
+			if eeID := ids.ees[extractEEName(src)]; eeID != 0 {
+				payload["execution_environment"] = eeID
+			}
+
+			id, err := createResource(dst, listPath, payload)
+			if err != nil {
+				logger(fmt.Sprintf("  FAIL: %s: %v", label, err))
+				continue
+			}
+			logger(fmt.Sprintf("  CREATED: %s (ID %d)", label, id))
+		}
+	}
+}
+
+// findMigrated returns the destination ID of a named resource, looking in
+// the migrated IDs first and then on the destination.
+func findMigrated(dst *platform.Client, path string, byName map[string]int, name string) int {
+	if id := byName[name]; id != 0 {
+		return id
+	}
+	if existing, err := dst.FindByName(path, name); err == nil && existing != nil {
+		return resourceID(existing)
+	}
+	return 0
+}
+
+// countInventorySources returns the number of exported inventory sources.
+func countInventorySources(data *ExportedData) int {
+	var n int
+	for _, sources := range data.InventorySources {
+		n += len(sources)
+	}
+	return n
+}
+
+// stringOf returns v if it is a string, otherwise "".
+func stringOf(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,76 @@
+package migration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestInventorySources_GitBacked(t *testing.T) {
+	src := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/inventories/1/inventory_sources/" {
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":7,"name":"From Git","source":"scm",
+				"source_path":"inventories/prod.yml","overwrite":true,"update_on_launch":true,"credential":null,
+				"summary_fields":{"source_project":{"id":3,"name":"Inventory Repo"},"credential":{"id":5,"name":"Vault"}}}]}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer src.Close()
+
+	data := &ExportedData{
+		Inventories:      []models.Resource{{"id": float64(1), "name": "Cloud"}},
+		InventorySources: make(map[int][]models.Resource),
+	}
+	exportInventorySources(newTestClient(t, src), "/api/v2/", data, func(string) {})
+	if n := countInventorySources(data); n != 1 {
+		t.Fatalf("exported %d inventory sources, want 1", n)
+	}
+
+	var mu sync.Mutex
+	var created map[string]interface{}
+	dst := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v2/inventories/10/inventory_sources/":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":70}`))
+		case r.URL.Path == "/api/v2/credentials/" && r.URL.Query().Get("name") == "Vault":
+			// Credential already on the destination, not part of this run.
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":50,"name":"Vault"}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer dst.Close()
+
+	ids := newIDMap()
+	ids.invs["Cloud"] = 10
+	ids.projects["Inventory Repo"] = 30
+	var logs []string
+	importInventorySources(newTestClient(t, dst), "/api/v2/", data, nil, ids,
+		newProgressTracker(data, nil), func(s string) { logs = append(logs, s) })
+
+	if created == nil {
+		t.Fatalf("no inventory source created; log: %q", logs)
+	}
+	want := map[string]interface{}{
+		"name": "From Git", "source": "scm", "source_path": "inventories/prod.yml",
+		"overwrite": true, "update_on_launch": true,
+		"source_project": float64(30), "credential": float64(50),
+	}
+	for k, v := range want {
+		if created[k] != v {
+			t.Errorf("payload[%s] = %v, want %v", k, created[k], v)
+		}
+	}
+	if !containsLine(logs, "  CREATED: Cloud/From Git (ID 70)") {
+		t.Errorf("missing CREATED line in %q", logs)
+	}
+}
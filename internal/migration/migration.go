@@ -10,23 +10,93 @@ import (
 
 // ExportedData holds all resources fetched from the source, in memory.
 type ExportedData struct {
-	Organizations   []models.Resource
-	Teams           []models.Resource
-	Users           []models.Resource
-	CredentialTypes []models.Resource
-	Credentials     []models.Resource
-	Projects        []models.Resource
-	Inventories     []models.Resource
-	Hosts           map[int][]models.Resource // inventory source ID → hosts
-	Groups          map[int][]models.Resource // inventory source ID → groups
-	GroupHosts      map[int][]int             // group source ID → host source IDs
-	JobTemplates    []models.Resource
-	Surveys         map[int]models.Resource   // JT/WFJT source ID → survey spec
-	WorkflowJTs     []models.Resource
-	WorkflowNodes   map[int][]models.Resource // WFJT source ID → nodes
-	Schedules       []models.Resource
-	OrgUsers        map[int][]string // org source ID → usernames
-	TeamUsers       map[int][]string // team source ID → usernames
+	Organizations         []models.Resource           `json:"organizations"`
+	Teams                 []models.Resource           `json:"teams"`
+	Users                 []models.Resource           `json:"users"`
+	CredentialTypes       []models.Resource           `json:"credential_types"`
+	ManagedCredTypeKinds  map[string]string           `json:"managed_credential_type_kinds"` // managed credential type name → kind
+	Credentials           []models.Resource           `json:"credentials"`
+	CredInputSources      map[int][]models.Resource   `json:"credential_input_sources"` // credential source ID → input sources
+	ExecutionEnvironments []models.Resource           `json:"execution_environments"`
+	Applications          []models.Resource           `json:"applications"` // OAuth2 applications, without client secrets
+	Projects              []models.Resource           `json:"projects"`
+	Inventories           []models.Resource           `json:"inventories"`
+	Hosts                 map[int][]models.Resource   `json:"hosts"`             // inventory source ID → hosts
+	Groups                map[int][]models.Resource   `json:"groups"`            // inventory source ID → groups
+	GroupHosts            map[int][]int               `json:"group_hosts"`       // group source ID → host source IDs
+	InventorySources      map[int][]models.Resource   `json:"inventory_sources"` // inventory source ID → inventory sources
+	InputInventories      map[int][]string            `json:"input_inventories"` // constructed inventory source ID → input inventory names, in order
+	JobTemplates          []models.Resource           `json:"job_templates"`
+	Surveys               map[int]models.Resource     `json:"surveys"`         // JT/WFJT source ID → survey spec
+	Labels                map[int][]models.Resource   `json:"labels"`          // JT source ID → labels
+	WorkflowLabels        map[int][]models.Resource   `json:"workflow_labels"` // WFJT source ID → labels
+	NotificationTemplates []models.Resource           `json:"notification_templates"`
+	Notifications         map[int]map[string][]int    `json:"notifications"`          // JT source ID → event → notification template source IDs
+	WorkflowNotifications map[int]map[string][]int    `json:"workflow_notifications"` // WFJT source ID → event → notification template source IDs
+	InstanceGroups        map[string]map[int][]string `json:"instance_groups"`        // resource type → source ID → instance group names, in preference order
+	GalaxyCredentials     map[int][]string            `json:"galaxy_credentials"`     // org source ID → galaxy credential names, in priority order
+	WorkflowJTs           []models.Resource           `json:"workflow_job_templates"`
+	WorkflowNodes         map[int][]models.Resource   `json:"workflow_nodes"`   // WFJT source ID → nodes
+	NodeCredentials       map[int][]string            `json:"node_credentials"` // workflow node source ID → prompt credential names
+	Schedules             []models.Resource           `json:"schedules"`
+	OrgUsers              map[int][]string            `json:"org_users"`        // org source ID → usernames
+	TeamUsers             map[int][]string            `json:"team_users"`       // team source ID → usernames
+	RoleAssignments       []RoleAssignment            `json:"role_assignments"` // object roles granted to teams and users
+	Disabled              map[string]int              `json:"disabled"`         // resource type → count filtered out as disabled
+	Types                 []string                    `json:"types,omitempty"`  // selected resource types; empty means all
+}
+
+// DefaultExportConcurrency is the number of inventories exported in
+// parallel when ExportOptions.Concurrency is not set.
+const DefaultExportConcurrency = 4
+
+// ExportOptions controls what Preview fetches from the source and how it
+// treats resources that already exist on the destination.
+type ExportOptions struct {
+	// ExcludeDisabled skips hosts and schedules whose "enabled" flag is false.
+	ExcludeDisabled bool
+	// Concurrency bounds how many inventories have their hosts and groups
+	// fetched at once. Zero means DefaultExportConcurrency.
+	Concurrency int
+	// Conflicts maps a resource type to "skip" (default) or "update".
+	// Existing organizations, inventories and job templates set to "update"
+	// are PATCHed to match the source instead of being skipped.
+	Conflicts map[string]string
+	// Types limits the export to these resource types plus the types they
+	// depend on. Empty exports every type.
+	Types []string
+	// Transforms rewrites resource fields during import. Preview uses it to
+	// look up renamed resources on the destination.
+	Transforms *Transforms
+	// Exclude lists, per resource type, names left out of the migration in
+	// addition to DefaultExclusions. They are previewed as "skip_excluded"
+	// and not imported.
+	Exclude map[string][]string
+
+	// inventorySink, when set, receives each inventory's hosts and groups
+	// instead of them being kept in ExportedData. Used by ExportStreaming.
+	inventorySink func(invID int, c inventoryContents) error
+}
+
+// ImportOptions controls what Run, Resume, DryRun and RunFromArchive write
+// to the destination.
+type ImportOptions struct {
+	// Exclude lists, per resource type, names that are not imported, in
+	// addition to those the preview excluded.
+	Exclude map[string][]string
+	// IncludeGroups limits inventories (by name) to the listed groups and
+	// their hosts. Inventories not in it are migrated whole.
+	IncludeGroups map[string][]string
+	// Secrets fills credential inputs; inputs without a value stay empty.
+	Secrets CredentialSecrets
+	// Transforms rewrites resource fields just before they are sent.
+	Transforms *Transforms
+	// Users sets the password and privileges of created users.
+	Users *UserOptions
+
+	// varsFormat is the destination's extra_vars format, set from its
+	// connection type; empty means YAML.
+	varsFormat string
 }
 
 // apiPrefix returns the API path prefix for a connection.
@@ -43,9 +113,9 @@ func apiPrefix(conn *models.Connection) string {
 
 // Preview exports resources from source and checks the destination for conflicts.
 // Returns the preview (for the UI) and the exported data (for the import step).
-func Preview(src, dst *models.Connection, logger func(string)) (*models.MigrationPreview, *ExportedData, error) {
-	srcClient := platform.NewClient(src)
-	dstClient := platform.NewClient(dst)
+func Preview(ctx context.Context, src, dst *models.Connection, opts ExportOptions, logger func(string)) (*models.MigrationPreview, *ExportedData, error) {
+	srcClient := platform.NewClient(src).WithContext(ctx)
+	dstClient := platform.NewClient(dst).WithContext(ctx)
 
 	// Verify connectivity
 	logger("Checking source connectivity...")
@@ -65,7 +135,7 @@ func Preview(src, dst *models.Connection, logger func(string)) (*models.Migratio
 	// Export from source
 	logger("")
 	logger("=== Exporting from source ===")
-	data, err := exportAll(srcClient, srcPrefix, logger)
+	data, err := exportAll(ctx, srcClient, srcPrefix, opts, logger)
 	if err != nil {
 		return nil, nil, fmt.Errorf("export failed: %w", err)
 	}
@@ -73,7 +143,7 @@ func Preview(src, dst *models.Connection, logger func(string)) (*models.Migratio
 	// Preflight check on destination
 	logger("")
 	logger("=== Checking destination ===")
-	preview, err := preflightCheck(data, dstClient, dstPrefix, logger)
+	preview, err := preflightCheck(data, dstClient, dstPrefix, opts.Conflicts, opts.Exclude, opts.Transforms, logger)
 	if err != nil {
 		return nil, nil, fmt.Errorf("preflight failed: %w", err)
 	}
@@ -82,29 +152,38 @@ func Preview(src, dst *models.Connection, logger func(string)) (*models.Migratio
 	preview.DestinationID = dst.ID
 
 	// Summary
-	var createCount, skipCount int
+	var createCount, updateCount, skipCount int
 	for _, items := range preview.Resources {
 		for _, item := range items {
-			if item.Action == "create" {
+			switch item.Action {
+			case "create":
 				createCount++
-			} else {
+			case "update":
+				updateCount++
+			default:
 				skipCount++
 			}
 		}
 	}
 	logger("")
-	logger(fmt.Sprintf("Preview complete: %d to create, %d to skip", createCount, skipCount))
+	logger(fmt.Sprintf("Preview complete: %d to create, %d to update, %d to skip", createCount, updateCount, skipCount))
 
 	return preview, data, nil
 }
 
-// Run imports the previously exported data into the destination.
-func Run(ctx context.Context, dst *models.Connection, data *ExportedData, preview *models.MigrationPreview, exclude map[string][]string, logger func(string)) error {
-	dstClient := platform.NewClient(dst)
+// Run imports the previously exported data into the destination as opts
+// describes. What gets created is recorded in state (nil to not keep it) for Resume.
+// If report is non-nil it receives the completed and total step counts.
+func Run(ctx context.Context, dst *models.Connection, data *ExportedData, preview *models.MigrationPreview, opts ImportOptions, state *ResumeState, report func(completed, total int), logger func(string)) error {
+	dstClient := platform.NewClient(dst).WithContext(ctx)
 	dstPrefix := apiPrefix(dst)
 
 	logger("=== Starting migration to " + dst.Name + " ===")
 	logger("")
 
-	return importAll(ctx, dstClient, dstPrefix, dst.Type, data, preview, exclude, logger)
+	if state == nil {
+		state = NewResumeState()
+	}
+	opts.varsFormat = varsFormat(dst)
+	return importAll(ctx, dstClient, dstPrefix, data, preview, opts, state.ids, report, logger)
 }
@@ -0,0 +1,144 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestAPIPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		conn models.Connection
+		want string
+	}{
+		{"awx default", models.Connection{Type: "awx"}, "/api/v2/"},
+		{"aap default", models.Connection{Type: "aap"}, "/api/controller/v2/"},
+		{"aap discovered", models.Connection{Type: "aap", APIPrefix: "/api/gateway/controller/v2/"}, "/api/gateway/controller/v2/"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := apiPrefix(&tc.conn); got != tc.want {
+				t.Errorf("apiPrefix() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPreviewAndRun_UseDiscoveredPrefix(t *testing.T) {
+	const prefix = "/api/custom/v2/"
+	var mu sync.Mutex
+	var paths []string
+	var posts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, r.URL.Path)
+		switch {
+		case r.Method == "POST":
+			posts = append(posts, r.URL.Path)
+			w.Write([]byte(`{"id":5}`))
+		case r.URL.Path == prefix+"organizations/" && r.URL.Query().Get("name") == "":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Eng"}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	src := newTestConnection(t, ts)
+	src.Type, src.APIPrefix = "aap", prefix
+	dst := newTestConnection(t, ts)
+	dst.Type, dst.APIPrefix = "aap", prefix
+
+	preview, data, err := Preview(context.Background(), src, dst, ExportOptions{Types: []string{"organizations"}}, func(string) {})
+	if err != nil {
+		t.Fatalf("Preview returned error: %v", err)
+	}
+	if err := Run(context.Background(), dst, data, preview, ImportOptions{}, nil, nil, func(string) {}); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	for _, p := range paths {
+		if !strings.HasPrefix(p, prefix) {
+			t.Errorf("request to %s does not use the discovered prefix", p)
+		}
+	}
+	if len(posts) != 1 || posts[0] != prefix+"organizations/" {
+		t.Errorf("POSTs = %v, want one to %sorganizations/", posts, prefix)
+	}
+}
+
+func TestPreviewAndRun_AWXToAWX(t *testing.T) {
+	src := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/organizations/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Eng"}]}`))
+		case "/api/v2/projects/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":2,"name":"Playbooks","scm_type":"git",
+				"scm_url":"https://git.example.com/playbooks.git","summary_fields":{"organization":{"name":"Eng"}}}]}`))
+		case "/api/v2/inventories/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":3,"name":"Prod","summary_fields":{"organization":{"name":"Eng"}}}]}`))
+		case "/api/v2/job_templates/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":4,"name":"Deploy","playbook":"site.yml",
+				"summary_fields":{"project":{"name":"Playbooks"},"inventory":{"name":"Prod"}}}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer src.Close()
+
+	var mu sync.Mutex
+	posted := make(map[string]map[string]interface{}) // path → body
+	ids := map[string]int{"/api/v2/organizations/": 10, "/api/v2/projects/": 20, "/api/v2/inventories/": 30, "/api/v2/job_templates/": 40}
+	dst := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.HasPrefix(r.URL.Path, "/api/v2/") {
+			t.Errorf("destination request to %s outside /api/v2/", r.URL.Path)
+		}
+		switch {
+		case r.Method == "POST":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			posted[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": ids[r.URL.Path]})
+		case r.URL.Path == "/api/v2/projects/20/":
+			w.Write([]byte(`{"id":20,"status":"successful"}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer dst.Close()
+
+	srcConn, dstConn := newTestConnection(t, src), newTestConnection(t, dst)
+	preview, data, err := Preview(context.Background(), srcConn, dstConn, ExportOptions{}, func(string) {})
+	if err != nil {
+		t.Fatalf("Preview returned error: %v", err)
+	}
+	var logs []string
+	if err := Run(context.Background(), dstConn, data, preview, ImportOptions{}, nil, nil, func(s string) { logs = append(logs, s) }); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if got := posted["/api/v2/organizations/"]["name"]; got != "Eng" {
+		t.Errorf("organization POST name = %v, want Eng", got)
+	}
+	jt := posted["/api/v2/job_templates/"]
+	if jt == nil {
+		t.Fatalf("job template not created; log:\n%s", strings.Join(logs, "\n"))
+	}
+	if jt["project"] != float64(20) || jt["inventory"] != float64(30) {
+		t.Errorf("job template project/inventory = %v/%v, want 20/30", jt["project"], jt["inventory"])
+	}
+	if !containsLine(logs, "  Project Playbooks sync complete") {
+		t.Errorf("AWX destination did not wait for the project sync:\n%s", strings.Join(logs, "\n"))
+	}
+}
@@ -0,0 +1,89 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// notificationEvents are the job events a notification template can be
+// attached to, matching the notification_templates_<event> sub-endpoints.
+var notificationEvents = []string{"started", "success", "error"}
+
+// notificationSecretFields lists the secret-bearing notification_configuration
+// keys per notification type. The API returns these as "$encrypted$".
+var notificationSecretFields = map[string][]string{
+	"email":      {"password"},
+	"grafana":    {"grafana_key"},
+	"irc":        {"password"},
+	"mattermost": {"mattermost_url"},
+	"pagerduty":  {"token"},
+	"rocketchat": {"rocketchat_url"},
+	"slack":      {"token"},
+	"twilio":     {"account_token"},
+	"webhook":    {"password", "headers"},
+}
+
+// redactNotificationConfig blanks the secret fields of a notification
+// template's configuration, plus anything the API returned encrypted.
+// It returns the redacted keys.
+func redactNotificationConfig(nt models.Resource) []string {
+	cfg, ok := nt["notification_configuration"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	secret := make(map[string]bool)
+	for _, f := range notificationSecretFields[stringField(nt, "notification_type")] {
+		secret[f] = true
+	}
+	var redacted []string
+	for k, v := range cfg {
+		if s, _ := v.(string); secret[k] || s == "$encrypted$" {
+			if k == "headers" {
+				cfg[k] = map[string]interface{}{}
+			} else {
+				cfg[k] = ""
+			}
+			redacted = append(redacted, k)
+		}
+	}
+	return redacted
+}
+
+// fetchNotificationAttachments returns the source notification template IDs
+// attached to a JT or WFJT, keyed by event.
+func fetchNotificationAttachments(client *platform.Client, parentPath string) map[string][]int {
+	attached := make(map[string][]int)
+	for _, event := range notificationEvents {
+		nts, err := client.GetAll(fmt.Sprintf("%snotification_templates_%s/", parentPath, event))
+		if err != nil {
+			continue
+		}
+		for _, nt := range nts {
+			attached[event] = append(attached[event], resourceID(nt))
+		}
+	}
+	if len(attached) == 0 {
+		return nil
+	}
+	return attached
+}
+
+// attachNotifications associates migrated notification templates with the
+// destination JT or WFJT at parentPath.
+func attachNotifications(assoc *associator, parentPath string, attached map[string][]int, ids *idMap, logger func(string)) {
+	for _, event := range notificationEvents {
+		for _, srcID := range attached[event] {
+			destID := ids.notifications[srcID]
+			if destID == 0 {
+				continue
+			}
+			if _, err := assoc.associate(fmt.Sprintf("%snotification_templates_%s/", parentPath, event), destID); err != nil {
+				logger(fmt.Sprintf("    FAIL notification on %s: %v", event, err))
+				continue
+			}
+			logger(fmt.Sprintf("    NOTIFY on %s: notification template %d", event, destID))
+		}
+	}
+}
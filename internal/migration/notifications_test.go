@@ -0,0 +1,122 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func emailTemplate() models.Resource {
+	return models.Resource{
+		"id":                float64(8),
+		"name":              "Ops mail",
+		"notification_type": "email",
+		"notification_configuration": map[string]interface{}{
+			"host":       "smtp.example.com",
+			"port":       float64(587),
+			"username":   "notifier",
+			"password":   "$encrypted$",
+			"recipients": []interface{}{"ops@example.com"},
+		},
+		"summary_fields": map[string]interface{}{"organization": map[string]interface{}{"name": "Eng"}},
+	}
+}
+
+func TestRedactNotificationConfig(t *testing.T) {
+	nt := emailTemplate()
+	nt["notification_configuration"].(map[string]interface{})["sender"] = "$encrypted$"
+
+	redacted := redactNotificationConfig(nt)
+	sort.Strings(redacted)
+	if len(redacted) != 2 || redacted[0] != "password" || redacted[1] != "sender" {
+		t.Errorf("redacted = %v, want [password sender]", redacted)
+	}
+	cfg := nt["notification_configuration"].(map[string]interface{})
+	if cfg["password"] != "" || cfg["host"] != "smtp.example.com" {
+		t.Errorf("configuration after redaction = %v", cfg)
+	}
+}
+
+// notificationServer fakes an empty destination that records created
+// notification templates and success-event attachments on job template 5.
+type notificationServer struct {
+	mu       sync.Mutex
+	created  []map[string]interface{}
+	attached []int
+}
+
+func (n *notificationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if r.Method == "GET" {
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		return
+	}
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	switch r.URL.Path {
+	case "/api/v2/notification_templates/":
+		n.created = append(n.created, body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":70}`))
+	case "/api/v2/job_templates/":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":5}`))
+	case "/api/v2/job_templates/5/notification_templates_success/":
+		n.attached = append(n.attached, toInt(body["id"]))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}
+}
+
+func TestImportAll_NotificationOnSuccess(t *testing.T) {
+	srv := &notificationServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	nt := emailTemplate()
+	redactNotificationConfig(nt)
+	data := &ExportedData{
+		Organizations:         []models.Resource{{"id": float64(1), "name": "Eng"}},
+		NotificationTemplates: []models.Resource{nt},
+		JobTemplates:          []models.Resource{{"id": float64(12), "name": "Deploy"}},
+		Notifications:         map[int]map[string][]int{12: {"success": {8}}},
+	}
+	preview := &models.MigrationPreview{
+		Resources: map[string][]models.MigrationResource{
+			"organizations": {{Name: "Eng", Action: "skip_exists", DestID: 1}},
+		},
+	}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	if len(srv.created) != 1 {
+		t.Fatalf("created %d notification templates, want 1", len(srv.created))
+	}
+	created := srv.created[0]
+	if created["notification_type"] != "email" || toInt(created["organization"]) != 1 {
+		t.Errorf("created notification template = %v", created)
+	}
+	if cfg, _ := created["notification_configuration"].(map[string]interface{}); cfg["password"] != "" {
+		t.Errorf("password = %v, want blank", cfg["password"])
+	}
+	if len(srv.attached) != 1 || srv.attached[0] != 70 {
+		t.Errorf("success attachments = %v, want [70]", srv.attached)
+	}
+	if !containsLine(logs, "    NOTIFY on success: notification template 70") {
+		t.Errorf("missing NOTIFY log line in %q", logs)
+	}
+}
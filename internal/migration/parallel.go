@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"context"
+	"sync"
+)
+
+// forEachLimit calls fn(i) for every i in [0, n) with at most workers calls
+// running at once; workers <= 1 runs them in order on the calling goroutine.
+// Once ctx is done no further calls are started. It returns when all
+// started calls have returned.
+func forEachLimit(ctx context.Context, n, workers int, fn func(i int)) {
+	if workers <= 1 {
+		for i := 0; i < n && ctx.Err() == nil; i++ {
+			fn(i)
+		}
+		return
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
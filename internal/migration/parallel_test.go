@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+func TestImportAll_ConcurrentHosts(t *testing.T) {
+	const n = 200
+	srv := newCollectionServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	conn := newTestConnection(t, ts)
+	conn.ImportConcurrency = 8
+
+	data := newExportedData()
+	data.Inventories = []models.Resource{{"id": float64(1), "name": "Fleet"}}
+	group := models.Resource{"id": float64(5000), "name": "all-web"}
+	data.Groups[1] = []models.Resource{group}
+	for i := 0; i < n; i++ {
+		hostID := 1000 + i
+		data.Hosts[1] = append(data.Hosts[1], models.Resource{"id": float64(hostID), "name": fmt.Sprintf("host-%03d", i)})
+		data.GroupHosts[5000] = append(data.GroupHosts[5000], hostID)
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{
+		"inventories": {{Name: "Fleet", Type: "inventories", Action: "create"}},
+	}}
+
+	ids := newIDMap()
+	var logs []string
+	err := importAll(context.Background(), platform.NewClient(conn), "/api/v2/", data, preview, ImportOptions{}, ids, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("host-%03d", i)
+		if got := srv.posts[name]; got != 1 {
+			t.Errorf("%s POSTed %d times, want 1", name, got)
+		}
+		if ids.hosts["Fleet/"+name] == 0 {
+			t.Errorf("%s missing from the ID map", name)
+		}
+	}
+	groupHosts := fmt.Sprintf("/api/v2/groups/%d/hosts/", ids.groups["Fleet/all-web"])
+	if got := len(srv.objects[groupHosts]); got != n {
+		t.Errorf("associated %d hosts with the group, want %d", got, n)
+	}
+	if !containsLine(logs, fmt.Sprintf("  Fleet: %d hosts", n)) {
+		t.Errorf("missing host count for Fleet in %q", logs)
+	}
+}
+
+func TestForEachLimit_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	forEachLimit(ctx, 10, 1, func(i int) {
+		calls++
+		if i == 2 {
+			cancel()
+		}
+	})
+	if calls != 3 {
+		t.Errorf("fn called %d times after cancelling at the third, want 3", calls)
+	}
+}
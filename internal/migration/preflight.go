@@ -2,6 +2,8 @@ package migration
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
 	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
@@ -10,17 +12,49 @@ import (
 // Resource types in the order they appear in the preview.
 var previewOrder = []string{
 	"organizations", "teams", "users", "credential_types", "credentials",
-	"projects", "inventories", "hosts", "groups",
+	"execution_environments", "applications", "projects", "inventories", "hosts", "groups", "notification_templates",
 	"job_templates", "workflow_job_templates", "schedules",
 }
 
+// updatableTypes lists the resource types that can be PATCHed in place when
+// they already exist on the destination.
+var updatableTypes = map[string]bool{
+	"organizations": true,
+	"inventories":   true,
+	"job_templates": true,
+}
+
+// ValidateConflicts returns an error unless every entry of conflicts maps a
+// migratable resource type to "skip" or, for updatable types, "update".
+func ValidateConflicts(conflicts map[string]string) error {
+	for rt, strategy := range conflicts {
+		if err := ValidateTypes([]string{rt}); err != nil {
+			return fmt.Errorf("conflicts: %w", err)
+		}
+		switch strategy {
+		case "skip":
+		case "update":
+			if !updatableTypes[rt] {
+				return fmt.Errorf("conflicts: %s cannot be updated, only skipped", rt)
+			}
+		default:
+			return fmt.Errorf("conflicts: unknown strategy %q for %s, want skip or update", strategy, rt)
+		}
+	}
+	return nil
+}
+
 // preflightCheck examines the destination for each exported resource and classifies
-// the action as "create" or "skip_exists".
-func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, logger func(string)) (*models.MigrationPreview, error) {
+// the action as "create", "skip_exists", or "update" when conflicts asks for
+// existing resources of an updatable type to be updated. Resources named in
+// exclude are "skip_excluded" without being looked up. Others are looked up
+// under the name tf will give them.
+func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, conflicts map[string]string, exclude map[string][]string, tf *Transforms, logger func(string)) (*models.MigrationPreview, error) {
 	preview := &models.MigrationPreview{
 		Resources:   make(map[string][]models.MigrationResource),
 		HostCounts:  make(map[string]int),
 		GroupCounts: make(map[string]int),
+		Totals:      make(map[string]int),
 	}
 
 	// Build inventory name lookup
@@ -29,7 +63,11 @@ func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, log
 		invNames[resourceID(inv)] = resourceName(inv)
 	}
 
+	want := selectTypes(data.Types)
 	for _, rt := range previewOrder {
+		if !want.has(rt) {
+			continue
+		}
 		items := dataForType(data, rt)
 		if len(items) == 0 {
 			continue
@@ -47,6 +85,9 @@ func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, log
 					Type:     rt,
 					Action:   "create",
 				}
+				if isExcluded(exclude, rt, name) {
+					mr.Action = "skip_excluded"
+				}
 				preview.Resources[rt] = append(preview.Resources[rt], mr)
 			}
 			continue
@@ -62,23 +103,35 @@ func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, log
 				Name:     name,
 				Type:     rt,
 			}
+			if isExcluded(exclude, rt, name) {
+				mr.Action = "skip_excluded"
+				logger(fmt.Sprintf("  %s: excluded", name))
+				preview.Resources[rt] = append(preview.Resources[rt], mr)
+				continue
+			}
 
 			var existing models.Resource
 			var err error
 
+			destName := tf.name(rt, name)
 			switch rt {
 			case "users":
-				existing, err = dst.FindByUsername(prefix+rt+"/", name)
+				existing, err = dst.FindByUsername(prefix+rt+"/", destName)
 			case "credential_types":
-				existing, err = dst.FindByName(prefix+"credential_types/", name)
+				existing, err = dst.FindByName(prefix+"credential_types/", destName)
 			default:
-				existing, err = dst.FindByName(prefix+rt+"/", name)
+				existing, err = dst.FindByName(prefix+rt+"/", destName)
 			}
 
 			if err == nil && existing != nil {
-				mr.Action = "skip_exists"
 				mr.DestID = resourceID(existing)
-				logger(fmt.Sprintf("  %s: exists (dest ID %d)", name, mr.DestID))
+				if conflicts[rt] == "update" && updatableTypes[rt] {
+					mr.Action = "update"
+					logger(fmt.Sprintf("  %s: exists (dest ID %d), will update", name, mr.DestID))
+				} else {
+					mr.Action = "skip_exists"
+					logger(fmt.Sprintf("  %s: exists (dest ID %d)", name, mr.DestID))
+				}
 			} else {
 				mr.Action = "create"
 			}
@@ -87,6 +140,25 @@ func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, log
 		}
 	}
 
+	// Counts after export filtering (defaults, disabled, unselected types).
+	// Resources excluded by name are counted under Excluded instead. Every
+	// selected type is reported, including hosts and groups, even when none
+	// were exported.
+	for _, rt := range previewOrder {
+		if !want.has(rt) {
+			continue
+		}
+		n := 0
+		for _, mr := range preview.Resources[rt] {
+			if mr.Action == "skip_excluded" {
+				addExcluded(preview, rt, 1)
+			} else {
+				n++
+			}
+		}
+		preview.Totals[rt] = n
+	}
+
 	// Compute host/group counts per inventory
 	for srcInvID, hosts := range data.Hosts {
 		invName := invNames[srcInvID]
@@ -101,10 +173,50 @@ func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, log
 		}
 	}
 
+	// Resources filtered out during export (e.g. disabled hosts/schedules)
+	for rt, n := range data.Disabled {
+		addExcluded(preview, rt, n)
+	}
+
 	// Warnings
+	if dups := duplicateNames(data); len(dups) > 0 {
+		for _, d := range dups {
+			logger("  WARNING: duplicate name: " + d)
+		}
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("%d names are used by more than one exported object of the same type in different organizations: %s. Objects are matched by name during import, so only one object of each name is migrated and references to the others resolve to it — rename them on the source first.", len(dups), strings.Join(dups, "; ")))
+	}
 	if len(data.Credentials) > 0 {
 		preview.Warnings = append(preview.Warnings,
-			"Credential secrets cannot be exported via API. Credentials will be created with empty inputs — you must set secrets manually after migration.")
+			"Credential secrets cannot be exported via API. Credentials not listed in credential_secrets will be created with empty inputs — you must set their secrets manually after migration.")
+	}
+	if n := countConfidentialApplications(data); n > 0 {
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("%d OAuth2 applications have client secrets, which cannot be exported. The destination issues new secrets — you must regenerate them and update every client using these applications after migration.", n))
+	}
+	if len(data.NotificationTemplates) > 0 {
+		preview.Warnings = append(preview.Warnings,
+			"Notification template secrets (passwords, tokens, webhook headers) cannot be exported. They will be created with those fields empty — you must re-enter them after migration.")
+	}
+	if n := countGalaxyCredentials(data); n > 0 {
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("%d organization galaxy credential associations will be re-attached by credential name after credentials are created. Credentials missing on the destination are skipped.", n))
+	}
+	if n := countCredentialInputSources(data); n > 0 {
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("%d credential input sources will be linked to their secret backend credentials by name. Input sources whose backend credential is missing on the destination are skipped.", n))
+	}
+	if n := countInventorySources(data); n > 0 {
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("%d inventory sources will be recreated without syncing. Their credentials and source projects are resolved by name; run an update on each after migration.", n))
+	}
+	if n := len(data.RoleAssignments); n > 0 {
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("%d role assignments will be granted to teams and users after all resources are created. Assignments on resources that are not migrated are skipped.", n))
+	}
+	if missing := missingInstanceGroups(data, dst, prefix); len(missing) > 0 {
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("Instance groups not found on destination (assignments will be skipped): %s", strings.Join(missing, ", ")))
 	}
 	if len(data.Users) > 0 {
 		preview.Warnings = append(preview.Warnings,
@@ -118,6 +230,53 @@ func preflightCheck(data *ExportedData, dst *platform.Client, prefix string, log
 	return preview, nil
 }
 
+// addExcluded adds n resources of type rt to the preview's excluded count.
+func addExcluded(preview *models.MigrationPreview, rt string, n int) {
+	if n == 0 {
+		return
+	}
+	if preview.Excluded == nil {
+		preview.Excluded = make(map[string]int)
+	}
+	preview.Excluded[rt] += n
+}
+
+// orgScopedTypes lists the resource types whose names are only unique
+// within an organization.
+var orgScopedTypes = []string{
+	"teams", "credentials", "execution_environments", "applications", "projects",
+	"inventories", "notification_templates", "job_templates", "workflow_job_templates",
+}
+
+// duplicateNames reports the names shared by several exported objects of an
+// org-scoped type, e.g. `job_templates "Deploy" (Eng, Ops)`. The import maps
+// objects by name alone, so such objects would collide.
+func duplicateNames(data *ExportedData) []string {
+	var dups []string
+	for _, rt := range orgScopedTypes {
+		orgs := make(map[string][]string) // name → organizations using it
+		var names []string
+		for _, r := range dataForType(data, rt) {
+			name := resourceName(r)
+			if _, seen := orgs[name]; !seen {
+				names = append(names, name)
+			}
+			org := extractOrgName(r)
+			if org == "" {
+				org = "no organization"
+			}
+			orgs[name] = append(orgs[name], org)
+		}
+		for _, name := range names {
+			if len(orgs[name]) > 1 {
+				sort.Strings(orgs[name])
+				dups = append(dups, fmt.Sprintf("%s %q (%s)", rt, name, strings.Join(orgs[name], ", ")))
+			}
+		}
+	}
+	return dups
+}
+
 // dataForType returns the exported resources for a given type name.
 func dataForType(data *ExportedData, typeName string) []models.Resource {
 	switch typeName {
@@ -131,22 +290,28 @@ func dataForType(data *ExportedData, typeName string) []models.Resource {
 		return data.CredentialTypes
 	case "credentials":
 		return data.Credentials
+	case "execution_environments":
+		return data.ExecutionEnvironments
+	case "applications":
+		return data.Applications
 	case "projects":
 		return data.Projects
 	case "inventories":
 		return data.Inventories
 	case "hosts":
 		var all []models.Resource
-		for _, hosts := range data.Hosts {
-			all = append(all, hosts...)
+		for _, invID := range sortedInventoryIDs(data.Hosts) {
+			all = append(all, data.Hosts[invID]...)
 		}
 		return all
 	case "groups":
 		var all []models.Resource
-		for _, groups := range data.Groups {
-			all = append(all, groups...)
+		for _, invID := range sortedInventoryIDs(data.Groups) {
+			all = append(all, data.Groups[invID]...)
 		}
 		return all
+	case "notification_templates":
+		return data.NotificationTemplates
 	case "job_templates":
 		return data.JobTemplates
 	case "workflow_job_templates":
@@ -156,3 +321,33 @@ func dataForType(data *ExportedData, typeName string) []models.Resource {
 	}
 	return nil
 }
+
+// missingInstanceGroups returns the sorted names of instance groups used on
+// the source that do not exist on the destination.
+func missingInstanceGroups(data *ExportedData, dst *platform.Client, prefix string) []string {
+	if len(data.InstanceGroups) == 0 {
+		return nil
+	}
+	all, err := dst.GetAll(prefix + "instance_groups/")
+	if err != nil {
+		return nil
+	}
+	existing := make(map[string]bool)
+	for _, ig := range all {
+		existing[resourceName(ig)] = true
+	}
+	seen := make(map[string]bool)
+	var missing []string
+	for _, byID := range data.InstanceGroups {
+		for _, names := range byID {
+			for _, name := range names {
+				if !existing[name] && !seen[name] {
+					seen[name] = true
+					missing = append(missing, name)
+				}
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,51 @@
+package migration
+
+// importPhases is the number of "=== Importing ... ===" phases in importAll.
+const importPhases = 23
+
+// progressTracker counts import steps (one per phase plus one per resource)
+// and passes them to a report callback, e.g. Job.SetProgress.
+type progressTracker struct {
+	report    func(completed, total int)
+	completed int
+	total     int
+}
+
+func newProgressTracker(data *ExportedData, report func(completed, total int)) *progressTracker {
+	total := importPhases +
+		len(data.Organizations) + len(data.CredentialTypes) + len(data.Users) +
+		len(data.Teams) + len(data.Credentials) + len(data.ExecutionEnvironments) + len(data.Applications) + len(data.Projects) +
+		len(data.Inventories) + len(data.NotificationTemplates) +
+		len(data.JobTemplates) + len(data.Schedules) + len(data.WorkflowJTs) +
+		countInventorySources(data)
+	for _, hosts := range data.Hosts {
+		total += len(hosts)
+	}
+	for _, groups := range data.Groups {
+		total += len(groups)
+	}
+	p := &progressTracker{report: report, total: total}
+	p.emit()
+	return p
+}
+
+// step records one finished unit of work.
+func (p *progressTracker) step() {
+	if p.completed < p.total {
+		p.completed++
+	}
+	p.emit()
+}
+
+// done marks all work as finished, covering resources that were skipped
+// without being stepped (e.g. hosts of an excluded inventory).
+func (p *progressTracker) done() {
+	p.completed = p.total
+	p.emit()
+}
+
+func (p *progressTracker) emit() {
+	if p.report != nil {
+		p.report(p.completed, p.total)
+	}
+}
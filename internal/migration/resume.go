@@ -0,0 +1,54 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// ResumeState records the source → destination mappings a migration run has
+// built, so that a cancelled or failed run can be resumed with Resume
+// without creating the same resources twice. It is not persisted: a run
+// interrupted by a restart can only be started again, and relies on the
+// preflight check to find what it already created.
+type ResumeState struct {
+	ids *idMap
+}
+
+// NewResumeState returns an empty state for a new migration run.
+func NewResumeState() *ResumeState {
+	return &ResumeState{ids: newIDMap()}
+}
+
+// Resume continues an interrupted run into dst. The destination is checked
+// again first, so resources created just before the interruption are found
+// by name, and everything already recorded in state is skipped.
+func Resume(ctx context.Context, dst *models.Connection, data *ExportedData, conflicts map[string]string, opts ImportOptions, state *ResumeState, report func(completed, total int), logger func(string)) error {
+	dstClient := platform.NewClient(dst).WithContext(ctx)
+	dstPrefix := apiPrefix(dst)
+
+	logger("=== Checking destination ===")
+	preview, err := preflightCheck(data, dstClient, dstPrefix, conflicts, opts.Exclude, opts.Transforms, logger)
+	if err != nil {
+		return fmt.Errorf("preflight failed: %w", err)
+	}
+
+	logger("")
+	logger("=== Resuming migration to " + dst.Name + " ===")
+	logger("")
+	state.ids.resumed = true
+	opts.varsFormat = varsFormat(dst)
+	return importAll(ctx, dstClient, dstPrefix, data, preview, opts, state.ids, report, logger)
+}
+
+// migrated reports whether a resumed run already handled the named resource,
+// logging the skip. It is always false for a fresh run.
+func (m *idMap) migrated(byName map[string]int, name string, logger func(string)) bool {
+	if !m.resumed || byName[name] == 0 {
+		return false
+	}
+	logger(fmt.Sprintf("  SKIP (already migrated): %s", name))
+	return true
+}
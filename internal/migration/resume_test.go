@@ -0,0 +1,173 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// collectionServer fakes a destination that keeps every POSTed object in
+// memory, so name lookups find what earlier requests created.
+type collectionServer struct {
+	mu      sync.Mutex
+	nextID  int
+	objects map[string][]map[string]interface{} // list path -> objects
+	posts   map[string]int                      // name -> number of POSTs
+}
+
+func newCollectionServer() *collectionServer {
+	return &collectionServer{
+		nextID:  100,
+		objects: make(map[string][]map[string]interface{}),
+		posts:   make(map[string]int),
+	}
+}
+
+func (s *collectionServer) add(path, name string) int {
+	s.nextID++
+	s.objects[path] = append(s.objects[path], map[string]interface{}{"id": s.nextID, "name": name})
+	return s.nextID
+}
+
+func (s *collectionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case "GET":
+		name := r.URL.Query().Get("name")
+		results := []map[string]interface{}{}
+		for _, obj := range s.objects[r.URL.Path] {
+			if name == "" || obj["name"] == name {
+				results = append(results, obj)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count": len(results), "next": nil, "results": results,
+		})
+	case "POST":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		name, _ := body["name"].(string)
+		s.posts[name]++
+		id := s.add(r.URL.Path, name)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "name": name})
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestResume_SkipsMigratedResources(t *testing.T) {
+	srv := newCollectionServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	dst := newTestConnection(t, ts)
+
+	data := &ExportedData{
+		Organizations: []models.Resource{{"id": float64(1), "name": "Eng"}},
+		Teams: []models.Resource{{"id": float64(2), "name": "Ops",
+			"summary_fields": map[string]interface{}{"organization": map[string]interface{}{"name": "Eng"}}}},
+		Projects: []models.Resource{
+			{"id": float64(3), "name": "Playbooks"},
+			{"id": float64(4), "name": "Roles"},
+		},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+	for _, r := range []struct{ rt, name string }{
+		{"organizations", "Eng"}, {"teams", "Ops"}, {"projects", "Playbooks"}, {"projects", "Roles"},
+	} {
+		preview.Resources[r.rt] = append(preview.Resources[r.rt], models.MigrationResource{Name: r.name, Type: r.rt, Action: "create"})
+	}
+
+	// First run: cancelled once teams are done.
+	state := NewResumeState()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := importAll(ctx, newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, state.ids, nil,
+		func(line string) {
+			if line == "=== Importing credentials ===" {
+				cancel()
+			}
+		})
+	if err != context.Canceled {
+		t.Fatalf("first run error = %v, want context.Canceled", err)
+	}
+
+	// A project created after the first run lost track of it.
+	srv.mu.Lock()
+	srv.add("/api/v2/projects/", "Playbooks")
+	srv.mu.Unlock()
+
+	var logs []string
+	err = Resume(context.Background(), dst, data, nil, ImportOptions{}, state, nil,
+		func(line string) { logs = append(logs, line) })
+	if err != nil {
+		t.Fatalf("Resume returned error: %v", err)
+	}
+
+	want := map[string]int{"Eng": 1, "Ops": 1, "Playbooks": 0, "Roles": 1}
+	for name, n := range want {
+		if got := srv.posts[name]; got != n {
+			t.Errorf("%s POSTed %d times, want %d", name, got, n)
+		}
+	}
+	if !containsLine(logs, "  SKIP (already migrated): Eng") {
+		t.Errorf("missing already-migrated skip for Eng in %q", strings.Join(logs, "\n"))
+	}
+	if !containsLine(logs, "  SKIP (exists): Playbooks") {
+		t.Errorf("missing exists skip for Playbooks in %q", strings.Join(logs, "\n"))
+	}
+}
+
+func TestResume_SkipsMigratedSchedules(t *testing.T) {
+	srv := newCollectionServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	dst := newTestConnection(t, ts)
+
+	parent := map[string]interface{}{"unified_job_template": map[string]interface{}{"name": "Deploy"}}
+	data := &ExportedData{
+		Schedules: []models.Resource{
+			{"id": float64(1), "name": "Nightly", "rrule": "DTSTART:20240101T000000Z RRULE:FREQ=DAILY", "summary_fields": parent},
+			{"id": float64(2), "name": "Weekly", "rrule": "DTSTART:20240101T000000Z RRULE:FREQ=WEEKLY", "summary_fields": parent},
+		},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+
+	// First run: the job template was migrated, and the run is cancelled
+	// once the schedules are done.
+	state := NewResumeState()
+	state.ids.jts["Deploy"] = 40
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := importAll(ctx, newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, state.ids, nil,
+		func(line string) {
+			if line == "=== Importing workflow job templates ===" {
+				cancel()
+			}
+		})
+	if err != context.Canceled {
+		t.Fatalf("first run error = %v, want context.Canceled", err)
+	}
+
+	var logs []string
+	err = Resume(context.Background(), dst, data, nil, ImportOptions{}, state, nil,
+		func(line string) { logs = append(logs, line) })
+	if err != nil {
+		t.Fatalf("Resume returned error: %v", err)
+	}
+	for _, name := range []string{"Nightly", "Weekly"} {
+		if got := srv.posts[name]; got != 1 {
+			t.Errorf("%s POSTed %d times, want 1", name, got)
+		}
+	}
+	if !containsLine(logs, "  SKIP (already migrated): Deploy/Nightly") {
+		t.Errorf("missing already-migrated skip for Nightly in %q", strings.Join(logs, "\n"))
+	}
+}
@@ -0,0 +1,130 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// RoleAssignment is an object role granted to a team or a user, e.g. team
+// "Ops" holding "Execute" on job template "Deploy".
+type RoleAssignment struct {
+	ResourceType string `json:"resource_type"` // e.g. "job_templates"
+	ResourceName string `json:"resource_name"`
+	Role         string `json:"role"` // role display name, e.g. "Execute"
+	Team         string `json:"team,omitempty"`
+	User         string `json:"user,omitempty"`
+}
+
+// roleResourceTypes maps the resource_type reported by the roles API to the
+// resource types whose role assignments are migrated.
+var roleResourceTypes = map[string]string{
+	"organization": "organizations",
+	"job_template": "job_templates",
+	"inventory":    "inventories",
+	"credential":   "credentials",
+	"project":      "projects",
+}
+
+// exportRoleAssignments records the object roles granted to each exported
+// team and user on organizations, job templates, inventories, credentials
+// and projects.
+func exportRoleAssignments(client *platform.Client, prefix string, data *ExportedData, logger func(string)) {
+	logger("Exporting role assignments...")
+	for _, team := range data.Teams {
+		roles, err := client.GetAll(fmt.Sprintf("%steams/%d/roles/", prefix, resourceID(team)))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get roles for team %s: %v", resourceName(team), err))
+			continue
+		}
+		for _, role := range roles {
+			if ra, ok := roleAssignment(role); ok {
+				ra.Team = resourceName(team)
+				data.RoleAssignments = append(data.RoleAssignments, ra)
+			}
+		}
+	}
+	for _, user := range data.Users {
+		username := stringField(user, "username")
+		roles, err := client.GetAll(fmt.Sprintf("%susers/%d/roles/", prefix, resourceID(user)))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get roles for user %s: %v", username, err))
+			continue
+		}
+		for _, role := range roles {
+			if ra, ok := roleAssignment(role); ok {
+				ra.User = username
+				data.RoleAssignments = append(data.RoleAssignments, ra)
+			}
+		}
+	}
+	logger(fmt.Sprintf("  %d role assignments", len(data.RoleAssignments)))
+}
+
+// roleAssignment describes a role from a team or user roles list, reporting
+// false for roles on resource types that are not migrated.
+func roleAssignment(role models.Resource) (RoleAssignment, bool) {
+	rt, ok := roleResourceTypes[stringField(summaryMap(role), "resource_type")]
+	if !ok {
+		return RoleAssignment{}, false
+	}
+	return RoleAssignment{
+		ResourceType: rt,
+		ResourceName: stringField(summaryMap(role), "resource_name"),
+		Role:         resourceName(role),
+	}, true
+}
+
+// summaryMap returns a resource's summary_fields, or nil.
+func summaryMap(r models.Resource) map[string]interface{} {
+	sf, _ := r["summary_fields"].(map[string]interface{})
+	return sf
+}
+
+// importRoleAssignments grants migrated teams and users their roles on the
+// migrated objects. Role names are resolved against the object_roles of the
+// destination object, then the team or user is POSTed to roles/{id}/.
+func importRoleAssignments(dst *platform.Client, prefix string, data *ExportedData, ids *idMap, assoc *associator, logger func(string)) {
+	objectRoles := make(map[string]map[string]int) // object path → role name → role ID
+	for _, ra := range data.RoleAssignments {
+		grantee, path, granteeID := ra.Team, "teams", ids.teams[ra.Team]
+		if ra.User != "" {
+			grantee, path, granteeID = ra.User, "users", ids.users[ra.User]
+		}
+		label := fmt.Sprintf("%s → %s %s (%s)", grantee, ra.ResourceType, ra.ResourceName, ra.Role)
+		destID := ids.destIDFor(ra.ResourceType, ra.ResourceName)
+		if granteeID == 0 || destID == 0 {
+			logger(fmt.Sprintf("  SKIP: %s (not migrated)", label))
+			continue
+		}
+
+		objPath := fmt.Sprintf("%s%s/%d/", prefix, ra.ResourceType, destID)
+		roles, ok := objectRoles[objPath]
+		if !ok {
+			var obj models.Resource
+			if err := dst.GetJSON(objPath, nil, &obj); err != nil {
+				logger(fmt.Sprintf("  FAIL: %s: %v", label, err))
+				continue
+			}
+			roles = make(map[string]int)
+			byField, _ := summaryMap(obj)["object_roles"].(map[string]interface{})
+			for _, v := range byField {
+				if role, ok := v.(map[string]interface{}); ok {
+					roles[stringField(role, "name")] = intField(role, "id")
+				}
+			}
+			objectRoles[objPath] = roles
+		}
+		roleID := roles[ra.Role]
+		if roleID == 0 {
+			logger(fmt.Sprintf("  WARNING: role %q not found on %s %s", ra.Role, ra.ResourceType, ra.ResourceName))
+			continue
+		}
+		if _, err := assoc.associate(fmt.Sprintf("%sroles/%d/%s/", prefix, roleID, path), granteeID); err != nil {
+			logger(fmt.Sprintf("  FAIL: %s: %v", label, err))
+			continue
+		}
+		logger("  " + label)
+	}
+}
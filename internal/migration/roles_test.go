@@ -0,0 +1,170 @@
+package migration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestExportRoleAssignments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/teams/2/roles/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[
+				{"id":50,"name":"Execute","summary_fields":{"resource_type":"job_template","resource_name":"Deploy"}},
+				{"id":51,"name":"Execute","summary_fields":{"resource_type":"workflow_job_template","resource_name":"Release"}}]}`))
+		case "/api/v2/users/5/roles/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[
+				{"id":60,"name":"Use","summary_fields":{"resource_type":"credential","resource_name":"Vault"}}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		Teams: []models.Resource{{"id": float64(2), "name": "Ops"}},
+		Users: []models.Resource{{"id": float64(5), "username": "alice"}},
+	}
+	exportRoleAssignments(newTestClient(t, ts), "/api/v2/", data, func(string) {})
+
+	want := []RoleAssignment{
+		{ResourceType: "job_templates", ResourceName: "Deploy", Role: "Execute", Team: "Ops"},
+		{ResourceType: "credentials", ResourceName: "Vault", Role: "Use", User: "alice"},
+	}
+	if len(data.RoleAssignments) != len(want) {
+		t.Fatalf("RoleAssignments = %+v, want %+v", data.RoleAssignments, want)
+	}
+	for i, ra := range data.RoleAssignments {
+		if ra != want[i] {
+			t.Errorf("RoleAssignments[%d] = %+v, want %+v", i, ra, want[i])
+		}
+	}
+}
+
+func TestImportRoleAssignments_TeamExecuteOnJobTemplate(t *testing.T) {
+	var mu sync.Mutex
+	var granted []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v2/job_templates/30/":
+			w.Write([]byte(`{"id":30,"name":"Deploy","summary_fields":{"object_roles":{
+				"admin_role":{"id":76,"name":"Admin"},
+				"execute_role":{"id":77,"name":"Execute"}}}}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v2/roles/77/teams/":
+			var body struct {
+				ID int `json:"id"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			granted = append(granted, body.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{RoleAssignments: []RoleAssignment{
+		{ResourceType: "job_templates", ResourceName: "Deploy", Role: "Execute", Team: "Ops"},
+		{ResourceType: "job_templates", ResourceName: "Deploy", Role: "Approve", Team: "Ops"},
+		{ResourceType: "projects", ResourceName: "Playbooks", Role: "Use", Team: "Ops"},
+	}}
+	ids := newIDMap()
+	ids.teams["Ops"] = 20
+	ids.jts["Deploy"] = 30
+
+	client := newTestClient(t, ts)
+	var logs []string
+	importRoleAssignments(client, "/api/v2/", data, ids, newAssociator(client), func(s string) { logs = append(logs, s) })
+
+	if len(granted) != 1 || granted[0] != 20 {
+		t.Errorf("granted = %v, want team 20 on execute role", granted)
+	}
+	for _, line := range []string{
+		"  Ops → job_templates Deploy (Execute)",
+		`  WARNING: role "Approve" not found on job_templates Deploy`,
+		"  SKIP: Ops → projects Playbooks (Use) (not migrated)",
+	} {
+		if !containsLine(logs, line) {
+			t.Errorf("missing %q in %q", line, logs)
+		}
+	}
+}
+
+func TestImportRoleAssignments_CredentialAndProjectRoles(t *testing.T) {
+	var mu sync.Mutex
+	granted := make(map[string][]int) // grant path → grantee IDs
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v2/credentials/10/":
+			w.Write([]byte(`{"id":10,"name":"Vault","summary_fields":{"object_roles":{
+				"admin_role":{"id":90,"name":"Admin"},
+				"use_role":{"id":91,"name":"Use"},
+				"read_role":{"id":92,"name":"Read"}}}}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v2/projects/12/":
+			w.Write([]byte(`{"id":12,"name":"Playbooks","summary_fields":{"object_roles":{
+				"admin_role":{"id":95,"name":"Admin"},
+				"use_role":{"id":96,"name":"Use"},
+				"update_role":{"id":97,"name":"Update"}}}}`))
+		case r.Method == "POST":
+			var body struct {
+				ID int `json:"id"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			granted[r.URL.Path] = append(granted[r.URL.Path], body.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{RoleAssignments: []RoleAssignment{
+		{ResourceType: "credentials", ResourceName: "Vault", Role: "Use", Team: "Ops"},
+		{ResourceType: "credentials", ResourceName: "Vault", Role: "Admin", User: "alice"},
+		{ResourceType: "projects", ResourceName: "Playbooks", Role: "Use", Team: "Ops"},
+		{ResourceType: "projects", ResourceName: "Playbooks", Role: "Admin", User: "alice"},
+	}}
+	ids := newIDMap()
+	ids.teams["Ops"] = 20
+	ids.users["alice"] = 21
+	ids.creds["Vault"] = 10
+	ids.projects["Playbooks"] = 12
+
+	client := newTestClient(t, ts)
+	var logs []string
+	importRoleAssignments(client, "/api/v2/", data, ids, newAssociator(client), func(s string) { logs = append(logs, s) })
+
+	want := map[string][]int{
+		"/api/v2/roles/91/teams/": {20},
+		"/api/v2/roles/90/users/": {21},
+		"/api/v2/roles/96/teams/": {20},
+		"/api/v2/roles/95/users/": {21},
+	}
+	if len(granted) != len(want) {
+		t.Fatalf("granted = %v, want %v; logs: %q", granted, want, logs)
+	}
+	for path, grantees := range want {
+		if got := granted[path]; len(got) != 1 || got[0] != grantees[0] {
+			t.Errorf("%s granted to %v, want %v", path, got, grantees)
+		}
+	}
+	for _, line := range []string{
+		"  Ops → credentials Vault (Use)",
+		"  alice → credentials Vault (Admin)",
+		"  Ops → projects Playbooks (Use)",
+		"  alice → projects Playbooks (Admin)",
+	} {
+		if !containsLine(logs, line) {
+			t.Errorf("missing %q in %q", line, logs)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// rejectedRRuleParts are RRULE parts AWX accepts in older rrules but the
+// controller's schedule validation refuses.
+var rejectedRRuleParts = map[string]bool{
+	"BYSECOND":  true,
+	"BYWEEKNO":  true,
+	"BYYEARDAY": true,
+}
+
+// cleanRRule removes rejectedRRuleParts from every RRULE and EXRULE line of
+// rrule, returning the result and the names of the parts removed. DTSTART,
+// including its TZID, is kept as is.
+func cleanRRule(rrule string) (string, []string) {
+	var removed []string
+	tokens := strings.Fields(rrule)
+	for i, tok := range tokens {
+		kind, rule, ok := strings.Cut(tok, ":")
+		if !ok || (kind != "RRULE" && kind != "EXRULE") {
+			continue
+		}
+		var kept []string
+		for _, part := range strings.Split(rule, ";") {
+			key, _, _ := strings.Cut(part, "=")
+			if rejectedRRuleParts[strings.ToUpper(key)] {
+				removed = append(removed, key)
+				continue
+			}
+			kept = append(kept, part)
+		}
+		tokens[i] = kind + ":" + strings.Join(kept, ";")
+	}
+	return strings.Join(tokens, " "), removed
+}
+
+// schedulePayload builds the POST body for a migrated schedule: its rrule
+// cleaned for the controller, timezone, enabled state and extra_data prompts.
+func schedulePayload(sched models.Resource, logger func(string)) map[string]interface{} {
+	name := resourceName(sched)
+	rrule, removed := cleanRRule(stringField(sched, "rrule"))
+	if len(removed) > 0 {
+		logger(fmt.Sprintf("  WARNING: %s: removed unsupported rrule parts %s", name, strings.Join(removed, ", ")))
+	}
+	payload := map[string]interface{}{
+		"name":        name,
+		"description": stringField(sched, "description"),
+		"rrule":       rrule,
+	}
+	if tz := stringField(sched, "timezone"); tz != "" {
+		payload["timezone"] = tz
+	}
+	if enabled, ok := sched["enabled"].(bool); ok {
+		payload["enabled"] = enabled
+	}
+	if extra, ok := sched["extra_data"].(map[string]interface{}); ok && len(extra) > 0 {
+		payload["extra_data"] = extra
+	}
+	return payload
+}
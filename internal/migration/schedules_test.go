@@ -0,0 +1,93 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestCleanRRule(t *testing.T) {
+	tests := []struct {
+		in, want string
+		removed  []string
+	}{
+		{"DTSTART;TZID=Europe/Lisbon:20250101T090000 RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO",
+			"DTSTART;TZID=Europe/Lisbon:20250101T090000 RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO", nil},
+		{"DTSTART:20250101T090000Z RRULE:FREQ=YEARLY;BYWEEKNO=20;BYSECOND=0;INTERVAL=1",
+			"DTSTART:20250101T090000Z RRULE:FREQ=YEARLY;INTERVAL=1", []string{"BYWEEKNO", "BYSECOND"}},
+		{"DTSTART:20250101T090000Z RRULE:FREQ=DAILY EXRULE:FREQ=YEARLY;BYYEARDAY=1",
+			"DTSTART:20250101T090000Z RRULE:FREQ=DAILY EXRULE:FREQ=YEARLY", []string{"BYYEARDAY"}},
+	}
+	for _, tt := range tests {
+		got, removed := cleanRRule(tt.in)
+		if got != tt.want || !reflect.DeepEqual(removed, tt.removed) {
+			t.Errorf("cleanRRule(%q) = %q, %v; want %q, %v", tt.in, got, removed, tt.want, tt.removed)
+		}
+	}
+}
+
+func TestImportAll_ScheduleTimezoneAndEnabled(t *testing.T) {
+	var mu sync.Mutex
+	var posted map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && r.URL.Path == "/api/v2/job_templates/40/schedules/" {
+			json.NewDecoder(r.Body).Decode(&posted)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":9}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{
+		JobTemplates: []models.Resource{{"id": float64(4), "name": "Deploy"}},
+		Schedules: []models.Resource{{
+			"id":         float64(5),
+			"name":       "Nightly",
+			"rrule":      "DTSTART;TZID=America/New_York:20250101T020000 RRULE:FREQ=DAILY;INTERVAL=1;BYSECOND=0",
+			"timezone":   "America/New_York",
+			"enabled":    false,
+			"extra_data": map[string]interface{}{"env": "prod"},
+			"summary_fields": map[string]interface{}{
+				"unified_job_template": map[string]interface{}{"id": float64(4), "name": "Deploy"},
+			},
+		}},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{
+		"job_templates": {{Name: "Deploy", Type: "job_templates", Action: "skip_exists", DestID: 40}},
+	}}
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	if posted == nil {
+		t.Fatalf("schedule was not created; logs: %q", logs)
+	}
+	if got := posted["rrule"]; got != "DTSTART;TZID=America/New_York:20250101T020000 RRULE:FREQ=DAILY;INTERVAL=1" {
+		t.Errorf("rrule = %v", got)
+	}
+	if got := posted["timezone"]; got != "America/New_York" {
+		t.Errorf("timezone = %v, want America/New_York", got)
+	}
+	if got, ok := posted["enabled"]; !ok || got != false {
+		t.Errorf("enabled = %v, want false", got)
+	}
+	if extra, _ := posted["extra_data"].(map[string]interface{}); extra["env"] != "prod" {
+		t.Errorf("extra_data = %v, want env=prod", posted["extra_data"])
+	}
+	if !containsLine(logs, "  WARNING: Nightly: removed unsupported rrule parts BYSECOND") {
+		t.Errorf("missing rrule warning in %q", logs)
+	}
+}
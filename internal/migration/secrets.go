@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// CredentialSecrets supplies credential inputs that cannot be exported from
+// the source: credential name → input key → value. Values may reference
+// environment variables as ${VAR}.
+type CredentialSecrets map[string]map[string]string
+
+// resolve returns the inputs configured for a credential and the sorted keys
+// that were filled. Keys whose value expands to an empty string are left out
+// and returned as empty.
+func (s CredentialSecrets) resolve(name string) (inputs map[string]interface{}, filled, empty []string) {
+	inputs = make(map[string]interface{})
+	for key, raw := range s[name] {
+		value := models.ExpandEnv(raw)
+		if value == "" {
+			empty = append(empty, key)
+			continue
+		}
+		inputs[key] = value
+		filled = append(filled, key)
+	}
+	sort.Strings(filled)
+	sort.Strings(empty)
+	return inputs, filled, empty
+}
+
+// secretsNote describes how a created credential's inputs were populated.
+func secretsNote(filled, empty []string) string {
+	switch {
+	case len(filled) == 0 && len(empty) == 0:
+		return "[inputs empty — set secrets manually]"
+	case len(filled) == 0:
+		return "[inputs empty — no value for " + strings.Join(empty, ", ") + "]"
+	case len(empty) == 0:
+		return "[inputs set: " + strings.Join(filled, ", ") + "]"
+	default:
+		return "[inputs partially set: " + strings.Join(filled, ", ") + "; no value for " + strings.Join(empty, ", ") + "]"
+	}
+}
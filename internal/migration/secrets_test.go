@@ -0,0 +1,71 @@
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCredentialSecrets_Resolve(t *testing.T) {
+	t.Setenv("WB_TEST_PASSWORD", "s3cr$t")
+	t.Setenv("WB_TEST_EMPTY", "")
+	secrets := CredentialSecrets{
+		"Machine": {"username": "deploy", "password": "${WB_TEST_PASSWORD}"},
+		"Vault":   {"vault_id": "prod", "vault_password": "${WB_TEST_EMPTY}"},
+	}
+
+	tests := []struct {
+		name       string
+		credential string
+		inputs     map[string]interface{}
+		filled     []string
+		empty      []string
+		note       string
+	}{
+		{
+			name:       "full mapping",
+			credential: "Machine",
+			inputs:     map[string]interface{}{"username": "deploy", "password": "s3cr$t"},
+			filled:     []string{"password", "username"},
+			note:       "[inputs set: password, username]",
+		},
+		{
+			name:       "partial mapping",
+			credential: "Vault",
+			inputs:     map[string]interface{}{"vault_id": "prod"},
+			filled:     []string{"vault_id"},
+			empty:      []string{"vault_password"},
+			note:       "[inputs partially set: vault_id; no value for vault_password]",
+		},
+		{
+			name:       "missing mapping",
+			credential: "SCM",
+			inputs:     map[string]interface{}{},
+			note:       "[inputs empty — set secrets manually]",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inputs, filled, empty := secrets.resolve(tc.credential)
+			if !reflect.DeepEqual(inputs, tc.inputs) {
+				t.Errorf("inputs = %v, want %v", inputs, tc.inputs)
+			}
+			if !reflect.DeepEqual(filled, tc.filled) {
+				t.Errorf("filled = %v, want %v", filled, tc.filled)
+			}
+			if !reflect.DeepEqual(empty, tc.empty) {
+				t.Errorf("empty = %v, want %v", empty, tc.empty)
+			}
+			if got := secretsNote(filled, empty); got != tc.note {
+				t.Errorf("note = %q, want %q", got, tc.note)
+			}
+		})
+	}
+}
+
+func TestCredentialSecrets_NilMap(t *testing.T) {
+	var secrets CredentialSecrets
+	inputs, filled, empty := secrets.resolve("Machine")
+	if len(inputs) != 0 || filled != nil || empty != nil {
+		t.Errorf("resolve on nil secrets = (%v, %v, %v), want empty", inputs, filled, empty)
+	}
+}
@@ -0,0 +1,164 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// Subdirectories of a streamed export holding one JSON file per inventory,
+// named by the inventory's source ID.
+const (
+	streamHosts      = "hosts"
+	streamGroups     = "groups"
+	streamGroupHosts = "group_hosts"
+)
+
+// ExportStreaming exports all migratable resources from src into outputDir.
+// Hosts, groups and group memberships, which dominate the size of large
+// instances, are written per inventory as they are fetched instead of being
+// kept in memory; the remaining resources go to data.json next to
+// header.json. ReadExportDir loads the result back, and RunFromArchive
+// imports it.
+func ExportStreaming(ctx context.Context, src *models.Connection, outputDir string, opts ExportOptions, logger func(string)) error {
+	client := platform.NewClient(src).WithContext(ctx)
+	prefix := apiPrefix(src)
+
+	logger("Checking source connectivity...")
+	if _, err := client.Get(prefix+"organizations/", nil); err != nil {
+		return fmt.Errorf("source connection failed: %w", err)
+	}
+	logger("Source OK: " + src.Name)
+
+	for _, dir := range []string{streamHosts, streamGroups, streamGroupHosts} {
+		if err := os.MkdirAll(filepath.Join(outputDir, dir), 0755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+	opts.inventorySink = func(invID int, c inventoryContents) error {
+		name := strconv.Itoa(invID) + ".json"
+		if err := writeJSONFile(filepath.Join(outputDir, streamHosts, name), c.hosts); err != nil {
+			return err
+		}
+		if c.groups == nil {
+			return nil
+		}
+		if err := writeJSONFile(filepath.Join(outputDir, streamGroups, name), c.groups); err != nil {
+			return err
+		}
+		return writeJSONFile(filepath.Join(outputDir, streamGroupHosts, name), c.groupHosts)
+	}
+
+	logger("")
+	logger("=== Exporting from source ===")
+	data, err := exportAll(ctx, client, prefix, opts, logger)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	bundle := NewBundle(src, data, time.Now())
+	if err := writeJSONFile(filepath.Join(outputDir, archiveHeader), bundle.Header); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(outputDir, archiveData), bundle.Data); err != nil {
+		return err
+	}
+	logger("Export written to " + outputDir)
+	return nil
+}
+
+// ReadExportDir loads a bundle from a directory written by ExportStreaming
+// and validates it like ReadArchive.
+func ReadExportDir(dir string) (*Bundle, error) {
+	bundle := &Bundle{Data: &ExportedData{}}
+	if err := readJSONFile(filepath.Join(dir, archiveHeader), &bundle.Header); err != nil {
+		return nil, err
+	}
+	if err := readJSONFile(filepath.Join(dir, archiveData), bundle.Data); err != nil {
+		return nil, err
+	}
+	data := bundle.Data
+	data.Hosts = make(map[int][]models.Resource)
+	data.Groups = make(map[int][]models.Resource)
+	data.GroupHosts = make(map[int][]int)
+
+	err := readInventoryFiles(filepath.Join(dir, streamHosts), func(invID int, path string) error {
+		var hosts []models.Resource
+		err := readJSONFile(path, &hosts)
+		data.Hosts[invID] = hosts
+		return err
+	})
+	if err == nil {
+		err = readInventoryFiles(filepath.Join(dir, streamGroups), func(invID int, path string) error {
+			var groups []models.Resource
+			err := readJSONFile(path, &groups)
+			data.Groups[invID] = groups
+			return err
+		})
+	}
+	if err == nil {
+		err = readInventoryFiles(filepath.Join(dir, streamGroupHosts), func(invID int, path string) error {
+			var groupHosts map[int][]int
+			err := readJSONFile(path, &groupHosts)
+			for gID, hostIDs := range groupHosts {
+				data.GroupHosts[gID] = append(data.GroupHosts[gID], hostIDs...)
+			}
+			return err
+		})
+	}
+	if err == nil {
+		err = validateData(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// readInventoryFiles calls fn for each "<inventory ID>.json" file in dir.
+func readInventoryFiles(dir string, fn func(invID int, path string) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		invID, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil || e.IsDir() {
+			continue
+		}
+		if err := fn(invID, filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func readJSONFile(path string, v interface{}) error {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestExportInventoryContents_StreamsInBoundedWindow(t *testing.T) {
+	const inventories, hostsPer, workers = 20, 50, 2
+
+	var mu sync.Mutex
+	served := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/hosts/") {
+			mu.Lock()
+			served++
+			mu.Unlock()
+			var hosts []string
+			for i := 0; i < hostsPer; i++ {
+				hosts = append(hosts, fmt.Sprintf(`{"id":%d,"name":"h%d"}`, i, i))
+			}
+			fmt.Fprintf(w, `{"count":%d,"next":null,"results":[%s]}`, hostsPer, strings.Join(hosts, ","))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{Disabled: make(map[string]int), Hosts: make(map[int][]models.Resource)}
+	for i := 1; i <= inventories; i++ {
+		data.Inventories = append(data.Inventories, models.Resource{"id": float64(i), "name": fmt.Sprintf("inv%d", i)})
+	}
+
+	var order []int
+	opts := ExportOptions{Concurrency: workers}
+	opts.inventorySink = func(invID int, c inventoryContents) error {
+		mu.Lock()
+		defer mu.Unlock()
+		// Only the inventory being stored and the ones filling the other
+		// worker slots may have been fetched so far.
+		if max := len(order) + workers; served > max {
+			t.Errorf("inventory %d stored after %d host lists were fetched, want at most %d", invID, served, max)
+		}
+		if len(c.hosts) != hostsPer {
+			t.Errorf("inventory %d: %d hosts, want %d", invID, len(c.hosts), hostsPer)
+		}
+		order = append(order, invID)
+		return nil
+	}
+	if err := exportInventoryContents(context.Background(), newTestClient(t, ts), "/api/v2/", opts, false, data, func(string) {}); err != nil {
+		t.Fatalf("exportInventoryContents returned error: %v", err)
+	}
+
+	if len(order) != inventories {
+		t.Fatalf("sink called %d times, want %d", len(order), inventories)
+	}
+	for i, invID := range order {
+		if invID != i+1 {
+			t.Fatalf("sink order = %v, want inventory order", order)
+		}
+	}
+	if len(data.Hosts) != 0 {
+		t.Errorf("streamed hosts were also kept in memory for %d inventories", len(data.Hosts))
+	}
+}
+
+func TestExportStreaming_RoundTrip(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/organizations/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Eng"}]}`))
+		case "/api/v2/inventories/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":3,"name":"Prod"}]}`))
+		case "/api/v2/inventories/3/hosts/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":10,"name":"web1"},{"id":11,"name":"web2"}]}`))
+		case "/api/v2/inventories/3/groups/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":20,"name":"web"}]}`))
+		case "/api/v2/groups/20/hosts/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":10,"name":"web1"}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	if err := ExportStreaming(context.Background(), newTestConnection(t, ts), dir, ExportOptions{}, func(string) {}); err != nil {
+		t.Fatalf("ExportStreaming returned error: %v", err)
+	}
+
+	bundle, err := ReadArchive(dir)
+	if err != nil {
+		t.Fatalf("ReadArchive returned error: %v", err)
+	}
+	data := bundle.Data
+	if len(data.Organizations) != 1 || len(data.Inventories) != 1 {
+		t.Errorf("got %d organizations and %d inventories, want 1 each", len(data.Organizations), len(data.Inventories))
+	}
+	if n := len(data.Hosts[3]); n != 2 {
+		t.Errorf("inventory 3 has %d hosts, want 2", n)
+	}
+	if n := len(data.Groups[3]); n != 1 {
+		t.Errorf("inventory 3 has %d groups, want 1", n)
+	}
+	if got := data.GroupHosts[20]; len(got) != 1 || got[0] != 10 {
+		t.Errorf("group 20 hosts = %v, want [10]", got)
+	}
+}
@@ -0,0 +1,115 @@
+package migration
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TransformRule rewrites one field of every migrated resource of a type:
+// each match of the regular expression Find is replaced with Replace, which
+// may reference groups as $1. Typical uses are renaming resources or
+// pointing scm_url at an internal mirror.
+type TransformRule struct {
+	Type    string
+	Field   string
+	Find    string
+	Replace string
+}
+
+// NameAffix adds a fixed prefix and/or suffix to the names of every migrated
+// resource of a type, e.g. "DEV-" when testing against a shared destination.
+type NameAffix struct {
+	Type   string
+	Prefix string
+	Suffix string
+}
+
+// Rules returns the name transform rules that apply the affix. References
+// between resources still resolve, since they are tracked by source name.
+func (a NameAffix) Rules() []TransformRule {
+	field := "name"
+	if a.Type == "users" {
+		field = "username"
+	}
+	var rules []TransformRule
+	if a.Prefix != "" {
+		rules = append(rules, TransformRule{Type: a.Type, Field: field, Find: "^", Replace: escapeReplacement(a.Prefix)})
+	}
+	if a.Suffix != "" {
+		rules = append(rules, TransformRule{Type: a.Type, Field: field, Find: "$", Replace: escapeReplacement(a.Suffix)})
+	}
+	return rules
+}
+
+// escapeReplacement quotes s for use as a literal regexp replacement.
+func escapeReplacement(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
+
+// Transforms is a compiled set of transform rules. A nil *Transforms
+// changes nothing.
+type Transforms struct {
+	rules map[string][]compiledRule // resource type → rules, in config order
+}
+
+type compiledRule struct {
+	field   string
+	find    *regexp.Regexp
+	replace string
+}
+
+// NewTransforms validates and compiles rules.
+func NewTransforms(rules []TransformRule) (*Transforms, error) {
+	t := &Transforms{rules: make(map[string][]compiledRule)}
+	for i, r := range rules {
+		if err := ValidateTypes([]string{r.Type}); err != nil {
+			return nil, fmt.Errorf("transform %d: %w", i+1, err)
+		}
+		if r.Field == "" {
+			return nil, fmt.Errorf("transform %d: field is required", i+1)
+		}
+		find, err := regexp.Compile(r.Find)
+		if err != nil {
+			return nil, fmt.Errorf("transform %d: find: %w", i+1, err)
+		}
+		t.rules[r.Type] = append(t.rules[r.Type], compiledRule{field: r.Field, find: find, replace: r.Replace})
+	}
+	return t, nil
+}
+
+// apply rewrites the string fields of a payload about to be sent for a
+// resource of typeName, logging each field it changes.
+func (t *Transforms) apply(typeName, name string, payload map[string]interface{}, logger func(string)) {
+	if t == nil {
+		return
+	}
+	for _, r := range t.rules[typeName] {
+		old, ok := payload[r.field].(string)
+		if !ok {
+			continue
+		}
+		if v := r.find.ReplaceAllString(old, r.replace); v != old {
+			payload[r.field] = v
+			logger(fmt.Sprintf("  TRANSFORM: %s %s: %q → %q", name, r.field, old, v))
+		}
+	}
+}
+
+// name returns the name a resource will have on the destination after its
+// name rules ("username" for users) are applied.
+func (t *Transforms) name(typeName, name string) string {
+	if t == nil {
+		return name
+	}
+	field := "name"
+	if typeName == "users" {
+		field = "username"
+	}
+	for _, r := range t.rules[typeName] {
+		if r.field == field {
+			name = r.find.ReplaceAllString(name, r.replace)
+		}
+	}
+	return name
+}
@@ -0,0 +1,168 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestNewTransforms_Invalid(t *testing.T) {
+	for name, rule := range map[string]TransformRule{
+		"unknown type":  {Type: "widgets", Field: "name", Find: "a"},
+		"missing field": {Type: "projects", Find: "a"},
+		"bad regexp":    {Type: "projects", Field: "scm_url", Find: "("},
+	} {
+		if _, err := NewTransforms([]TransformRule{rule}); err == nil {
+			t.Errorf("%s: NewTransforms returned nil error", name)
+		}
+	}
+}
+
+func TestImportAll_TransformsSCMURL(t *testing.T) {
+	var mu sync.Mutex
+	var posted map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && r.URL.Path == "/api/v2/projects/" {
+			json.NewDecoder(r.Body).Decode(&posted)
+			w.Write([]byte(`{"id":30}`))
+			return
+		}
+		if r.URL.Path == "/api/v2/projects/30/" {
+			w.Write([]byte(`{"id":30,"status":"successful"}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	tf, err := NewTransforms([]TransformRule{
+		{Type: "projects", Field: "scm_url", Find: `^https://github\.com/`, Replace: "https://git.mirror.internal/"},
+		{Type: "projects", Field: "name", Find: `^(.*)$`, Replace: "$1 (mirror)"},
+	})
+	if err != nil {
+		t.Fatalf("NewTransforms: %v", err)
+	}
+	data := &ExportedData{
+		Projects: []models.Resource{{"id": float64(3), "name": "Playbooks", "scm_type": "git",
+			"scm_url": "https://github.com/acme/playbooks.git"}},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+
+	var logs []string
+	err = importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{Transforms: tf}, nil, nil,
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	if got := posted["scm_url"]; got != "https://git.mirror.internal/acme/playbooks.git" {
+		t.Errorf("posted scm_url = %v, want the mirror URL", got)
+	}
+	if got := posted["name"]; got != "Playbooks (mirror)" {
+		t.Errorf("posted name = %v, want %q", got, "Playbooks (mirror)")
+	}
+	if !containsLine(logs, `  TRANSFORM: Playbooks scm_url: "https://github.com/acme/playbooks.git" → "https://git.mirror.internal/acme/playbooks.git"`) {
+		t.Errorf("missing TRANSFORM log line in %q", logs)
+	}
+	if got := tf.name("projects", "Playbooks"); got != "Playbooks (mirror)" {
+		t.Errorf("tf.name = %q, want the renamed project", got)
+	}
+}
+
+func TestNameAffix_Rules(t *testing.T) {
+	rules := NameAffix{Type: "users", Prefix: "$DEV-", Suffix: "-tmp"}.Rules()
+	tf, err := NewTransforms(rules)
+	if err != nil {
+		t.Fatalf("NewTransforms: %v", err)
+	}
+	if got := tf.name("users", "alice"); got != "$DEV-alice-tmp" {
+		t.Errorf("tf.name = %q, want %q", got, "$DEV-alice-tmp")
+	}
+	if rules := (NameAffix{Type: "teams"}).Rules(); len(rules) != 0 {
+		t.Errorf("empty affix produced %d rules", len(rules))
+	}
+}
+
+func TestImportAll_NameAffixWiresReferences(t *testing.T) {
+	var mu sync.Mutex
+	posted := make(map[string]map[string]interface{}) // path → body
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			posted[r.URL.Path] = body
+			switch r.URL.Path {
+			case "/api/v2/organizations/":
+				w.Write([]byte(`{"id":10}`))
+			default:
+				w.Write([]byte(`{"id":20}`))
+			}
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	var rules []TransformRule
+	for _, rt := range []string{"organizations", "teams"} {
+		rules = append(rules, NameAffix{Type: rt, Prefix: "DEV-"}.Rules()...)
+	}
+	tf, err := NewTransforms(rules)
+	if err != nil {
+		t.Fatalf("NewTransforms: %v", err)
+	}
+	data := &ExportedData{
+		Organizations: []models.Resource{{"id": float64(1), "name": "Eng"}},
+		Teams: []models.Resource{{"id": float64(2), "name": "Ops",
+			"summary_fields": map[string]interface{}{"organization": map[string]interface{}{"name": "Eng"}}}},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+	err = importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{Transforms: tf}, nil, nil, func(string) {})
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	if got := posted["/api/v2/organizations/"]["name"]; got != "DEV-Eng" {
+		t.Errorf("organization name = %v, want DEV-Eng", got)
+	}
+	team := posted["/api/v2/teams/"]
+	if got := team["name"]; got != "DEV-Ops" {
+		t.Errorf("team name = %v, want DEV-Ops", got)
+	}
+	if got := team["organization"]; got != float64(10) {
+		t.Errorf("team organization = %v, want 10", got)
+	}
+}
+
+func TestPreflightCheck_NameAffix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") == "DEV-Eng" {
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":5,"name":"DEV-Eng"}]}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	tf, err := NewTransforms(NameAffix{Type: "organizations", Prefix: "DEV-"}.Rules())
+	if err != nil {
+		t.Fatalf("NewTransforms: %v", err)
+	}
+	data := &ExportedData{Organizations: []models.Resource{{"id": float64(1), "name": "Eng"}}}
+	preview, err := preflightCheck(data, newTestClient(t, ts), "/api/v2/", nil, nil, tf, func(string) {})
+	if err != nil {
+		t.Fatalf("preflightCheck returned error: %v", err)
+	}
+	if got := preview.Resources["organizations"][0]; got.Action != "skip_exists" || got.DestID != 5 {
+		t.Errorf("organization preview = %+v, want skip_exists with dest ID 5", got)
+	}
+}
@@ -0,0 +1,78 @@
+package migration
+
+import "fmt"
+
+// typeDependencies lists, per resource type, the types it cannot be imported
+// without (e.g. a job template needs its project and inventory).
+var typeDependencies = map[string][]string{
+	"teams":                  {"organizations"},
+	"credentials":            {"organizations", "credential_types"},
+	"execution_environments": {"organizations"},
+	"applications":           {"organizations"},
+	"projects":               {"organizations", "credentials"},
+	"inventories":            {"organizations"},
+	"hosts":                  {"inventories"},
+	"groups":                 {"hosts"},
+	"notification_templates": {"organizations"},
+	"job_templates":          {"projects", "inventories", "credentials"},
+	"workflow_job_templates": {"organizations", "job_templates"},
+	"schedules":              {"job_templates", "workflow_job_templates"},
+}
+
+// typeSet is a set of selected resource types. A nil set selects every type.
+type typeSet map[string]bool
+
+func (s typeSet) has(rt string) bool {
+	return s == nil || s[rt]
+}
+
+// list returns the selected types in preview order, or nil for all types.
+func (s typeSet) list() []string {
+	if s == nil {
+		return nil
+	}
+	var types []string
+	for _, rt := range previewOrder {
+		if s[rt] {
+			types = append(types, rt)
+		}
+	}
+	return types
+}
+
+// ValidateTypes returns an error if any entry is not a migratable resource type.
+func ValidateTypes(types []string) error {
+	known := make(map[string]bool, len(previewOrder))
+	for _, rt := range previewOrder {
+		known[rt] = true
+	}
+	for _, rt := range types {
+		if !known[rt] {
+			return fmt.Errorf("unknown resource type %q", rt)
+		}
+	}
+	return nil
+}
+
+// selectTypes expands types with everything they depend on. No types
+// selects all of them.
+func selectTypes(types []string) typeSet {
+	if len(types) == 0 {
+		return nil
+	}
+	set := make(typeSet)
+	var add func(rt string)
+	add = func(rt string) {
+		if set[rt] {
+			return
+		}
+		set[rt] = true
+		for _, dep := range typeDependencies[rt] {
+			add(dep)
+		}
+	}
+	for _, rt := range types {
+		add(rt)
+	}
+	return set
+}
@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateTypes(t *testing.T) {
+	if err := ValidateTypes([]string{"organizations", "schedules"}); err != nil {
+		t.Errorf("ValidateTypes(known) = %v, want nil", err)
+	}
+	if err := ValidateTypes(nil); err != nil {
+		t.Errorf("ValidateTypes(nil) = %v, want nil", err)
+	}
+	if err := ValidateTypes([]string{"organizations", "widgets"}); err == nil {
+		t.Error("ValidateTypes(unknown) = nil, want error")
+	}
+}
+
+func TestSelectTypes_AddsDependencies(t *testing.T) {
+	if got := selectTypes(nil); got != nil || !got.has("schedules") {
+		t.Errorf("selectTypes(nil) = %v, want every type", got)
+	}
+
+	got := selectTypes([]string{"groups", "teams"}).list()
+	want := []string{"organizations", "teams", "inventories", "hosts", "groups"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("selectTypes(groups, teams) = %v, want %v", got, want)
+	}
+}
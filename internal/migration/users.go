@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// DefaultUserPassword is given to migrated users, whose passwords cannot be
+// exported, unless UserOptions.Password is set.
+const DefaultUserPassword = "changeme!"
+
+// UserOptions controls how migrated users are created. A nil *UserOptions
+// creates ordinary users with DefaultUserPassword.
+type UserOptions struct {
+	Password          string // placeholder password; empty means DefaultUserPassword
+	SystemAuditor     bool   // make every migrated user a system auditor
+	PreserveSuperuser bool   // keep is_superuser from the source instead of clearing it
+}
+
+// apply sets the password and privilege fields of a user payload.
+func (o *UserOptions) apply(user models.Resource, name string, payload map[string]interface{}, logger func(string)) {
+	payload["password"] = DefaultUserPassword
+	payload["is_superuser"] = false
+	if o == nil {
+		return
+	}
+	if o.Password != "" {
+		payload["password"] = o.Password
+	}
+	if o.SystemAuditor {
+		payload["is_system_auditor"] = true
+	}
+	if o.PreserveSuperuser && boolField(user, "is_superuser") {
+		payload["is_superuser"] = true
+		logger(fmt.Sprintf("  WARNING: %s: superuser preserved from source", name))
+	}
+}
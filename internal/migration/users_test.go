@@ -0,0 +1,74 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// importUsers runs an import of two users, one a superuser on the source,
+// and returns the POSTed user payloads by username.
+func importUsers(t *testing.T, users *UserOptions) map[string]map[string]interface{} {
+	t.Helper()
+	var mu sync.Mutex
+	posted := make(map[string]map[string]interface{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && r.URL.Path == "/api/v2/users/" {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			posted[body["username"].(string)] = body
+			w.Write([]byte(`{"id":7}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	data := &ExportedData{Users: []models.Resource{
+		{"id": float64(1), "username": "alice", "is_superuser": true},
+		{"id": float64(2), "username": "bob", "is_superuser": false},
+	}}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{}}
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview, ImportOptions{Users: users}, nil, nil, func(string) {})
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+	return posted
+}
+
+func TestImportAll_UserDefaults(t *testing.T) {
+	posted := importUsers(t, nil)
+	for _, name := range []string{"alice", "bob"} {
+		if got := posted[name]["password"]; got != DefaultUserPassword {
+			t.Errorf("%s password = %v, want %q", name, got, DefaultUserPassword)
+		}
+		if got := posted[name]["is_superuser"]; got != false {
+			t.Errorf("%s is_superuser = %v, want false", name, got)
+		}
+	}
+}
+
+func TestImportAll_UserOptions(t *testing.T) {
+	posted := importUsers(t, &UserOptions{Password: "Welcome-2026", SystemAuditor: true, PreserveSuperuser: true})
+	for _, name := range []string{"alice", "bob"} {
+		if got := posted[name]["password"]; got != "Welcome-2026" {
+			t.Errorf("%s password = %v, want the configured one", name, got)
+		}
+		if got := posted[name]["is_system_auditor"]; got != true {
+			t.Errorf("%s is_system_auditor = %v, want true", name, got)
+		}
+	}
+	if got := posted["alice"]["is_superuser"]; got != true {
+		t.Errorf("alice is_superuser = %v, want it preserved", got)
+	}
+	if got := posted["bob"]["is_superuser"]; got != false {
+		t.Errorf("bob is_superuser = %v, want false", got)
+	}
+}
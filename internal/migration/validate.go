@@ -0,0 +1,84 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// validateData checks data decoded from an archive or export directory before
+// anything is imported: every map importAll reads must be present, and job
+// templates may only reference organizations, projects and inventories that
+// are part of the same export or are defaults such as Demo Project, which
+// exports leave out and the destination already has. All problems are
+// reported in one error.
+func validateData(data *ExportedData) error {
+	var problems []string
+
+	maps := []struct {
+		key string
+		nil bool
+	}{
+		{"managed_credential_type_kinds", data.ManagedCredTypeKinds == nil},
+		{"hosts", data.Hosts == nil},
+		{"groups", data.Groups == nil},
+		{"group_hosts", data.GroupHosts == nil},
+		{"inventory_sources", data.InventorySources == nil},
+		{"surveys", data.Surveys == nil},
+		{"labels", data.Labels == nil},
+		{"workflow_labels", data.WorkflowLabels == nil},
+		{"notifications", data.Notifications == nil},
+		{"workflow_notifications", data.WorkflowNotifications == nil},
+		{"instance_groups", data.InstanceGroups == nil},
+		{"galaxy_credentials", data.GalaxyCredentials == nil},
+		{"workflow_nodes", data.WorkflowNodes == nil},
+		{"node_credentials", data.NodeCredentials == nil},
+		{"org_users", data.OrgUsers == nil},
+		{"team_users", data.TeamUsers == nil},
+		{"disabled", data.Disabled == nil},
+	}
+	var missing []string
+	for _, m := range maps {
+		if m.nil {
+			missing = append(missing, m.key)
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(missing, ", "))
+	}
+
+	orgs := namesOf(data.Organizations)
+	projects := namesOf(data.Projects)
+	invs := namesOf(data.Inventories)
+	for _, jt := range data.JobTemplates {
+		name := resourceName(jt)
+		refs := []struct {
+			kind, typeName, name string
+			known                map[string]bool
+		}{
+			{"organization", "organizations", extractOrgName(jt), orgs},
+			{"project", "projects", extractProjectName(jt), projects},
+			{"inventory", "inventories", extractInventoryName(jt), invs},
+		}
+		for _, ref := range refs {
+			if ref.name != "" && !ref.known[ref.name] && !skipNames[ref.typeName][ref.name] {
+				problems = append(problems, fmt.Sprintf("job template %q references missing %s %q", name, ref.kind, ref.name))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid export data: %s", strings.Join(problems, "; "))
+}
+
+// namesOf returns the set of resource names in resources.
+func namesOf(resources []models.Resource) map[string]bool {
+	names := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		names[resourceName(r)] = true
+	}
+	return names
+}
@@ -0,0 +1,68 @@
+package migration
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestRunFromArchive_RejectsDanglingReferences(t *testing.T) {
+	data := newExportedData()
+	data.Organizations = []models.Resource{{"id": float64(1), "name": "Eng"}}
+	data.Inventories = []models.Resource{{"id": float64(2), "name": "Servers"}}
+	data.JobTemplates = []models.Resource{
+		{"id": float64(3), "name": "Deploy", "summary_fields": map[string]interface{}{
+			"organization": map[string]interface{}{"name": "Eng"},
+			"project":      map[string]interface{}{"name": "Playbooks"},
+			"inventory":    map[string]interface{}{"name": "Servers"},
+		}},
+	}
+	path := filepath.Join(t.TempDir(), "export.tar.gz")
+	if err := writeArchive(path, &Bundle{Header: BundleHeader{ExportedAt: time.Now()}, Data: data}); err != nil {
+		t.Fatalf("writeArchive: %v", err)
+	}
+
+	dstSrv := &createServer{posts: make(map[string]int)}
+	dst := httptest.NewServer(dstSrv)
+	defer dst.Close()
+
+	err := RunFromArchive(context.Background(), newTestConnection(t, dst), path, ImportOptions{}, nil, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), `job template "Deploy" references missing project "Playbooks"`) {
+		t.Fatalf("RunFromArchive error = %v, want dangling project reference", err)
+	}
+	if len(dstSrv.posts) != 0 {
+		t.Errorf("destination received POSTs %v, want none", dstSrv.posts)
+	}
+}
+
+func TestValidateData_AllowsDefaultReferences(t *testing.T) {
+	data := newExportedData()
+	data.JobTemplates = []models.Resource{
+		{"id": float64(3), "name": "Ping", "summary_fields": map[string]interface{}{
+			"organization": map[string]interface{}{"name": "Default"},
+			"project":      map[string]interface{}{"name": "Demo Project"},
+			"inventory":    map[string]interface{}{"name": "Demo Inventory"},
+		}},
+	}
+	if err := validateData(data); err != nil {
+		t.Errorf("validateData = %v, want defaults accepted", err)
+	}
+}
+
+func TestValidateData_MissingMaps(t *testing.T) {
+	data := newExportedData()
+	data.Hosts = nil
+	data.WorkflowNodes = nil
+	err := validateData(data)
+	if err == nil || !strings.Contains(err.Error(), "missing hosts, workflow_nodes") {
+		t.Fatalf("validateData error = %v, want missing hosts and workflow_nodes", err)
+	}
+	if err := validateData(newExportedData()); err != nil {
+		t.Errorf("validateData on empty export = %v, want nil", err)
+	}
+}
@@ -0,0 +1,102 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"github.com/rflorenc/ansible-automation-workbench/internal/platform"
+)
+
+// nodePromptFields are the launch prompt overrides of a workflow node that
+// are copied verbatim. Inventory and credentials are resolved separately.
+var nodePromptFields = []string{
+	"identifier", "extra_data", "limit", "scm_branch", "job_type", "job_tags",
+	"skip_tags", "diff_mode", "verbosity", "forks", "timeout", "job_slice_count",
+	"all_parents_must_converge",
+}
+
+// exportNodeCredentials records the names of the prompt credentials set on
+// each workflow node of workflow wfName.
+func exportNodeCredentials(client *platform.Client, prefix, wfName string, nodes []models.Resource, data *ExportedData, logger func(string)) {
+	for _, node := range nodes {
+		nodeID := resourceID(node)
+		creds, err := client.GetAll(fmt.Sprintf("%sworkflow_job_template_nodes/%d/credentials/", prefix, nodeID))
+		if err != nil {
+			logger(fmt.Sprintf("  WARNING: failed to get credentials for node %d of workflow %s: %v", nodeID, wfName, err))
+			continue
+		}
+		for _, c := range creds {
+			data.NodeCredentials[nodeID] = append(data.NodeCredentials[nodeID], resourceName(c))
+		}
+	}
+}
+
+// workflowNodePayload builds the POST body for a workflow node running
+// destUJTID, carrying over its prompt overrides. The inventory is resolved
+// by name through ids; one that was not migrated is dropped with a warning.
+func workflowNodePayload(node models.Resource, destUJTID int, ids *idMap, logger func(string)) map[string]interface{} {
+	payload := map[string]interface{}{"unified_job_template": destUJTID}
+	for _, f := range nodePromptFields {
+		if v, ok := node[f]; ok && v != nil {
+			payload[f] = v
+		}
+	}
+	if invName := extractInventoryName(node); invName != "" {
+		if invID := ids.invs[invName]; invID != 0 {
+			payload["inventory"] = invID
+		} else {
+			logger(fmt.Sprintf("  WARNING: node for %s: inventory %q not migrated, prompt dropped", extractUnifiedJTName(node), invName))
+		}
+	}
+	return payload
+}
+
+// isApprovalNode reports whether a workflow node waits for an approval
+// rather than running a job template or workflow.
+func isApprovalNode(node models.Resource) bool {
+	t, _ := summaryField(node, "unified_job_template", "unified_job_type").(string)
+	return t == "workflow_approval"
+}
+
+// createApprovalNode recreates an approval node under the workflow destWFID:
+// a node without a job template, then its approval template with the
+// source's name, description and timeout. A node whose template cannot be
+// created is deleted again.
+func createApprovalNode(dst *platform.Client, prefix string, destWFID int, node models.Resource) (int, error) {
+	payload := map[string]interface{}{}
+	for _, f := range []string{"identifier", "all_parents_must_converge"} {
+		if v, ok := node[f]; ok && v != nil {
+			payload[f] = v
+		}
+	}
+	nodeID, err := createResource(dst, fmt.Sprintf("%sworkflow_job_templates/%d/workflow_nodes/", prefix, destWFID), payload)
+	if err != nil {
+		return 0, err
+	}
+	description, _ := summaryField(node, "unified_job_template", "description").(string)
+	approval := map[string]interface{}{
+		"name":        extractUnifiedJTName(node),
+		"description": description,
+		"timeout":     toInt(summaryField(node, "unified_job_template", "timeout")),
+	}
+	if _, err := createResource(dst, fmt.Sprintf("%sworkflow_job_template_nodes/%d/create_approval_template/", prefix, nodeID), approval); err != nil {
+		dst.Delete(fmt.Sprintf("%sworkflow_job_template_nodes/%d/", prefix, nodeID))
+		return 0, err
+	}
+	return nodeID, nil
+}
+
+// attachNodeCredentials adds the exported prompt credentials to a created
+// workflow node, resolving them by name.
+func attachNodeCredentials(dst *platform.Client, prefix string, assoc *associator, destNodeID int, names []string, ids *idMap, logger func(string)) {
+	for _, name := range names {
+		credID := findMigrated(dst, prefix+"credentials/", ids.creds, name)
+		if credID == 0 {
+			logger(fmt.Sprintf("  WARNING: node %d: credential %q not found", destNodeID, name))
+			continue
+		}
+		if _, err := assoc.associate(fmt.Sprintf("%sworkflow_job_template_nodes/%d/credentials/", prefix, destNodeID), credID); err != nil {
+			logger(fmt.Sprintf("  FAIL: node %d → %s: %v", destNodeID, name, err))
+		}
+	}
+}
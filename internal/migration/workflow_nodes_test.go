@@ -0,0 +1,186 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestWorkflowNodePayload_ExtraDataOverride(t *testing.T) {
+	node := models.Resource{
+		"id":         float64(1),
+		"extra_data": map[string]interface{}{"env": "prod"},
+		"job_tags":   nil,
+	}
+	p := workflowNodePayload(node, 40, newIDMap(), func(string) {})
+	if p["unified_job_template"] != 40 {
+		t.Errorf("unified_job_template = %v, want 40", p["unified_job_template"])
+	}
+	extra, ok := p["extra_data"].(map[string]interface{})
+	if !ok || extra["env"] != "prod" {
+		t.Errorf("extra_data = %v, want env=prod", p["extra_data"])
+	}
+	if _, ok := p["job_tags"]; ok {
+		t.Error("null job_tags should not be sent")
+	}
+}
+
+func TestWorkflowNodePayload_LimitAndInventory(t *testing.T) {
+	node := models.Resource{
+		"id":    float64(1),
+		"limit": "webservers",
+		"summary_fields": map[string]interface{}{
+			"inventory":            map[string]interface{}{"id": float64(3), "name": "Prod"},
+			"unified_job_template": map[string]interface{}{"id": float64(4), "name": "Deploy"},
+		},
+	}
+	ids := newIDMap()
+	ids.invs["Prod"] = 30
+	p := workflowNodePayload(node, 40, ids, func(string) {})
+	if p["limit"] != "webservers" {
+		t.Errorf("limit = %v, want webservers", p["limit"])
+	}
+	if p["inventory"] != 30 {
+		t.Errorf("inventory = %v, want 30", p["inventory"])
+	}
+
+	var logs []string
+	p = workflowNodePayload(node, 40, newIDMap(), func(s string) { logs = append(logs, s) })
+	if _, ok := p["inventory"]; ok {
+		t.Error("unmigrated inventory should be dropped")
+	}
+	if !containsLine(logs, `  WARNING: node for Deploy: inventory "Prod" not migrated, prompt dropped`) {
+		t.Errorf("missing warning in %q", logs)
+	}
+}
+
+func TestAttachNodeCredentials(t *testing.T) {
+	var mu sync.Mutex
+	var attached []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && r.URL.Path == "/api/v2/workflow_job_template_nodes/70/credentials/" {
+			var body struct{ ID int }
+			json.NewDecoder(r.Body).Decode(&body)
+			attached = append(attached, body.ID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	client := newTestClient(t, ts)
+	ids := newIDMap()
+	ids.creds["Vault"] = 50
+	var logs []string
+	attachNodeCredentials(client, "/api/v2/", newAssociator(client), 70, []string{"Vault", "Missing"}, ids,
+		func(s string) { logs = append(logs, s) })
+
+	if len(attached) != 1 || attached[0] != 50 {
+		t.Errorf("attached = %v, want [50]", attached)
+	}
+	if !containsLine(logs, `  WARNING: node 70: credential "Missing" not found`) {
+		t.Errorf("missing warning in %q", logs)
+	}
+}
+
+func TestExportNodeCredentials_WarnsOnError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/workflow_job_template_nodes/7/credentials/" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Vault"}]}`))
+	}))
+	defer ts.Close()
+
+	data := newExportedData()
+	nodes := []models.Resource{{"id": float64(7)}, {"id": float64(8)}}
+	var logs []string
+	exportNodeCredentials(newTestClient(t, ts), "/api/v2/", "Release", nodes, data, func(s string) { logs = append(logs, s) })
+
+	if len(logs) != 1 || !strings.HasPrefix(logs[0], "  WARNING: failed to get credentials for node 7 of workflow Release: ") {
+		t.Errorf("logs = %q, want one WARNING for node 7", logs)
+	}
+	if got := data.NodeCredentials[8]; len(got) != 1 || got[0] != "Vault" {
+		t.Errorf("node 8 credentials = %v, want [Vault]", got)
+	}
+}
+
+func TestImportAll_ApprovalNode(t *testing.T) {
+	srv := &postRecorder{nextID: 100, posts: make(map[string][]map[string]interface{})}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ujt := func(name, kind string, extra map[string]interface{}) map[string]interface{} {
+		u := map[string]interface{}{"name": name, "unified_job_type": kind}
+		for k, v := range extra {
+			u[k] = v
+		}
+		return map[string]interface{}{"unified_job_template": u}
+	}
+	data := newExportedData()
+	data.JobTemplates = []models.Resource{{"id": float64(1), "name": "Build"}, {"id": float64(2), "name": "Deploy"}}
+	data.WorkflowJTs = []models.Resource{{"id": float64(5), "name": "Release"}}
+	data.WorkflowNodes[5] = []models.Resource{
+		{"id": float64(11), "success_nodes": []interface{}{float64(12)},
+			"summary_fields": ujt("Build", "job", nil)},
+		{"id": float64(12), "identifier": "gate", "success_nodes": []interface{}{float64(13)},
+			"summary_fields": ujt("Sign-off", "workflow_approval", map[string]interface{}{"description": "QA approves", "timeout": float64(3600)})},
+		{"id": float64(13), "summary_fields": ujt("Deploy", "job", nil)},
+	}
+	preview := &models.MigrationPreview{Resources: map[string][]models.MigrationResource{
+		"job_templates": {
+			{Name: "Build", Action: "skip_exists", DestID: 30},
+			{Name: "Deploy", Action: "skip_exists", DestID: 31},
+		},
+		"workflow_job_templates": {{Name: "Release", Action: "skip_exists", DestID: 50}},
+	}}
+
+	var logs []string
+	err := importAll(context.Background(), newTestClient(t, ts), "/api/v2/", data, preview,
+		ImportOptions{}, nil, nil, func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("importAll returned error: %v", err)
+	}
+
+	nodes := srv.posts["/api/v2/workflow_job_templates/50/workflow_nodes/"]
+	if len(nodes) != 3 {
+		t.Fatalf("%d nodes created, want 3: %v; logs: %q", len(nodes), nodes, logs)
+	}
+	// Node IDs follow POST order: Build 101, the approval node 102, its
+	// template 103, Deploy 104.
+	if nodes[0]["unified_job_template"] != float64(30) || nodes[2]["unified_job_template"] != float64(31) {
+		t.Errorf("job template nodes = %v, %v", nodes[0], nodes[2])
+	}
+	if _, ok := nodes[1]["unified_job_template"]; ok || nodes[1]["identifier"] != "gate" {
+		t.Errorf("approval node payload = %v, want an identifier and no job template", nodes[1])
+	}
+	approvals := srv.posts["/api/v2/workflow_job_template_nodes/102/create_approval_template/"]
+	want := map[string]interface{}{"name": "Sign-off", "description": "QA approves", "timeout": float64(3600)}
+	if len(approvals) != 1 {
+		t.Fatalf("approval templates created = %v, want 1", approvals)
+	}
+	for k, v := range want {
+		if approvals[0][k] != v {
+			t.Errorf("approval template %s = %v, want %v", k, approvals[0][k], v)
+		}
+	}
+	for path, target := range map[string]float64{
+		"/api/v2/workflow_job_template_nodes/101/success_nodes/": 102,
+		"/api/v2/workflow_job_template_nodes/102/success_nodes/": 104,
+	} {
+		edges := srv.posts[path]
+		if len(edges) != 1 || edges[0]["id"] != target {
+			t.Errorf("%s = %v, want node %v", path, edges, target)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,15 +17,25 @@ type Connection struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Type     string `json:"type"`     // "awx" or "aap"
-	Role     string `json:"role"`     // "source" or "destination"
+	Role     string `json:"role"`     // "source", "destination" or "auditor"
 	Scheme   string `json:"scheme"`   // "http" or "https"
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Token       string     `json:"token,omitempty"`         // OAuth2 token; used instead of basic auth when set
 	Insecure    bool       `json:"insecure"`                // skip TLS verification
 	CACert      string     `json:"ca_cert,omitempty"`       // PEM-encoded CA certificate for TLS verification
+	CACertFile  string     `json:"ca_cert_file,omitempty"`  // path to a PEM CA bundle, used when ca_cert is empty
+	ClientCert  string     `json:"client_cert,omitempty"`   // PEM client certificate, or a path to one, for mutual TLS
+	ClientKey   string     `json:"client_key,omitempty"`    // PEM private key of client_cert, or a path to one
+	Timeout     time.Duration `json:"timeout,omitempty"`    // per-request HTTP timeout, a duration string such as "45s" in JSON; 0 uses the client default (30s)
+	MaxRetries  int        `json:"max_retries,omitempty"`   // retries for transient errors; 0 uses the default (3), negative disables
+	RateLimit   float64    `json:"rate_limit,omitempty"`    // max requests per second; 0 is unlimited
+	PageConcurrency int    `json:"page_concurrency,omitempty"` // pages of a list fetched in parallel; 0 or 1 is serial
+	ImportConcurrency int  `json:"import_concurrency,omitempty"` // hosts created in parallel when migrating here; 0 or 1 is serial
 	Version     string     `json:"version,omitempty"`       // detected platform version, e.g. "23.4.0" or "4.7.8"
+	GatewayVersion string  `json:"gateway_version,omitempty"` // detected AAP gateway version (2.5+), e.g. "2.5.20250115"
 	APIPrefix   string     `json:"api_prefix,omitempty"`    // detected API prefix, e.g. "/api/v2/" or "/api/controller/v2/"
 	PingStatus  string     `json:"ping_status"`             // "unknown", "ok", "error"
 	PingError   string     `json:"ping_error,omitempty"`
@@ -30,9 +44,21 @@ type Connection struct {
 	LastChecked *time.Time `json:"last_checked,omitempty"`
 }
 
-// BaseURL returns the full base URL for this connection.
+// BaseURL returns the full base URL for this connection. IPv6 literals are
+// bracketed, and a port already given in Host (e.g. "[fe80::1]:8443" or
+// "awx:8080") takes precedence over Port.
 func (c *Connection) BaseURL() string {
-	return fmt.Sprintf("%s://%s:%d", c.Scheme, c.Host, c.Port)
+	host, port := c.Host, strconv.Itoa(c.Port)
+	if h, p, err := net.SplitHostPort(c.Host); err == nil {
+		host, port = h, p
+	} else {
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	}
+	// A zone (fe80::1%eth0) must be escaped as %25 in a URL.
+	if i := strings.Index(host, "%"); i >= 0 && !strings.HasPrefix(host[i:], "%25") {
+		host = host[:i] + "%25" + host[i+1:]
+	}
+	return fmt.Sprintf("%s://%s", c.Scheme, net.JoinHostPort(host, port))
 }
 
 // MaskedPassword returns a mask if password is set, empty string otherwise.
@@ -43,6 +69,95 @@ func (c *Connection) MaskedPassword() string {
 	return ""
 }
 
+// MaskedToken returns a mask if a token is set, empty string otherwise.
+func (c *Connection) MaskedToken() string {
+	if c.Token != "" {
+		return "••••••••"
+	}
+	return ""
+}
+
+// MaskedClientKey returns a mask if the client key is given inline, and the
+// key's path or an empty string otherwise.
+func (c *Connection) MaskedClientKey() string {
+	if strings.Contains(c.ClientKey, "-----BEGIN") {
+		return "••••••••"
+	}
+	return c.ClientKey
+}
+
+// MarshalJSON writes the timeout as a duration string such as "45s".
+func (c Connection) MarshalJSON() ([]byte, error) {
+	type plain Connection
+	v := struct {
+		plain
+		Timeout string `json:"timeout,omitempty"`
+	}{plain: plain(c)}
+	if c.Timeout != 0 {
+		v.Timeout = c.Timeout.String()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON reads connections written by MarshalJSON. A missing timeout
+// keeps the current value.
+func (c *Connection) UnmarshalJSON(data []byte) error {
+	type plain Connection
+	v := struct {
+		*plain
+		Timeout string `json:"timeout,omitempty"`
+	}{plain: (*plain)(c)}
+	if c.Timeout != 0 {
+		v.Timeout = c.Timeout.String()
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	c.Timeout = 0
+	if v.Timeout != "" {
+		timeout, err := time.ParseDuration(v.Timeout)
+		if err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		c.Timeout = timeout
+	}
+	return nil
+}
+
+// HasCredentials reports whether the connection has a token or a username and password.
+func (c *Connection) HasCredentials() bool {
+	return c.Token != "" || (c.Username != "" && c.Password != "")
+}
+
+// RoleAuditor marks a connection that may be browsed and diffed but never
+// written to by cleanup, populate or migrations.
+const RoleAuditor = "auditor"
+
+// ReadOnly reports whether the connection is registered for inspection only.
+func (c *Connection) ReadOnly() bool {
+	return c.Role == RoleAuditor
+}
+
+// Validate checks the fields that select how the connection is reached and
+// used, returning an error naming the first invalid one.
+func (c *Connection) Validate() error {
+	switch {
+	case c.Host == "":
+		return fmt.Errorf("host is required")
+	case c.Type != "awx" && c.Type != "aap":
+		return fmt.Errorf("type must be \"awx\" or \"aap\", got %q", c.Type)
+	case c.Scheme != "http" && c.Scheme != "https":
+		return fmt.Errorf("scheme must be \"http\" or \"https\", got %q", c.Scheme)
+	case c.Role != "source" && c.Role != "destination" && c.Role != RoleAuditor:
+		return fmt.Errorf("role must be \"source\", \"destination\" or \"auditor\", got %q", c.Role)
+	case c.Port < 1 || c.Port > 65535:
+		return fmt.Errorf("port must be between 1 and 65535, got %d", c.Port)
+	case (c.ClientCert == "") != (c.ClientKey == ""):
+		return fmt.Errorf("client_cert and client_key must be set together")
+	}
+	return nil
+}
+
 // ConnectionStore is an in-memory thread-safe store for connections.
 type ConnectionStore struct {
 	mu    sync.RWMutex
@@ -92,6 +207,41 @@ func (s *ConnectionStore) SetVersion(id, version, apiPrefix string) {
 	conn.APIPrefix = apiPrefix
 }
 
+// SetAPIPrefix updates the detected API prefix of a connection.
+func (s *ConnectionStore) SetAPIPrefix(id, apiPrefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, ok := s.conns[id]
+	if !ok {
+		return
+	}
+	conn.APIPrefix = apiPrefix
+}
+
+// Versions returns the detected version, gateway version and API prefix of
+// a connection. Discovery updates them while the connection is in use, so
+// they are read under the store's lock.
+func (s *ConnectionStore) Versions(id string) (version, gatewayVersion, apiPrefix string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	conn, ok := s.conns[id]
+	if !ok {
+		return "", "", ""
+	}
+	return conn.Version, conn.GatewayVersion, conn.APIPrefix
+}
+
+// SetGatewayVersion updates the detected AAP gateway version of a connection.
+func (s *ConnectionStore) SetGatewayVersion(id, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, ok := s.conns[id]
+	if !ok {
+		return
+	}
+	conn.GatewayVersion = version
+}
+
 // Get returns a connection by ID, or nil if not found.
 func (s *ConnectionStore) Get(id string) *Connection {
 	s.mu.RLock()
@@ -1,8 +1,11 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestBaseURL(t *testing.T) {
@@ -14,6 +17,12 @@ func TestBaseURL(t *testing.T) {
 		{"https default", Connection{Scheme: "https", Host: "aap.lab.local", Port: 443}, "https://aap.lab.local:443"},
 		{"http custom port", Connection{Scheme: "http", Host: "awx.lab.local", Port: 32000}, "http://awx.lab.local:32000"},
 		{"localhost", Connection{Scheme: "http", Host: "localhost", Port: 80}, "http://localhost:80"},
+		{"IPv4", Connection{Scheme: "https", Host: "192.0.2.10", Port: 8443}, "https://192.0.2.10:8443"},
+		{"bare IPv6", Connection{Scheme: "https", Host: "fe80::1", Port: 443}, "https://[fe80::1]:443"},
+		{"bracketed IPv6", Connection{Scheme: "https", Host: "[2001:db8::10]", Port: 443}, "https://[2001:db8::10]:443"},
+		{"bracketed IPv6 with port", Connection{Scheme: "https", Host: "[2001:db8::10]:8443", Port: 443}, "https://[2001:db8::10]:8443"},
+		{"IPv6 with zone", Connection{Scheme: "http", Host: "fe80::1%eth0", Port: 80}, "http://[fe80::1%25eth0]:80"},
+		{"hostname with port", Connection{Scheme: "http", Host: "awx.lab.local:8080", Port: 80}, "http://awx.lab.local:8080"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -25,6 +34,45 @@ func TestBaseURL(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	valid := Connection{Type: "aap", Role: "destination", Scheme: "https", Host: "aap.lab.local", Port: 443}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() on a valid connection = %v", err)
+	}
+	auditor := valid
+	auditor.Role = RoleAuditor
+	if err := auditor.Validate(); err != nil {
+		t.Fatalf("Validate() on an auditor connection = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Connection)
+		field  string
+	}{
+		{"missing host", func(c *Connection) { c.Host = "" }, "host"},
+		{"bad type", func(c *Connection) { c.Type = "oops" }, "type"},
+		{"bad scheme", func(c *Connection) { c.Scheme = "ftp" }, "scheme"},
+		{"bad role", func(c *Connection) { c.Role = "both" }, "role"},
+		{"port zero", func(c *Connection) { c.Port = 0 }, "port"},
+		{"port too high", func(c *Connection) { c.Port = 65536 }, "port"},
+		{"client cert without key", func(c *Connection) { c.ClientCert = "/etc/pki/client.pem" }, "client_cert"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid
+			tc.modify(&c)
+			err := c.Validate()
+			if err == nil {
+				t.Fatal("Validate() = nil, want error")
+			}
+			if !strings.HasPrefix(err.Error(), tc.field+" ") {
+				t.Errorf("Validate() = %q, want a %s error", err, tc.field)
+			}
+		})
+	}
+}
+
 func TestMaskedPassword(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -45,6 +93,31 @@ func TestMaskedPassword(t *testing.T) {
 	}
 }
 
+func TestConnection_TimeoutJSON(t *testing.T) {
+	data, err := json.Marshal(Connection{Name: "awx", Timeout: 45 * time.Second})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if !strings.Contains(string(data), `"timeout":"45s"`) || !strings.Contains(string(data), `"name":"awx"`) {
+		t.Errorf("marshalled %s, want timeout 45s and the other fields", data)
+	}
+
+	var c Connection
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if c.Timeout != 45*time.Second || c.Name != "awx" {
+		t.Errorf("round trip = %+v, want awx with 45s timeout", c)
+	}
+
+	if data, _ := json.Marshal(Connection{}); strings.Contains(string(data), "timeout") {
+		t.Errorf("zero timeout written: %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"timeout":"soon"}`), &c); err == nil {
+		t.Error("invalid timeout accepted")
+	}
+}
+
 func TestConnectionStore_CRUD(t *testing.T) {
 	store := NewConnectionStore()
 
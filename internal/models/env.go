@@ -0,0 +1,23 @@
+package models
+
+import (
+	"log"
+	"os"
+	"regexp"
+)
+
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv replaces ${VAR} references with environment values. Unset
+// variables expand to "" with a warning. Unlike os.ExpandEnv it leaves a
+// bare "$" alone, since passwords and secrets often contain one.
+func ExpandEnv(s string) string {
+	return envRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envRef.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			log.Printf("environment variable %s is not set", name)
+		}
+		return v
+	})
+}
@@ -0,0 +1,10 @@
+package models
+
+import "testing"
+
+func TestExpandEnv_KeepsBareDollar(t *testing.T) {
+	t.Setenv("WB_TEST_USER", "alice")
+	if got := ExpandEnv("pa$$word-${WB_TEST_USER}-$HOME"); got != "pa$$word-alice-$HOME" {
+		t.Errorf("ExpandEnv = %q", got)
+	}
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,59 +21,209 @@ type Job struct {
 	FinishedAt   *time.Time `json:"finished_at,omitempty"`
 	Error        string    `json:"error,omitempty"`
 	Output       []string  `json:"output"`
+	Dropped      int       `json:"dropped,omitempty"` // lines removed from the front of Output, see AppendLog
+	Total        int       `json:"total"`     // units of work, 0 if unknown
+	Completed    int       `json:"completed"` // units of work done so far
+	entries      []JobLogEntry // structured view of Output, see LogEntries
+	others       []string      // connections the job uses besides ConnectionID
+	structured   bool          // whether entries are recorded
 	mu           sync.Mutex
 	ctx          context.Context
 	cancelFn     context.CancelFunc
+	persist      func(j *Job, immediate bool) // set by a file-backed store
+	onFinish     func(JobResult)              // set by JobStore.OnFinish
+	redactor     *Redactor                    // masks secrets in AppendLog
+	maxLines     int                          // cap on len(Output); 0 keeps every line
 }
 
-// AppendLog adds a log line to the job output.
+// DefaultMaxLogLines is how many log lines a job keeps unless
+// JobStore.SetMaxLogLines says otherwise; MinMaxLogLines is the smallest cap
+// it accepts.
+const (
+	DefaultMaxLogLines = 50000
+	MinMaxLogLines     = 100
+)
+
+// JobResult summarises a job that has completed or failed.
+type JobResult struct {
+	ID           string    `json:"job_id"`
+	Type         string    `json:"type"`
+	ConnectionID string    `json:"connection_id"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	Duration     float64   `json:"duration_seconds"`
+}
+
+// AppendLog adds a log line to the job output, with secret values masked.
+// Once the output holds more lines than the store's cap, the oldest lines
+// are dropped down to 90% of the cap and the first remaining line is
+// replaced by a "[log truncated]" marker. Offsets still count every line
+// ever appended: Output[i] is line Dropped+i.
 func (j *Job) AppendLog(line string) {
+	line = j.redactor.Redact(line)
 	j.mu.Lock()
-	defer j.mu.Unlock()
+	if j.structured {
+		j.appendEntry(line)
+	}
 	j.Output = append(j.Output, line)
+	if j.maxLines > 0 && len(j.Output) > j.maxLines {
+		j.truncate(j.maxLines - j.maxLines/10)
+	}
+	j.mu.Unlock()
+	j.changed(false)
 }
 
-// LogsSince returns log lines starting from the given index.
-func (j *Job) LogsSince(offset int) []string {
+// truncate shortens the output to keep lines: the newest keep-1 lines,
+// after the truncation marker, which takes the place of the newest line
+// dropped so the offsets of the lines kept do not change. Lines are moved
+// within the existing slices, and only once every tenth of the cap.
+// Callers hold j.mu.
+func (j *Job) truncate(keep int) {
+	n := len(j.Output) - keep
+	j.Dropped += n
+	marker := fmt.Sprintf("[log truncated: %d earlier lines dropped]", j.Dropped+1)
+	copy(j.Output[1:], j.Output[n+1:])
+	j.Output = j.Output[:keep]
+	j.Output[0] = marker
+	if len(j.entries) > n {
+		e := parseLogLine(marker, j.entries[n].Phase)
+		e.Line = j.Dropped
+		copy(j.entries[1:], j.entries[n+1:])
+		j.entries = j.entries[:keep]
+		j.entries[0] = e
+	}
+}
+
+// appendEntry records the structured entry for the next output line.
+// Callers hold j.mu.
+func (j *Job) appendEntry(line string) {
+	var phase string
+	if n := len(j.entries); n > 0 {
+		phase = j.entries[n-1].Phase
+	}
+	e := parseLogLine(line, phase)
+	e.Line = j.Dropped + len(j.entries)
+	j.entries = append(j.entries, e)
+}
+
+// LogEntries returns the job output as structured entries, with a level
+// derived from the WARNING/FAIL/ERROR prefixes operations already use. It
+// is empty unless the store records them, see JobStore.SetStructuredLogs.
+func (j *Job) LogEntries() []JobLogEntry {
 	j.mu.Lock()
 	defer j.mu.Unlock()
-	if offset >= len(j.Output) {
-		return nil
+	entries := make([]JobLogEntry, len(j.entries))
+	copy(entries, j.entries)
+	return entries
+}
+
+// SetProgress records how many of the job's units of work are done.
+func (j *Job) SetProgress(completed, total int) {
+	j.mu.Lock()
+	j.Completed = completed
+	j.Total = total
+	j.mu.Unlock()
+	j.changed(false)
+}
+
+// changed notifies the store's persister, if any, that the job was modified.
+// Status changes are written immediately; log appends may be batched.
+func (j *Job) changed(immediate bool) {
+	if j.persist != nil {
+		j.persist(j, immediate)
 	}
-	lines := make([]string, len(j.Output)-offset)
-	copy(lines, j.Output[offset:])
+}
+
+// LogsSince returns log lines starting from the given offset, the number
+// of lines already read. Lines that were truncated away are skipped: the
+// result then starts with the truncation marker.
+func (j *Job) LogsSince(offset int) []string {
+	lines, _ := j.LogsFrom(offset)
 	return lines
 }
 
+// LogsFrom is LogsSince that also returns the offset to continue from, which
+// is offset+len(lines) unless lines were truncated away in between.
+func (j *Job) LogsFrom(offset int) ([]string, int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	next := j.Dropped + len(j.Output)
+	start := offset - j.Dropped
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(j.Output) {
+		return nil, next
+	}
+	lines := make([]string, len(j.Output)-start)
+	copy(lines, j.Output[start:])
+	return lines, next
+}
+
 // Complete marks the job as completed.
 func (j *Job) Complete() {
 	j.mu.Lock()
-	defer j.mu.Unlock()
 	j.Status = "completed"
 	now := time.Now()
 	j.FinishedAt = &now
+	result := j.result()
+	j.mu.Unlock()
+	j.changed(true)
+	j.finished(result)
 }
 
-// Fail marks the job as failed with an error message.
+// Fail marks the job as failed with an error message, with secret values
+// masked as in AppendLog.
 func (j *Job) Fail(err string) {
+	err = j.redactor.Redact(err)
 	j.mu.Lock()
-	defer j.mu.Unlock()
 	j.Status = "failed"
 	j.Error = err
 	now := time.Now()
 	j.FinishedAt = &now
+	result := j.result()
+	j.mu.Unlock()
+	j.changed(true)
+	j.finished(result)
+}
+
+// result summarises the job. Callers hold j.mu.
+func (j *Job) result() JobResult {
+	r := JobResult{
+		ID:           j.ID,
+		Type:         j.Type,
+		ConnectionID: j.ConnectionID,
+		Status:       j.Status,
+		Error:        j.Error,
+		StartedAt:    j.StartedAt,
+	}
+	if j.FinishedAt != nil {
+		r.FinishedAt = *j.FinishedAt
+		r.Duration = j.FinishedAt.Sub(j.StartedAt).Seconds()
+	}
+	return r
+}
+
+// finished passes the result to the store's OnFinish hook, if any.
+func (j *Job) finished(r JobResult) {
+	if j.onFinish != nil {
+		j.onFinish(r)
+	}
 }
 
 // Cancel marks the job as cancelled and triggers the cancellation context.
 func (j *Job) Cancel() {
 	j.mu.Lock()
-	defer j.mu.Unlock()
 	if j.cancelFn != nil {
 		j.cancelFn()
 	}
 	j.Status = "cancelled"
 	now := time.Now()
 	j.FinishedAt = &now
+	j.mu.Unlock()
+	j.changed(true)
 }
 
 // Context returns the job's cancellation context.
@@ -83,19 +236,89 @@ func (j *Job) IsCancelled() bool {
 	return j.ctx.Err() != nil
 }
 
-// JobStore is an in-memory thread-safe store for jobs.
+// Errors returned by JobStore.Delete.
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is still running")
+)
+
+// JobStore is a thread-safe store for jobs. Jobs are kept in memory and,
+// when created with NewPersistentJobStore, also written to disk.
 type JobStore struct {
-	mu   sync.RWMutex
-	jobs map[string]*Job
+	mu         sync.RWMutex
+	jobs       map[string]*Job
+	persister  *jobPersister
+	onFinish   func(JobResult)
+	redactor   *Redactor
+	maxLines   int
+	structured bool
+	workers    sync.WaitGroup // goroutines started with Go
 }
 
 // NewJobStore creates an empty job store.
 func NewJobStore() *JobStore {
-	return &JobStore{jobs: make(map[string]*Job)}
+	return &JobStore{jobs: make(map[string]*Job), redactor: NewRedactor(nil), maxLines: DefaultMaxLogLines}
+}
+
+// SetSecretKeys masks values of keys in the logs of jobs created afterwards,
+// in addition to DefaultSecretKeys.
+func (s *JobStore) SetSecretKeys(keys []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.redactor = NewRedactor(keys)
+}
+
+// SetMaxLogLines caps the log of jobs created afterwards at n lines, see
+// AppendLog. 0 keeps every line; other values below MinMaxLogLines are
+// raised to it.
+func (s *JobStore) SetMaxLogLines(n int) {
+	if n > 0 && n < MinMaxLogLines {
+		n = MinMaxLogLines
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxLines = n
+}
+
+// SetStructuredLogs turns recording of structured log entries, see
+// LogEntries, on or off. It applies to every job in the store; entries of
+// jobs already there, e.g. reloaded from disk, are rebuilt from their output.
+func (s *JobStore) SetStructuredLogs(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.structured = on
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		j.structured = on
+		j.entries = nil
+		if on {
+			for _, line := range j.Output {
+				j.appendEntry(line)
+			}
+		}
+		j.mu.Unlock()
+	}
+}
+
+// StructuredLogs reports whether the store records structured log entries.
+func (s *JobStore) StructuredLogs() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.structured
 }
 
-// Create adds a new job, assigning it a UUID.
-func (s *JobStore) Create(jobType, connectionID string) *Job {
+// OnFinish registers fn to be called whenever a job created afterwards
+// completes or fails. fn runs on the goroutine that finished the job.
+func (s *JobStore) OnFinish(fn func(JobResult)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onFinish = fn
+}
+
+// Create adds a new job, assigning it a UUID. connectionID is the connection
+// the job is listed under; others are further connections it uses, e.g. the
+// source of a migration, so that HasRunning reports the job for them too.
+func (s *JobStore) Create(jobType, connectionID string, others ...string) *Job {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -106,10 +329,19 @@ func (s *JobStore) Create(jobType, connectionID string) *Job {
 		Status:       "running",
 		StartedAt:    time.Now(),
 		Output:       []string{},
+		others:       others,
 		ctx:          ctx,
 		cancelFn:     cancel,
+		onFinish:     s.onFinish,
+		redactor:     s.redactor,
+		maxLines:     s.maxLines,
+		structured:   s.structured,
 	}
 	s.jobs[j.ID] = j
+	if s.persister != nil {
+		j.persist = s.persister.changed
+		s.persister.changed(j, true)
+	}
 	return j
 }
 
@@ -120,6 +352,32 @@ func (s *JobStore) Get(id string) *Job {
 	return s.jobs[id]
 }
 
+// HasRunning reports whether any job using the given connection is still
+// running.
+func (s *JobStore) HasRunning(connectionID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, j := range s.jobs {
+		if j.uses(connectionID) && j.running() {
+			return true
+		}
+	}
+	return false
+}
+
+// uses reports whether the job was created for connectionID or uses it.
+func (j *Job) uses(connectionID string) bool {
+	if j.ConnectionID == connectionID {
+		return true
+	}
+	for _, id := range j.others {
+		if id == connectionID {
+			return true
+		}
+	}
+	return false
+}
+
 // List returns all jobs, most recent first.
 func (s *JobStore) List() []*Job {
 	s.mu.RLock()
@@ -128,13 +386,123 @@ func (s *JobStore) List() []*Job {
 	for _, j := range s.jobs {
 		result = append(result, j)
 	}
-	// Sort by started_at descending
-	for i := 0; i < len(result); i++ {
-		for j := i + 1; j < len(result); j++ {
-			if result[j].StartedAt.After(result[i].StartedAt) {
-				result[i], result[j] = result[j], result[i]
-			}
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].StartedAt.After(result[b].StartedAt)
+	})
+	return result
+}
+
+// Page returns up to limit jobs starting at offset, most recent first, along
+// with the total number of jobs. A limit of 0 returns all remaining jobs.
+func (s *JobStore) Page(limit, offset int) ([]*Job, int) {
+	all := s.List()
+	total := len(all)
+	if offset >= total {
+		return []*Job{}, total
+	}
+	page := all[offset:]
+	if limit > 0 && limit < len(page) {
+		page = page[:limit]
+	}
+	return page, total
+}
+
+// Go runs fn, the work of a job, in a new goroutine that Wait waits for.
+func (s *JobStore) Go(fn func()) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		fn()
+	}()
+}
+
+// Wait blocks until every goroutine started with Go has returned, or ctx is
+// done, and reports whether they all returned.
+func (s *JobStore) Wait(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// CancelRunning cancels every running job, e.g. when the workbench shuts
+// down, and returns how many were cancelled.
+func (s *JobStore) CancelRunning() int {
+	s.mu.RLock()
+	var running []*Job
+	for _, j := range s.jobs {
+		if j.running() {
+			running = append(running, j)
 		}
 	}
-	return result
+	s.mu.RUnlock()
+	for _, j := range running {
+		j.AppendLog("CANCELLED: workbench is shutting down")
+		j.Cancel()
+	}
+	return len(running)
+}
+
+// Delete removes a finished job. Running jobs are kept and ErrJobRunning is
+// returned.
+func (s *JobStore) Delete(id string) error {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrJobNotFound
+	}
+	if j.running() {
+		s.mu.Unlock()
+		return ErrJobRunning
+	}
+	delete(s.jobs, id)
+	s.mu.Unlock()
+	s.removeFiles([]string{id})
+	return nil
+}
+
+// Prune removes every finished job that finished more than age ago and
+// returns the IDs removed.
+func (s *JobStore) Prune(age time.Duration) []string {
+	cutoff := time.Now().Add(-age)
+	s.mu.Lock()
+	removed := []string{}
+	for id, j := range s.jobs {
+		j.mu.Lock()
+		old := j.Status != "running" && j.FinishedAt != nil && j.FinishedAt.Before(cutoff)
+		j.mu.Unlock()
+		if old {
+			delete(s.jobs, id)
+			removed = append(removed, id)
+		}
+	}
+	s.mu.Unlock()
+	s.removeFiles(removed)
+	return removed
+}
+
+// removeFiles deletes the files of jobs already dropped from the store.
+// Callers must not hold s.mu, so other requests are not held up by disk I/O.
+func (s *JobStore) removeFiles(ids []string) {
+	if s.persister == nil {
+		return
+	}
+	for _, id := range ids {
+		s.persister.remove(id)
+	}
+}
+
+// running reports whether the job is still running.
+func (j *Job) running() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.Status == "running"
 }
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jobPersistDelay is how long log appends are batched before a job is
+// rewritten to disk.
+const jobPersistDelay = 500 * time.Millisecond
+
+// NewPersistentJobStore creates a job store that saves every job as
+// <dir>/<id>.json and reloads existing jobs from dir. Jobs that were still
+// running when the previous process exited are marked as failed.
+func NewPersistentJobStore(dir string) (*JobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating job directory: %w", err)
+	}
+	s := NewJobStore()
+	s.persister = &jobPersister{
+		dir:     dir,
+		delay:   jobPersistDelay,
+		pending: make(map[string]*pendingSave),
+		removed: make(map[string]bool),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading job directory: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading job %s: %w", e.Name(), err)
+		}
+		j := &Job{}
+		if err := json.Unmarshal(data, j); err != nil {
+			log.Printf("Skipping unreadable job file %s: %v", e.Name(), err)
+			continue
+		}
+		if j.Output == nil {
+			j.Output = []string{}
+		}
+		j.ctx, j.cancelFn = context.WithCancel(context.Background())
+		j.persist = s.persister.changed
+		j.redactor = s.redactor
+		j.maxLines = s.maxLines
+		s.jobs[j.ID] = j
+		if j.Status == "running" {
+			j.AppendLog("ERROR: workbench restarted while job was running")
+			j.Fail("interrupted by restart")
+		}
+	}
+	return s, nil
+}
+
+// Flush writes any jobs with batched, not yet saved changes to disk.
+func (s *JobStore) Flush() {
+	if s.persister != nil {
+		s.persister.flush()
+	}
+}
+
+// jobPersister writes jobs as JSON files, coalescing bursts of log appends
+// into a single write per job.
+type jobPersister struct {
+	dir     string
+	delay   time.Duration
+	mu      sync.Mutex
+	pending map[string]*pendingSave // job ID → scheduled write
+	writeMu sync.Mutex              // serializes file writes
+	removed map[string]bool         // IDs of deleted jobs, never written again; guarded by writeMu
+}
+
+type pendingSave struct {
+	job   *Job
+	timer *time.Timer
+}
+
+// changed is installed as Job.persist.
+func (p *jobPersister) changed(j *Job, immediate bool) {
+	if immediate {
+		p.save(j)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.pending[j.ID]; ok {
+		return
+	}
+	p.pending[j.ID] = &pendingSave{job: j, timer: time.AfterFunc(p.delay, func() { p.save(j) })}
+}
+
+// flush saves every job with a pending write.
+func (p *jobPersister) flush() {
+	p.mu.Lock()
+	jobs := make([]*Job, 0, len(p.pending))
+	for _, ps := range p.pending {
+		jobs = append(jobs, ps.job)
+	}
+	p.mu.Unlock()
+	for _, j := range jobs {
+		p.save(j)
+	}
+}
+
+// remove cancels any pending write for a job and deletes its file. The job
+// is remembered as deleted, so a worker that still logs to it, e.g. after it
+// was cancelled, does not write the file again.
+func (p *jobPersister) remove(id string) {
+	p.mu.Lock()
+	if ps, ok := p.pending[id]; ok {
+		ps.timer.Stop()
+		delete(p.pending, id)
+	}
+	p.mu.Unlock()
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	p.removed[id] = true
+	if err := os.Remove(filepath.Join(p.dir, id+".json")); err != nil && !os.IsNotExist(err) {
+		log.Printf("Removing job %s: %v", id, err)
+	}
+}
+
+// save writes the job to disk, replacing any earlier version atomically.
+func (p *jobPersister) save(j *Job) {
+	p.mu.Lock()
+	if ps, ok := p.pending[j.ID]; ok {
+		ps.timer.Stop()
+		delete(p.pending, j.ID)
+	}
+	p.mu.Unlock()
+
+	// Marshal under writeMu so a slower save of an older snapshot cannot
+	// overwrite a newer one.
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	if p.removed[j.ID] {
+		return
+	}
+	j.mu.Lock()
+	data, err := json.MarshalIndent(j, "", "  ")
+	j.mu.Unlock()
+	if err != nil {
+		log.Printf("Saving job %s: %v", j.ID, err)
+		return
+	}
+	path := filepath.Join(p.dir, j.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Saving job %s: %v", j.ID, err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		log.Printf("Saving job %s: %v", j.ID, err)
+	}
+}
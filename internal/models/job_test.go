@@ -0,0 +1,403 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPersistentJobStore_RestoresJobs(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+
+	done := store.Create("awx-export", "conn-1")
+	done.AppendLog("Exporting organizations...")
+	done.AppendLog("  3 organizations")
+	done.Complete()
+
+	failed := store.Create("aap-populate", "conn-2")
+	failed.AppendLog("=== Organizations ===")
+	failed.Fail("boom")
+
+	running := store.Create("migration-run", "conn-2")
+	running.AppendLog("=== Starting migration ===")
+	store.Flush()
+
+	// Simulate a restart
+	restored, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore (reload): %v", err)
+	}
+	if got := len(restored.List()); got != 3 {
+		t.Fatalf("restored %d jobs, want 3", got)
+	}
+
+	j := restored.Get(done.ID)
+	if j == nil || j.Status != "completed" || j.FinishedAt == nil {
+		t.Fatalf("completed job not restored correctly: %+v", j)
+	}
+	if len(j.Output) != 2 || j.Output[1] != "  3 organizations" {
+		t.Errorf("Output = %q", j.Output)
+	}
+
+	j = restored.Get(failed.ID)
+	if j == nil || j.Status != "failed" || j.Error != "boom" {
+		t.Fatalf("failed job not restored correctly: %+v", j)
+	}
+
+	j = restored.Get(running.ID)
+	if j == nil || j.Status != "failed" || j.Error != "interrupted by restart" {
+		t.Fatalf("running job should be marked interrupted: %+v", j)
+	}
+	if j.Output[0] != "=== Starting migration ===" {
+		t.Errorf("Output = %q", j.Output)
+	}
+	if j.IsCancelled() {
+		t.Error("restored job should have a live context")
+	}
+}
+
+func TestPersistentJobStore_DebouncesLogWrites(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+	store.persister.delay = time.Hour
+
+	j := store.Create("awx-export", "conn-1")
+	for i := 0; i < 100; i++ {
+		j.AppendLog("line")
+	}
+
+	// Only the write from Create has reached disk so far.
+	if got := len(savedJob(t, dir, j.ID).Output); got != 0 {
+		t.Errorf("Output on disk before flush has %d lines, want 0", got)
+	}
+
+	store.Flush()
+	if got := len(savedJob(t, dir, j.ID).Output); got != 100 {
+		t.Errorf("Output on disk after flush has %d lines, want 100", got)
+	}
+}
+
+// savedJob reads a job file written by a persistent store.
+func savedJob(t *testing.T, dir, id string) *Job {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		t.Fatalf("reading job file: %v", err)
+	}
+	var j Job
+	if err := json.Unmarshal(data, &j); err != nil {
+		t.Fatalf("parsing job file: %v", err)
+	}
+	return &j
+}
+
+func TestPersistentJobStore_SkipsCorruptFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+	if got := len(store.List()); got != 0 {
+		t.Errorf("loaded %d jobs, want 0", got)
+	}
+}
+
+func TestJob_SetProgressConcurrentWithAppendLog(t *testing.T) {
+	store, err := NewPersistentJobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+	job := store.Create("migration", "conn-1")
+
+	const n = 200
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= n; i++ {
+			job.SetProgress(i, n)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			job.AppendLog("line")
+		}
+	}()
+	wg.Wait()
+	store.Flush()
+
+	if got := len(job.LogsSince(0)); got != n {
+		t.Errorf("log lines = %d, want %d", got, n)
+	}
+	job.mu.Lock()
+	completed, total := job.Completed, job.Total
+	job.mu.Unlock()
+	if completed != n || total != n {
+		t.Errorf("progress = %d/%d, want %d/%d", completed, total, n, n)
+	}
+}
+
+func TestJobStore_HasRunningConcurrentWithComplete(t *testing.T) {
+	store := NewJobStore()
+	job := store.Create("awx-cleanup", "conn-1")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for store.HasRunning("conn-1") {
+		}
+	}()
+	job.Complete()
+	<-done
+	if store.HasRunning("conn-1") {
+		t.Error("HasRunning = true after the job completed")
+	}
+}
+
+func TestJobStore_Page(t *testing.T) {
+	store := NewJobStore()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		j := store.Create("awx-populate", "conn-1")
+		j.StartedAt = base.Add(time.Duration(i) * time.Minute)
+	}
+
+	all := store.List()
+	for i := 1; i < len(all); i++ {
+		if all[i].StartedAt.After(all[i-1].StartedAt) {
+			t.Fatalf("List not sorted most recent first at index %d", i)
+		}
+	}
+
+	tests := []struct {
+		limit, offset int
+		wantLen       int
+		wantFirst     int // minutes after base of the first job
+	}{
+		{limit: 10, offset: 0, wantLen: 10, wantFirst: 99},
+		{limit: 10, offset: 20, wantLen: 10, wantFirst: 79},
+		{limit: 30, offset: 90, wantLen: 10, wantFirst: 9},
+		{limit: 0, offset: 50, wantLen: 50, wantFirst: 49},
+		{limit: 10, offset: 100, wantLen: 0},
+		{limit: 10, offset: 500, wantLen: 0},
+	}
+	for _, tc := range tests {
+		page, total := store.Page(tc.limit, tc.offset)
+		if total != 100 {
+			t.Errorf("Page(%d, %d) total = %d, want 100", tc.limit, tc.offset, total)
+		}
+		if len(page) != tc.wantLen {
+			t.Errorf("Page(%d, %d) returned %d jobs, want %d", tc.limit, tc.offset, len(page), tc.wantLen)
+			continue
+		}
+		if tc.wantLen == 0 {
+			continue
+		}
+		if want := base.Add(time.Duration(tc.wantFirst) * time.Minute); !page[0].StartedAt.Equal(want) {
+			t.Errorf("Page(%d, %d) first job started %v, want %v", tc.limit, tc.offset, page[0].StartedAt, want)
+		}
+	}
+}
+
+func TestJobStore_DeleteRefusesRunning(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+	running := store.Create("migration-run", "conn-1")
+	done := store.Create("awx-export", "conn-1")
+	done.Complete()
+
+	if err := store.Delete(running.ID); err != ErrJobRunning {
+		t.Errorf("Delete(running) = %v, want ErrJobRunning", err)
+	}
+	if store.Get(running.ID) == nil {
+		t.Error("running job was removed")
+	}
+	if err := store.Delete(done.ID); err != nil {
+		t.Fatalf("Delete(completed) = %v", err)
+	}
+	if store.Get(done.ID) != nil {
+		t.Error("completed job still in store")
+	}
+	if _, err := os.Stat(filepath.Join(dir, done.ID+".json")); !os.IsNotExist(err) {
+		t.Errorf("job file still on disk: %v", err)
+	}
+	if err := store.Delete(done.ID); err != ErrJobNotFound {
+		t.Errorf("second Delete = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestJobStore_DeletedJobIsNotWrittenAgain(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+	job := store.Create("migration-run", "conn-1")
+	job.Cancel()
+	if err := store.Delete(job.ID); err != nil {
+		t.Fatalf("Delete(cancelled) = %v", err)
+	}
+
+	// The worker has not noticed the cancellation yet and keeps logging.
+	job.AppendLog("  CREATED: Deploy")
+	job.Fail("stopped")
+	store.Flush()
+
+	if _, err := os.Stat(filepath.Join(dir, job.ID+".json")); !os.IsNotExist(err) {
+		t.Fatalf("deleted job was written again: %v", err)
+	}
+	restored, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore (reload): %v", err)
+	}
+	if restored.Get(job.ID) != nil {
+		t.Error("deleted job came back after a restart")
+	}
+}
+
+func TestJobStore_PruneRespectsAge(t *testing.T) {
+	store := NewJobStore()
+	finish := func(j *Job, ago time.Duration) {
+		j.Complete()
+		at := time.Now().Add(-ago)
+		j.FinishedAt = &at
+	}
+	old := store.Create("awx-export", "conn-1")
+	finish(old, 48*time.Hour)
+	oldFailed := store.Create("awx-export", "conn-1")
+	oldFailed.Fail("boom")
+	at := time.Now().Add(-30 * time.Hour)
+	oldFailed.FinishedAt = &at
+	recent := store.Create("awx-export", "conn-1")
+	finish(recent, time.Hour)
+	running := store.Create("migration-run", "conn-1")
+
+	removed := store.Prune(24 * time.Hour)
+	if len(removed) != 2 {
+		t.Fatalf("Prune removed %v, want 2 jobs", removed)
+	}
+	if store.Get(old.ID) != nil || store.Get(oldFailed.ID) != nil {
+		t.Error("jobs older than the cutoff were kept")
+	}
+	if store.Get(recent.ID) == nil || store.Get(running.ID) == nil {
+		t.Error("recent or running job was pruned")
+	}
+}
+
+func TestJob_AppendLogTruncatesPastCap(t *testing.T) {
+	store := NewJobStore()
+	store.SetMaxLogLines(1) // raised to MinMaxLogLines
+	store.SetStructuredLogs(true)
+	job := store.Create("migration", "conn-1")
+
+	for i := 0; i < 60; i++ {
+		job.AppendLog(fmt.Sprintf("line %d", i))
+	}
+	lines, streamed := job.LogsFrom(0)
+	if len(lines) != 60 || streamed != 60 {
+		t.Fatalf("before cap: %d lines, next %d", len(lines), streamed)
+	}
+
+	// The 101st line trims the log to 90 lines: the marker, in place of
+	// line 11, and lines 12 to 100.
+	for i := 60; i < 101; i++ {
+		job.AppendLog(fmt.Sprintf("line %d", i))
+	}
+	lines, next := job.LogsFrom(0)
+	if next != 101 || len(lines) != 90 {
+		t.Fatalf("after cap: %d lines, next %d; want 90, 101", len(lines), next)
+	}
+	if lines[0] != "[log truncated: 12 earlier lines dropped]" || lines[1] != "line 12" || lines[89] != "line 100" {
+		t.Errorf("lines = %q ... %q", lines[:2], lines[89])
+	}
+
+	// A reader that had read 5 lines, all since dropped, resumes at the
+	// marker; one that had read 60 gets exactly the lines it is missing.
+	if lines, next := job.LogsFrom(5); len(lines) != 90 || lines[0] != "[log truncated: 12 earlier lines dropped]" || next != 101 {
+		t.Errorf("LogsFrom(5) = %d lines from %q, next %d", len(lines), lines[0], next)
+	}
+	if lines, next := job.LogsFrom(streamed); len(lines) != 41 || lines[0] != "line 60" || next != 101 {
+		t.Errorf("LogsFrom(%d) = %d lines from %q, next %d", streamed, len(lines), lines[0], next)
+	}
+	if lines, next := job.LogsFrom(95); len(lines) != 6 || lines[0] != "line 95" || next != 101 {
+		t.Errorf("LogsFrom(95) = %q, %d", lines, next)
+	}
+	if lines, next := job.LogsFrom(101); lines != nil || next != 101 {
+		t.Errorf("LogsFrom(101) = %q, %d", lines, next)
+	}
+
+	entries := job.LogEntries()
+	if len(entries) != 90 || entries[0].Line != 11 || entries[89].Line != 100 || entries[89].Message != "line 100" {
+		t.Errorf("entries = %+v ... %+v", entries[0], entries[len(entries)-1])
+	}
+
+	// Lines are dropped in batches: the next 10 lines fit, the 11th trims
+	// the log again.
+	for i := 101; i < 111; i++ {
+		job.AppendLog(fmt.Sprintf("line %d", i))
+	}
+	if job.Dropped != 11 || len(job.Output) != 100 {
+		t.Errorf("Dropped = %d, %d lines; want 11, 100", job.Dropped, len(job.Output))
+	}
+	job.AppendLog("line 111")
+	if job.Dropped != 22 || len(job.Output) != 90 || job.Output[0] != "[log truncated: 23 earlier lines dropped]" {
+		t.Errorf("Dropped = %d, %d lines from %q; want 22, 90", job.Dropped, len(job.Output), job.Output[0])
+	}
+}
+
+func TestJob_AppendLogUncapped(t *testing.T) {
+	store := NewJobStore()
+	store.SetMaxLogLines(0)
+	job := store.Create("migration", "conn-1")
+	for i := 0; i < DefaultMaxLogLines+10; i++ {
+		job.AppendLog("line")
+	}
+	if lines, next := job.LogsFrom(0); len(lines) != DefaultMaxLogLines+10 || next != len(lines) {
+		t.Errorf("got %d lines, next %d; want %d", len(lines), next, DefaultMaxLogLines+10)
+	}
+}
+
+func TestPersistentJobStore_RestoresTruncatedLog(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+	store.SetMaxLogLines(MinMaxLogLines)
+	job := store.Create("migration", "conn-1")
+	for i := 0; i < 110; i++ {
+		job.AppendLog(fmt.Sprintf("line %d", i))
+	}
+	job.Complete()
+	store.Flush()
+
+	restored, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore (reload): %v", err)
+	}
+	restored.SetStructuredLogs(true)
+	got := restored.Get(job.ID)
+	if lines, next := got.LogsFrom(109); len(lines) != 1 || lines[0] != "line 109" || next != 110 {
+		t.Errorf("LogsFrom(109) = %q, %d", lines, next)
+	}
+	if entries := got.LogEntries(); len(entries) != 99 || entries[98].Line != 109 {
+		t.Errorf("restored %d entries, last %+v", len(entries), entries[len(entries)-1])
+	}
+}
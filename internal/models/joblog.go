@@ -0,0 +1,67 @@
+package models
+
+import "strings"
+
+// JobLogEntry is a structured view of one job log line.
+type JobLogEntry struct {
+	Line     int    `json:"line"`               // line offset, as in Job.LogsSince
+	Level    string `json:"level"`              // "info", "warn" or "error"
+	Phase    string `json:"phase,omitempty"`    // last "=== ... ===" header, e.g. "Importing projects"
+	Resource string `json:"resource,omitempty"` // resource name for per-resource lines
+	Message  string `json:"message"`
+}
+
+// levels maps the first word of a line, without a trailing colon, to a
+// level. Operations write both "FAIL: name" and "  FAIL name (id=5): ...".
+var levels = map[string]string{
+	"WARNING":   "warn",
+	"CANCELLED": "warn",
+	"FAIL":      "error",
+	"ERROR":     "error",
+}
+
+// resourceWords are the first words of per-resource lines, which are
+// followed by the resource name.
+var resourceWords = map[string]bool{
+	"CREATED": true, "UPDATED": true, "EXCLUDED": true, "FAIL": true, "SKIP": true,
+}
+
+// parseLogLine classifies a log line. phase is the current phase; if the
+// line is itself a phase header the returned entry carries the new phase.
+func parseLogLine(line, phase string) JobLogEntry {
+	msg := strings.TrimSpace(line)
+	if strings.HasPrefix(msg, "===") && strings.HasSuffix(msg, "===") && len(msg) > 6 {
+		phase = strings.TrimSpace(msg[3 : len(msg)-3])
+	}
+	e := JobLogEntry{Level: "info", Phase: phase, Message: line}
+	word, rest, _ := strings.Cut(msg, " ")
+	word = strings.TrimSuffix(word, ":")
+	if level, ok := levels[word]; ok {
+		e.Level = level
+	}
+	if resourceWords[word] {
+		e.Resource = resourceFromLine(rest)
+	}
+	return e
+}
+
+// resourceFromLine extracts the name from the rest of lines such as
+// "CREATED: Deploy (ID 5)", "SKIP (exists): Deploy", "FAIL: Deploy: 400"
+// or "FAIL Deploy (id=5): 400", after the first word.
+func resourceFromLine(rest string) string {
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "(") {
+		_, after, ok := strings.Cut(rest, ": ")
+		if !ok {
+			return ""
+		}
+		rest = after
+	}
+	if i := strings.Index(rest, " ("); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, ": "); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
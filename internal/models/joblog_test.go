@@ -0,0 +1,71 @@
+package models
+
+import "testing"
+
+func TestJob_LogEntriesClassifyPrefixes(t *testing.T) {
+	store := NewJobStore()
+	store.SetStructuredLogs(true)
+	j := store.Create("migration-run", "conn-1")
+	for _, line := range []string{
+		"=== Importing projects ===",
+		"  CREATED: Playbooks (ID 7)",
+		"  WARNING: project Playbooks sync: timeout",
+		"  FAIL: Roles: 400 Bad Request: {\"scm_url\":[\"required\"]}",
+		"  FAIL Deploy (id=5): 400 Bad Request",
+		"=== Importing inventories ===",
+		"  SKIP (exists): Prod",
+		"ERROR: preflight failed",
+	} {
+		j.AppendLog(line)
+	}
+
+	want := []JobLogEntry{
+		{Line: 0, Level: "info", Phase: "Importing projects"},
+		{Line: 1, Level: "info", Phase: "Importing projects", Resource: "Playbooks"},
+		{Line: 2, Level: "warn", Phase: "Importing projects"},
+		{Line: 3, Level: "error", Phase: "Importing projects", Resource: "Roles"},
+		{Line: 4, Level: "error", Phase: "Importing projects", Resource: "Deploy"},
+		{Line: 5, Level: "info", Phase: "Importing inventories"},
+		{Line: 6, Level: "info", Phase: "Importing inventories", Resource: "Prod"},
+		{Line: 7, Level: "error", Phase: "Importing inventories"},
+	}
+	got := j.LogEntries()
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		w.Message = j.Output[i]
+		if got[i] != w {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestPersistentJobStore_RebuildsLogEntries(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore: %v", err)
+	}
+	j := store.Create("aap-cleanup", "conn-1")
+	j.AppendLog("  WARNING: could not delete Demo")
+	j.Complete()
+
+	restored, err := NewPersistentJobStore(dir)
+	if err != nil {
+		t.Fatalf("NewPersistentJobStore (reload): %v", err)
+	}
+	restored.SetStructuredLogs(true)
+	entries := restored.Get(j.ID).LogEntries()
+	if len(entries) != 1 || entries[0].Level != "warn" {
+		t.Errorf("restored entries = %+v, want one warn entry", entries)
+	}
+}
+
+func TestJob_LogEntriesOffByDefault(t *testing.T) {
+	j := NewJobStore().Create("migration-run", "conn-1")
+	j.AppendLog("  FAIL: Roles: 400 Bad Request")
+	if entries := j.LogEntries(); len(entries) != 0 {
+		t.Errorf("entries = %+v, want none without SetStructuredLogs", entries)
+	}
+}
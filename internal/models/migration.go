@@ -5,7 +5,7 @@ type MigrationResource struct {
 	SourceID int    `json:"source_id"`
 	Name     string `json:"name"`
 	Type     string `json:"type"`
-	Action   string `json:"action"` // "create", "skip_exists", "skip_default", "skip_managed"
+	Action   string `json:"action"` // "create", "update", "skip_exists", "skip_excluded", "skip_default", "skip_managed"
 	DestID   int    `json:"dest_id,omitempty"`
 }
 
@@ -17,4 +17,35 @@ type MigrationPreview struct {
 	Warnings      []string                       `json:"warnings"`
 	HostCounts    map[string]int                 `json:"host_counts,omitempty"`  // inventory name → host count
 	GroupCounts   map[string]int                 `json:"group_counts,omitempty"` // inventory name → group count
+	Excluded      map[string]int                 `json:"excluded,omitempty"`     // resource type → count excluded as disabled or by name
+	Totals        map[string]int                 `json:"totals"`                 // resource type → count exported, less those excluded by name
+}
+
+// FieldDiff describes a single field whose value differs between source and destination.
+type FieldDiff struct {
+	Field       string      `json:"field"`
+	Source      interface{} `json:"source"`
+	Destination interface{} `json:"destination"`
+}
+
+// ResourceDiff holds the comparison of one named object across two connections.
+type ResourceDiff struct {
+	Type        string      `json:"type"`
+	Name        string      `json:"name"`
+	Status      string      `json:"status"` // "identical", "different", "only_in_source", "only_in_destination", "not_found"
+	Differences []FieldDiff `json:"differences"`
+}
+
+// NameDiff lists which names of one resource type exist on each side.
+type NameDiff struct {
+	OnlyInSource      []string `json:"only_in_source"`
+	OnlyInDestination []string `json:"only_in_destination"`
+	InBoth            []string `json:"in_both"`
+}
+
+// ConnectionDiff holds the name-level comparison of two connections, keyed by resource type.
+type ConnectionDiff struct {
+	SourceID      string              `json:"source_id"`
+	DestinationID string              `json:"destination_id"`
+	Types         map[string]NameDiff `json:"types"`
 }
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultSecretKeys are the keys whose values are masked in job logs.
+var DefaultSecretKeys = []string{"password", "token", "secret", "vault_password"}
+
+// secretMask replaces masked values.
+const secretMask = "••••"
+
+var bearerToken = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+
+// Redactor masks secret values in log lines: "key: value", "key=value" and
+// JSON "key": "value" pairs whose key is a secret key (optionally prefixed,
+// e.g. become_password), and bearer tokens.
+type Redactor struct {
+	pairs *regexp.Regexp
+}
+
+// NewRedactor returns a Redactor for DefaultSecretKeys plus extra.
+func NewRedactor(extra []string) *Redactor {
+	var keys []string
+	for _, k := range append(append([]string{}, DefaultSecretKeys...), extra...) {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, regexp.QuoteMeta(k))
+		}
+	}
+	return &Redactor{pairs: regexp.MustCompile(`(?i)\b((?:[a-z0-9]+_)*(?:` + strings.Join(keys, "|") +
+		`))\b(["']?\s*[:=]\s*)("[^"]*"|'[^']*'|[^\s,;&}\]]+)`)}
+}
+
+// Redact returns line with secret values masked.
+func (r *Redactor) Redact(line string) string {
+	if r == nil {
+		return line
+	}
+	line = r.pairs.ReplaceAllString(line, "${1}${2}"+secretMask)
+	return bearerToken.ReplaceAllString(line, "${1}"+secretMask)
+}
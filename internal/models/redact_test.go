@@ -0,0 +1,60 @@
+package models
+
+import "testing"
+
+func TestRedactor(t *testing.T) {
+	r := NewRedactor([]string{"ssh_key_data"})
+	tests := []struct{ in, want string }{
+		{"password: ansible123", "password: ••••"},
+		{"  become_password=ansible123 user=admin", "  become_password=•••• user=admin"},
+		{`POST failed: {"username": "admin", "password": "ansible 123"}`, `POST failed: {"username": "admin", "password": ••••}`},
+		{"inputs map[password:ansible123 username:admin]", "inputs map[password:•••• username:admin]"},
+		{"Vault_Password: s3cret, next", "Vault_Password: ••••, next"},
+		{"ssh_key_data: -----BEGIN", "ssh_key_data: ••••"},
+		{"Authorization: Bearer abc.def-123", "Authorization: Bearer ••••"},
+		// Not secrets: key words without a value, or as part of other words.
+		{"  Secrets: 3 credentials filled, 1 left empty", "  Secrets: 3 credentials filled, 1 left empty"},
+		{"  Credential Type: API Token (id=4)", "  Credential Type: API Token (id=4)"},
+		{"  CREATED: Machine (ID 5) [inputs set: password, username]", "  CREATED: Machine (ID 5) [inputs set: password, username]"},
+	}
+	for _, tc := range tests {
+		if got := r.Redact(tc.in); got != tc.want {
+			t.Errorf("Redact(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestJob_AppendLogRedacts(t *testing.T) {
+	store := NewJobStore()
+	store.SetStructuredLogs(true)
+	j := store.Create("awx-populate", "conn-1")
+	j.AppendLog("  Credential Machine: password: ansible123")
+	if got := j.Output[0]; got != "  Credential Machine: password: ••••" {
+		t.Errorf("Output[0] = %q", got)
+	}
+	if got := j.LogEntries()[0].Message; got != "  Credential Machine: password: ••••" {
+		t.Errorf("log entry = %q, want the redacted line", got)
+	}
+
+	store.SetSecretKeys([]string{"api_key"})
+	j = store.Create("awx-populate", "conn-1")
+	j.AppendLog("api_key=abc123 password=ansible123")
+	if got := j.Output[0]; got != "api_key=•••• password=••••" {
+		t.Errorf("Output[0] = %q", got)
+	}
+}
+
+func TestJob_FailRedacts(t *testing.T) {
+	store := NewJobStore()
+	var result JobResult
+	store.OnFinish(func(r JobResult) { result = r })
+	j := store.Create("migration-run", "conn-1")
+	j.Fail(`POST credentials/: {"password": "ansible123"}`)
+	want := `POST credentials/: {"password": ••••}`
+	if j.Error != want {
+		t.Errorf("Error = %q, want %q", j.Error, want)
+	}
+	if result.Error != want {
+		t.Errorf("webhook result error = %q, want %q", result.Error, want)
+	}
+}
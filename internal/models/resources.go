@@ -5,9 +5,11 @@ type Resource map[string]interface{}
 
 // ResourceType describes a browsable resource type on a platform.
 type ResourceType struct {
-	Name       string          `json:"name"`     // "organizations", "job_templates", etc.
-	Label      string          `json:"label"`    // Human-readable: "Job Templates"
-	APIPath    string          `json:"api_path"` // "/api/v2/job_templates/"
-	Skip       map[string]bool `json:"-"`        // Names to never delete
-	MinVersion string          `json:"-"`        // Minimum platform version required, empty = always available
+	Name              string          `json:"name"`     // "organizations", "job_templates", etc.
+	Label             string          `json:"label"`    // Human-readable: "Job Templates"
+	APIPath           string          `json:"api_path"` // "/api/v2/job_templates/"
+	Skip              map[string]bool `json:"-"`        // Names to never delete
+	MinVersion        string          `json:"-"`        // Minimum platform version required, empty = always available
+	MinGatewayVersion string          `json:"-"`        // Minimum AAP gateway version required, empty = always available
+	GatewayAPIPath    string          `json:"-"`        // Used instead of APIPath when the AAP gateway manages the type
 }
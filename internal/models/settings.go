@@ -0,0 +1,141 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Settings are runtime defaults operators can change through the API
+// without restarting. Zero values fall back to the built-in defaults, and
+// settings on a connection take precedence over these.
+type Settings struct {
+	ExportConcurrency int           `json:"export_concurrency" yaml:"export_concurrency"` // inventories exported in parallel by migrations
+	ImportConcurrency int           `json:"import_concurrency" yaml:"import_concurrency"` // hosts created in parallel per inventory
+	MaxRetries        int           `json:"max_retries" yaml:"max_retries"`               // retries for transient errors; -1 disables
+	HealthInterval    time.Duration `json:"health_interval" yaml:"health_interval"`       // how often connections are re-checked
+	SecretKeys        []string      `json:"secret_keys" yaml:"secret_keys"`               // keys masked in job logs, besides DefaultSecretKeys
+}
+
+// settingsJSON is Settings with the health interval written as a duration
+// string such as "60s".
+type settingsJSON struct {
+	ExportConcurrency int      `json:"export_concurrency"`
+	ImportConcurrency int      `json:"import_concurrency"`
+	MaxRetries        int      `json:"max_retries"`
+	HealthInterval    string   `json:"health_interval"`
+	SecretKeys        []string `json:"secret_keys"`
+}
+
+// MarshalJSON writes the health interval as a duration string.
+func (s Settings) MarshalJSON() ([]byte, error) {
+	keys := s.SecretKeys
+	if keys == nil {
+		keys = []string{}
+	}
+	return json.Marshal(settingsJSON{
+		ExportConcurrency: s.ExportConcurrency,
+		ImportConcurrency: s.ImportConcurrency,
+		MaxRetries:        s.MaxRetries,
+		HealthInterval:    s.HealthInterval.String(),
+		SecretKeys:        keys,
+	})
+}
+
+// UnmarshalJSON reads settings written by MarshalJSON. Fields missing from
+// data keep their current values, so a partial object updates only the
+// fields it names.
+func (s *Settings) UnmarshalJSON(data []byte) error {
+	v := settingsJSON{
+		ExportConcurrency: s.ExportConcurrency,
+		ImportConcurrency: s.ImportConcurrency,
+		MaxRetries:        s.MaxRetries,
+		HealthInterval:    s.HealthInterval.String(),
+		SecretKeys:        s.SecretKeys,
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	interval, err := time.ParseDuration(v.HealthInterval)
+	if err != nil {
+		return fmt.Errorf("health_interval: %w", err)
+	}
+	*s = Settings{
+		ExportConcurrency: v.ExportConcurrency,
+		ImportConcurrency: v.ImportConcurrency,
+		MaxRetries:        v.MaxRetries,
+		HealthInterval:    interval,
+		SecretKeys:        v.SecretKeys,
+	}
+	return nil
+}
+
+// Validate returns an error naming the first out-of-range setting.
+func (s Settings) Validate() error {
+	switch {
+	case s.ExportConcurrency < 0:
+		return fmt.Errorf("export_concurrency must not be negative, got %d", s.ExportConcurrency)
+	case s.ImportConcurrency < 0:
+		return fmt.Errorf("import_concurrency must not be negative, got %d", s.ImportConcurrency)
+	case s.MaxRetries < -1:
+		return fmt.Errorf("max_retries must be -1 or more, got %d", s.MaxRetries)
+	case s.HealthInterval < 0:
+		return fmt.Errorf("health_interval must not be negative, got %s", s.HealthInterval)
+	}
+	return nil
+}
+
+// SettingsStore holds the current Settings. A nil store returns zero
+// Settings, so callers fall back to the built-in defaults.
+type SettingsStore struct {
+	mu       sync.RWMutex
+	settings Settings
+	onChange []func(Settings)
+}
+
+// NewSettingsStore creates a store holding initial.
+func NewSettingsStore(initial Settings) *SettingsStore {
+	return &SettingsStore{settings: initial.clone()}
+}
+
+// Get returns a copy of the current settings.
+func (s *SettingsStore) Get() Settings {
+	if s == nil {
+		return Settings{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.clone()
+}
+
+// Update validates and stores settings, then passes them to every
+// OnChange callback.
+func (s *SettingsStore) Update(settings Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.settings = settings.clone()
+	callbacks := s.onChange
+	s.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(settings.clone())
+	}
+	return nil
+}
+
+// OnChange registers fn to be called after every Update, e.g. to apply
+// settings that components copy instead of reading on each use.
+func (s *SettingsStore) OnChange(fn func(Settings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+func (s Settings) clone() Settings {
+	if s.SecretKeys != nil {
+		s.SecretKeys = append([]string(nil), s.SecretKeys...)
+	}
+	return s
+}
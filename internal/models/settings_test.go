@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSettings_PartialJSONUpdate(t *testing.T) {
+	s := Settings{ExportConcurrency: 4, HealthInterval: time.Minute, SecretKeys: []string{"api_key"}}
+	if err := json.Unmarshal([]byte(`{"max_retries": 5, "health_interval": "30s"}`), &s); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if s.ExportConcurrency != 4 || s.MaxRetries != 5 || s.HealthInterval != 30*time.Second || len(s.SecretKeys) != 1 {
+		t.Errorf("settings = %+v, want export_concurrency kept, max_retries 5, health_interval 30s", s)
+	}
+
+	out, _ := json.Marshal(Settings{})
+	want := `{"export_concurrency":0,"import_concurrency":0,"max_retries":0,"health_interval":"0s","secret_keys":[]}`
+	if string(out) != want {
+		t.Errorf("Marshal(Settings{}) = %s, want %s", out, want)
+	}
+}
+
+func TestSettingsStore_Update(t *testing.T) {
+	store := NewSettingsStore(Settings{ImportConcurrency: 2})
+	var seen Settings
+	store.OnChange(func(s Settings) { seen = s })
+
+	if err := store.Update(Settings{ImportConcurrency: -1}); err == nil {
+		t.Error("Update with negative import_concurrency returned nil error")
+	}
+	if got := store.Get().ImportConcurrency; got != 2 {
+		t.Errorf("import_concurrency after rejected update = %d, want 2", got)
+	}
+
+	if err := store.Update(Settings{ImportConcurrency: 8}); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if store.Get().ImportConcurrency != 8 || seen.ImportConcurrency != 8 {
+		t.Errorf("store/OnChange import_concurrency = %d/%d, want 8", store.Get().ImportConcurrency, seen.ImportConcurrency)
+	}
+
+	var nilStore *SettingsStore
+	if got := nilStore.Get(); got.MaxRetries != 0 {
+		t.Errorf("nil store Get() = %+v, want zero settings", got)
+	}
+}
@@ -1,10 +1,9 @@
 package platform
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,10 +13,13 @@ import (
 // AAP resource types (registry).
 var aapResources = []models.ResourceType{
 	{Name: "organizations", Label: "Organizations", APIPath: "/api/controller/v2/organizations/",
-		Skip: map[string]bool{"Default": true}},
-	{Name: "teams", Label: "Teams", APIPath: "/api/controller/v2/teams/"},
+		GatewayAPIPath: "/api/gateway/v1/organizations/",
+		Skip:           map[string]bool{"Default": true}},
+	{Name: "teams", Label: "Teams", APIPath: "/api/controller/v2/teams/",
+		GatewayAPIPath: "/api/gateway/v1/teams/"},
 	{Name: "users", Label: "Users", APIPath: "/api/controller/v2/users/",
-		Skip: map[string]bool{"admin": true}},
+		GatewayAPIPath: "/api/gateway/v1/users/",
+		Skip:           map[string]bool{"admin": true}},
 	{Name: "credential_types", Label: "Credential Types", APIPath: "/api/controller/v2/credential_types/"},
 	{Name: "credentials", Label: "Credentials", APIPath: "/api/controller/v2/credentials/",
 		Skip: map[string]bool{"Demo Credential": true}},
@@ -32,6 +34,8 @@ var aapResources = []models.ResourceType{
 			"Ansible Engine 2.9 Execution Environment": true,
 			"Minimal execution environment":        true,
 		}},
+	{Name: "applications", Label: "Applications", APIPath: "/api/controller/v2/applications/",
+		GatewayAPIPath: "/api/gateway/v1/applications/"},
 	{Name: "job_templates", Label: "Job Templates", APIPath: "/api/controller/v2/job_templates/"},
 	{Name: "workflow_job_templates", Label: "Workflows", APIPath: "/api/controller/v2/workflow_job_templates/"},
 	{Name: "schedules", Label: "Schedules", APIPath: "/api/controller/v2/schedules/"},
@@ -40,11 +44,16 @@ var aapResources = []models.ResourceType{
 // defaultAAPPrefix is the API prefix for AAP 2.5+ (with gateway).
 const defaultAAPPrefix = "/api/controller/v2/"
 
+// gatewayIdentityVersion is the first gateway version that owns users, teams
+// and organizations; from it on, types with a GatewayAPIPath are read there.
+const gatewayIdentityVersion = "2.5"
+
 // AAPPlatform implements Platform for AAP 2.x (controller + gateway).
 type AAPPlatform struct {
 	client    *Client
 	resources []models.ResourceType // nil = use static aapResources
 	version   string                // detected platform version
+	gwVersion string                // detected gateway version, empty before AAP 2.5
 	apiPrefix string                // e.g. "/api/controller/v2/" or "/api/v2/"
 }
 
@@ -61,11 +70,11 @@ func (p *AAPPlatform) path(suffix string) string {
 
 func (p *AAPPlatform) Ping() error {
 	// Try configured prefix first, fall back to alternatives
-	if err := p.client.Ping(p.path("ping/")); err == nil {
+	if err := p.client.PingAnonymous(p.path("ping/")); err == nil {
 		return nil
 	}
 	for _, path := range PingPaths("aap") {
-		if err := p.client.Ping(path); err == nil {
+		if err := p.client.PingAnonymous(path); err == nil {
 			return nil
 		}
 	}
@@ -86,12 +95,15 @@ func (p *AAPPlatform) GetResourceTypes() []models.ResourceType {
 	if p.resources != nil {
 		registry = p.resources
 	}
-	if p.version == "" {
-		return registry
-	}
+	useGateway := p.gwVersion != "" && VersionAtLeast(p.gwVersion, gatewayIdentityVersion)
 	var filtered []models.ResourceType
 	for _, r := range registry {
-		if VersionAtLeast(p.version, r.MinVersion) {
+		// An unknown gateway version may be AAP 2.4, which has no gateway.
+		gatewayOK := r.MinGatewayVersion == "" || p.gwVersion != "" && VersionAtLeast(p.gwVersion, r.MinGatewayVersion)
+		if VersionAtLeast(p.version, r.MinVersion) && gatewayOK {
+			if useGateway && r.GatewayAPIPath != "" {
+				r.APIPath = r.GatewayAPIPath
+			}
 			filtered = append(filtered, r)
 		}
 	}
@@ -107,8 +119,38 @@ func (p *AAPPlatform) ListResources(resourceType string) ([]models.Resource, err
 	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
 }
 
+func (p *AAPPlatform) ListResourcesFiltered(resourceType string, filter ListFilter) ([]models.Resource, error) {
+	for _, rt := range p.GetResourceTypes() {
+		if rt.Name == resourceType {
+			return p.client.GetFiltered(rt.APIPath, filter.params(), filter.PageSize)
+		}
+	}
+	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+}
+
+func (p *AAPPlatform) GetResource(resourceType string, id int) (models.Resource, error) {
+	for _, rt := range p.GetResourceTypes() {
+		if rt.Name == resourceType {
+			var obj models.Resource
+			err := p.client.GetJSON(fmt.Sprintf("%s%d/", rt.APIPath, id), nil, &obj)
+			return obj, err
+		}
+	}
+	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+}
+
+// DeleteResource deletes one object by ID, unless it is managed or a default.
+func (p *AAPPlatform) DeleteResource(resourceType string, id int) error {
+	for _, rt := range p.GetResourceTypes() {
+		if rt.Name == resourceType {
+			return deleteResource(p.client, rt, id)
+		}
+	}
+	return fmt.Errorf("unknown resource type: %s", resourceType)
+}
+
 // Populate creates sample AAP objects (orgs, teams, users, creds, projects, inventories, JTs, workflows, RBAC).
-func (p *AAPPlatform) Populate(logger func(string)) error {
+func (p *AAPPlatform) Populate(ctx context.Context, logger func(string)) error {
 	log := logger
 	c := p.client
 
@@ -151,12 +193,17 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 		return resourceID(created), nil
 	}
 
-	// Associate (POST with {"id": ...}, ignore errors for already-exists)
+	// Associate (POST with {"id": ...}); existing associations are fine
 	associate := func(path string, id int) {
-		c.Post(path, map[string]interface{}{"id": id})
+		if err := associateID(c, path, id); err != nil {
+			log(fmt.Sprintf("    WARNING: associating %d: %v", id, err))
+		}
 	}
 
 	// 1. Organizations
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Organizations ===")
 	orgCorpID, err := ensure(p.path("organizations/"), "MigrateMe-Corp", map[string]interface{}{
 		"name": "MigrateMe-Corp", "description": "Primary corporation for migration testing",
@@ -175,6 +222,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	log(fmt.Sprintf("  Organization: MigrateMe-Ops (id=%d)", orgOpsID))
 
 	// 2. Teams
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Teams ===")
 	type teamDef struct {
 		name  string
@@ -197,6 +247,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 3. Users
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Users ===")
 	type userDef struct {
 		username  string
@@ -221,6 +274,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	userIDs := make(map[string]int)
 	orgNameToID := map[string]int{"MigrateMe-Corp": orgCorpID, "MigrateMe-Ops": orgOpsID}
 	for _, u := range users {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		id, err := ensureUser(p.path("users/"), u.username, map[string]interface{}{
 			"username": u.username, "first_name": u.firstName, "last_name": u.lastName,
 			"email": u.email, "password": "changeme123!",
@@ -242,6 +298,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 4. Credential Types
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Credential Types ===")
 	ctID, err := ensure(p.path("credential_types/"), "API Token", map[string]interface{}{
 		"name": "API Token",
@@ -266,6 +325,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	log(fmt.Sprintf("  Credential Type: API Token (id=%d)", ctID))
 
 	// 5. Credentials
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Credentials ===")
 	type credDef struct {
 		name     string
@@ -304,6 +366,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 6. Projects
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Projects ===")
 	type projDef struct {
 		name   string
@@ -331,7 +396,7 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	// Wait for project sync; on failure, convert to manual project so JTs can still be created
 	log("  Waiting for project sync...")
 	for name, id := range projectIDs {
-		if err := p.waitForProject(id, 120*time.Second); err != nil {
+		if err := c.WaitForProject(ctx, fmt.Sprintf(p.path("projects/%d/"), id), 120*time.Second); err != nil {
 			log(fmt.Sprintf("  WARNING: project %s sync failed: %v", name, err))
 			log(fmt.Sprintf("  Converting %s to manual project (no SCM) so JTs can be created...", name))
 			_, _, patchErr := c.Patch(fmt.Sprintf(p.path("projects/%d/"), id), map[string]interface{}{
@@ -348,6 +413,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 7. Inventories, Hosts, Groups
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Inventories ===")
 	type hostDef struct {
 		name string
@@ -401,6 +469,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 
 	invIDs := make(map[string]int)
 	for _, inv := range inventories {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		invID, err := ensure(p.path("inventories/"), inv.name, map[string]interface{}{
 			"name": inv.name, "organization": inv.orgID,
 		})
@@ -447,6 +518,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 8. Job Templates
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Job Templates ===")
 	type jtDef struct {
 		name      string
@@ -468,6 +542,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 	jtIDs := make(map[string]int)
 	for _, jt := range jts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		id, err := ensure(p.path("job_templates/"), jt.name, map[string]interface{}{
 			"name": jt.name, "project": projectIDs[jt.project],
 			"inventory": invIDs[jt.inventory], "playbook": jt.playbook,
@@ -488,6 +565,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 8b. Schedules
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Schedules ===")
 	type schedDef struct {
 		name  string
@@ -515,8 +595,13 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 			log(fmt.Sprintf("  Schedule: %s (existing id=%d)", s.name, resourceID(existing)))
 			continue
 		}
-		body, _, err := c.Post(fmt.Sprintf(p.path("job_templates/%d/schedules/"), jtID),
-			map[string]interface{}{"name": s.name, "rrule": s.rrule})
+		var body []byte
+		err = retryPopulate(ctx, log, "schedule "+s.name, func() error {
+			var postErr error
+			body, _, postErr = c.Post(fmt.Sprintf(p.path("job_templates/%d/schedules/"), jtID),
+				map[string]interface{}{"name": s.name, "rrule": s.rrule})
+			return postErr
+		})
 		if err != nil {
 			log(fmt.Sprintf("  WARNING: schedule %s: %v", s.name, err))
 			continue
@@ -527,6 +612,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 8c. Surveys
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Surveys ===")
 	type surveyDef struct {
 		jtKey string
@@ -561,13 +649,19 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 			log(fmt.Sprintf("  WARNING: survey for %s: JT not found", sv.jtKey))
 			continue
 		}
-		_, _, err := c.Post(fmt.Sprintf(p.path("job_templates/%d/survey_spec/"), jtID), sv.spec)
+		err := retryPopulate(ctx, log, "survey for "+sv.jtKey, func() error {
+			_, _, postErr := c.Post(fmt.Sprintf(p.path("job_templates/%d/survey_spec/"), jtID), sv.spec)
+			return postErr
+		})
 		if err != nil {
 			log(fmt.Sprintf("  WARNING: survey for %s: %v", sv.jtKey, err))
 			continue
 		}
-		_, _, err = c.Patch(fmt.Sprintf(p.path("job_templates/%d/"), jtID),
-			map[string]interface{}{"survey_enabled": true})
+		err = retryPopulate(ctx, log, "enabling survey for "+sv.jtKey, func() error {
+			_, _, patchErr := c.Patch(fmt.Sprintf(p.path("job_templates/%d/"), jtID),
+				map[string]interface{}{"survey_enabled": true})
+			return patchErr
+		})
 		if err != nil {
 			log(fmt.Sprintf("  WARNING: enabling survey for %s: %v", sv.jtKey, err))
 			continue
@@ -576,6 +670,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 9. Workflow Job Template
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Workflow Job Templates ===")
 	wfjtID, err := ensure(p.path("workflow_job_templates/"), "MigrateMe - Full Deploy Pipeline", map[string]interface{}{
 		"name": "MigrateMe - Full Deploy Pipeline", "organization": orgCorpID,
@@ -639,6 +736,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	// 10. RBAC Roles
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Assigning Team Roles ===")
 	type roleDef struct {
 		teamName   string
@@ -670,6 +770,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 	}
 
 	for _, ra := range roleAssignments {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if ra.objectID == 0 {
 			continue
 		}
@@ -693,7 +796,9 @@ func (p *AAPPlatform) Populate(logger func(string)) error {
 }
 
 // Cleanup deletes non-default objects from AAP in reverse dependency order.
-func (p *AAPPlatform) Cleanup(logger func(string)) error {
+// With opts.DryRun set it only logs what would be deleted; with opts.Archive
+// users and job templates are deactivated instead. Stops early if ctx is cancelled.
+func (p *AAPPlatform) Cleanup(ctx context.Context, opts CleanupOptions, logger func(string)) error {
 	log := logger
 
 	// Deletion order (reverse dependency)
@@ -711,61 +816,59 @@ func (p *AAPPlatform) Cleanup(logger func(string)) error {
 		findResource(registry, "organizations"),
 	}
 
-	deleted, skipped, failed := 0, 0, 0
+	var result cleanupResult
 
 	for _, rt := range deleteOrder {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		log(fmt.Sprintf("\n--- Cleaning %s ---", rt.Label))
 
 		resources, err := p.client.GetAll(rt.APIPath)
 		if err != nil {
 			log(fmt.Sprintf("  ERROR listing %s: %v", rt.Label, err))
-			failed++
+			result.failed++
 			continue
 		}
 
 		for _, res := range resources {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			name := resourceName(res)
-			id := resourceID(res)
 
 			// Skip managed objects
 			if managed, ok := res["managed"].(bool); ok && managed {
 				log(fmt.Sprintf("  SKIP %s (managed)", name))
-				skipped++
+				result.skipped++
 				continue
 			}
 
 			// Skip known defaults
 			if rt.Skip != nil && rt.Skip[name] {
 				log(fmt.Sprintf("  SKIP %s (default)", name))
-				skipped++
+				result.skipped++
 				continue
 			}
 
 			// Skip managed credential types (kind != "cloud" and kind != "net")
 			if rt.Name == "credential_types" {
 				if managed, ok := res["managed"].(bool); ok && managed {
-					skipped++
+					result.skipped++
 					continue
 				}
 			}
 
-			err := p.client.Delete(fmt.Sprintf("%s%d/", rt.APIPath, id))
-			if err != nil {
-				log(fmt.Sprintf("  FAIL %s (id=%d): %v", name, id, err))
-				failed++
-			} else {
-				log(fmt.Sprintf("  DELETED %s (id=%d)", name, id))
-				deleted++
-			}
+			removeResource(p.client, rt, res, opts, &result, log)
 		}
 	}
 
-	log(fmt.Sprintf("\nCleanup complete: %d deleted, %d skipped, %d failed", deleted, skipped, failed))
+	log(result.summary(opts))
 	return nil
 }
 
 // Export downloads AAP assets in breadth-first dependency order.
-func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
+func (p *AAPPlatform) Export(ctx context.Context, outputDir string, opts ExportOptions, logger func(string)) error {
 	log := logger
 
 	downloaded := map[string]map[int]bool{
@@ -778,20 +881,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 		"organizations":           {},
 	}
 
-	fileCount := 0
-
-	writeJSON := func(dir, filename string, data interface{}) error {
-		dirPath := filepath.Join(outputDir, dir)
-		if err := os.MkdirAll(dirPath, 0755); err != nil {
-			return err
-		}
-		b, err := json.MarshalIndent(data, "", "  ")
-		if err != nil {
-			return err
-		}
-		fileCount++
-		return os.WriteFile(filepath.Join(dirPath, filename), b, 0644)
-	}
+	w := newExportWriter(outputDir, p.client.baseURL, opts)
 
 	safeName := func(name string) string {
 		r := strings.NewReplacer(" ", "_", "/", "_", "\\", "_")
@@ -817,7 +907,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeJSON("organizations", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("organizations", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Organization: %s (id=%d)", name, id))
 	}
 
@@ -833,7 +923,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 		}
 		name := obj["name"].(string)
 		obj["inputs"] = map[string]interface{}{"_note": "Sensitive data removed"}
-		writeJSON("credentials", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("credentials", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Credential: %s (id=%d)", name, id))
 		if orgID := intField(obj, "organization"); orgID > 0 {
 			downloadOrg(orgID)
@@ -851,7 +941,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeJSON("execution_environments", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("execution_environments", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Execution Environment: %s (id=%d)", name, id))
 		if orgID := intField(obj, "organization"); orgID > 0 {
 			downloadOrg(orgID)
@@ -869,7 +959,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeJSON("projects", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("projects", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Project: %s (id=%d)", name, id))
 		if orgID := intField(obj, "organization"); orgID > 0 {
 			downloadOrg(orgID)
@@ -895,7 +985,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeJSON("inventories", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("inventories", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Inventory: %s (id=%d)", name, id))
 		if orgID := intField(obj, "organization"); orgID > 0 {
 			downloadOrg(orgID)
@@ -903,7 +993,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 		// Inventory sources
 		sources, err := p.client.GetAll(fmt.Sprintf(p.path("inventories/%d/inventory_sources/"), id))
 		if err == nil && len(sources) > 0 {
-			writeJSON("inventories", fmt.Sprintf("%d_%s_sources.json", id, safeName(name)), sources)
+			w.write("inventories", fmt.Sprintf("%d_%s_sources.json", id, safeName(name)), sources)
 		}
 	}
 
@@ -918,13 +1008,13 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeJSON("job_templates", fmt.Sprintf("%d_%s_details.json", id, safeName(name)), obj)
+		w.writeResource("job_templates", fmt.Sprintf("%d_%s_details.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Job Template: %s (id=%d)", name, id))
 
 		// Survey (optional)
 		var survey map[string]interface{}
 		if err := p.client.GetJSON(fmt.Sprintf(p.path("job_templates/%d/survey_spec/"), id), nil, &survey); err == nil {
-			writeJSON("job_templates", fmt.Sprintf("%d_%s_survey.json", id, safeName(name)), survey)
+			w.write("job_templates", fmt.Sprintf("%d_%s_survey.json", id, safeName(name)), survey)
 		}
 
 		// Dependencies
@@ -958,9 +1048,12 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 		return fmt.Errorf("fetching workflows: %w", err)
 	}
 
-	writeJSON("workflow_job_templates", "_all_workflows.json", workflows)
+	w.write("workflow_job_templates", "_all_workflows.json", workflows)
 
 	for _, wf := range workflows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		wfID := resourceID(wf)
 		name := resourceName(wf)
 		if wfID == 0 {
@@ -975,7 +1068,7 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 			log(fmt.Sprintf("  WARNING: workflow details %d: %v", wfID, err))
 			continue
 		}
-		writeJSON("workflow_job_templates", fmt.Sprintf("%d_%s_details.json", wfID, safeName(name)), details)
+		w.writeResource("workflow_job_templates", fmt.Sprintf("%d_%s_details.json", wfID, safeName(name)), details)
 
 		// Nodes
 		nodes, err := p.client.GetAll(fmt.Sprintf(p.path("workflow_job_templates/%d/workflow_nodes/"), wfID))
@@ -983,16 +1076,19 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 			log(fmt.Sprintf("  WARNING: workflow nodes %d: %v", wfID, err))
 			continue
 		}
-		writeJSON("workflow_job_templates", fmt.Sprintf("%d_%s_nodes.json", wfID, safeName(name)), nodes)
+		w.write("workflow_job_templates", fmt.Sprintf("%d_%s_nodes.json", wfID, safeName(name)), nodes)
 
 		// Survey (optional)
 		var survey map[string]interface{}
 		if err := p.client.GetJSON(fmt.Sprintf(p.path("workflow_job_templates/%d/survey_spec/"), wfID), nil, &survey); err == nil {
-			writeJSON("workflow_job_templates", fmt.Sprintf("%d_%s_survey.json", wfID, safeName(name)), survey)
+			w.write("workflow_job_templates", fmt.Sprintf("%d_%s_survey.json", wfID, safeName(name)), survey)
 		}
 
 		// Process nodes to find job template dependencies
 		for _, node := range nodes {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			// Extract unified_job_template ID from the node
 			if ujt := intField(node, "unified_job_template"); ujt > 0 {
 				// Check if it's a job template by looking at related URLs or summary_fields
@@ -1007,39 +1103,45 @@ func (p *AAPPlatform) Export(outputDir string, logger func(string)) error {
 		}
 	}
 
-	log(fmt.Sprintf("\n=== Export complete: %d JSON files created ===", fileCount))
-	counts := make(map[string]int)
-	for k, v := range downloaded {
-		counts[k] = len(v)
+	if err := w.writeManifest(); err != nil {
+		log(fmt.Sprintf("WARNING: writing manifest: %v", err))
 	}
-	for k, v := range counts {
-		if v > 0 {
-			log(fmt.Sprintf("  %s: %d", k, v))
+	log(fmt.Sprintf("\n=== Export complete: %d %s files created ===", w.files, strings.ToUpper(w.format())))
+	for _, k := range sortedKeys(downloaded) {
+		if n := len(downloaded[k]); n > 0 {
+			log(fmt.Sprintf("  %s: %d", k, n))
 		}
 	}
 
 	return nil
 }
 
-// waitForProject polls a project until its status is "successful" or "failed".
-func (p *AAPPlatform) waitForProject(id int, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		var proj map[string]interface{}
-		err := p.client.GetJSON(fmt.Sprintf(p.path("projects/%d/"), id), nil, &proj)
-		if err != nil {
-			return err
-		}
-		status, _ := proj["status"].(string)
-		switch status {
-		case "successful":
+// Populate retry settings for requests that depend on a job template being
+// fully committed (a freshly booted controller often rejects the first attempt).
+const (
+	populateRetryAttempts = 4
+	populateRetryWait     = 3 * time.Second
+)
+
+// retryPopulate runs fn until it succeeds, logging each retry, and gives up
+// after populateRetryAttempts attempts or when ctx is cancelled.
+func retryPopulate(ctx context.Context, log func(string), what string, fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= populateRetryAttempts; attempt++ {
+		if err = fn(); err == nil {
 			return nil
-		case "failed", "error", "canceled":
-			return fmt.Errorf("project sync status: %s", status)
 		}
-		time.Sleep(3 * time.Second)
+		if attempt == populateRetryAttempts {
+			break
+		}
+		log(fmt.Sprintf("  RETRY %d/%d: %s: %v", attempt, populateRetryAttempts-1, what, err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(populateRetryWait):
+		}
 	}
-	return fmt.Errorf("timeout waiting for project sync")
+	return err
 }
 
 func findResource(resources []models.ResourceType, name string) models.ResourceType {
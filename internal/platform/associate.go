@@ -0,0 +1,30 @@
+package platform
+
+import (
+	"net/http"
+	"strings"
+)
+
+// associateID adds the object with the given ID to the list at path, e.g. a
+// user to an organization's users. An association that already exists is
+// not an error; anything else the server rejects, such as a 403, is.
+func associateID(c *Client, path string, id int) error {
+	body, status, err := c.Post(path, map[string]interface{}{"id": id})
+	if err != nil && !alreadyAssociated(status, body) {
+		return err
+	}
+	return nil
+}
+
+// alreadyAssociated reports whether a failed association response means the
+// objects were associated before. AWX answers 204 to a repeated
+// association, but some lists reject duplicates with a 400 or 409 that says
+// so.
+func alreadyAssociated(status int, body []byte) bool {
+	if status != http.StatusBadRequest && status != http.StatusConflict {
+		return false
+	}
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, "already exists") || strings.Contains(msg, "already associated") ||
+		strings.Contains(msg, "already a member")
+}
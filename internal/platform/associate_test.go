@@ -0,0 +1,40 @@
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAssociateID(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"associated", http.StatusNoContent, "", ""},
+		{"already associated", http.StatusBadRequest, `{"msg":"User is already a member of this team."}`, ""},
+		{"duplicate", http.StatusConflict, `{"detail":"Association already exists."}`, ""},
+		{"forbidden", http.StatusForbidden, `{"detail":"You do not have permission to perform this action."}`, "HTTP 403"},
+		{"other bad request", http.StatusBadRequest, `{"error":"Cannot assign multiple Machine credentials."}`, "HTTP 400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			err := associateID(newTestClient(ts), "/api/v2/teams/3/users/", 7)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("associateID returned error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("associateID error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,10 +1,9 @@
 package platform
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -47,7 +46,7 @@ func NewAWXPlatform(client *Client) *AWXPlatform {
 }
 
 func (p *AWXPlatform) Ping() error {
-	return p.client.Ping("/api/v2/ping/")
+	return p.client.PingAnonymous("/api/v2/ping/")
 }
 
 func (p *AWXPlatform) CheckAuth() error {
@@ -80,8 +79,38 @@ func (p *AWXPlatform) ListResources(resourceType string) ([]models.Resource, err
 	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
 }
 
+func (p *AWXPlatform) ListResourcesFiltered(resourceType string, filter ListFilter) ([]models.Resource, error) {
+	for _, rt := range p.GetResourceTypes() {
+		if rt.Name == resourceType {
+			return p.client.GetFiltered(rt.APIPath, filter.params(), filter.PageSize)
+		}
+	}
+	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+}
+
+func (p *AWXPlatform) GetResource(resourceType string, id int) (models.Resource, error) {
+	for _, rt := range p.GetResourceTypes() {
+		if rt.Name == resourceType {
+			var obj models.Resource
+			err := p.client.GetJSON(fmt.Sprintf("%s%d/", rt.APIPath, id), nil, &obj)
+			return obj, err
+		}
+	}
+	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+}
+
+// DeleteResource deletes one object by ID, unless it is managed or a default.
+func (p *AWXPlatform) DeleteResource(resourceType string, id int) error {
+	for _, rt := range p.GetResourceTypes() {
+		if rt.Name == resourceType {
+			return deleteResource(p.client, rt, id)
+		}
+	}
+	return fmt.Errorf("unknown resource type: %s", resourceType)
+}
+
 // Export downloads AWX assets in breadth-first dependency order.
-func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
+func (p *AWXPlatform) Export(ctx context.Context, outputDir string, opts ExportOptions, logger func(string)) error {
 	log := logger
 
 	downloaded := map[string]map[int]bool{
@@ -93,20 +122,7 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 		"organizations":          {},
 	}
 
-	fileCount := 0
-
-	writeFile := func(dir, filename string, data interface{}) error {
-		dirPath := filepath.Join(outputDir, dir)
-		if err := os.MkdirAll(dirPath, 0755); err != nil {
-			return err
-		}
-		b, err := json.MarshalIndent(data, "", "  ")
-		if err != nil {
-			return err
-		}
-		fileCount++
-		return os.WriteFile(filepath.Join(dirPath, filename), b, 0644)
-	}
+	w := newExportWriter(outputDir, p.client.baseURL, opts)
 
 	safeName := func(name string) string {
 		r := strings.NewReplacer(" ", "_", "/", "_", "\\", "_")
@@ -130,7 +146,7 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeFile("organizations", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("organizations", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Organization: %s (id=%d)", name, id))
 	}
 
@@ -146,7 +162,7 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 		}
 		name := obj["name"].(string)
 		obj["inputs"] = map[string]interface{}{"_note": "Sensitive data removed"}
-		writeFile("credentials", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("credentials", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Credential: %s (id=%d)", name, id))
 		if orgID := intField(obj, "organization"); orgID > 0 {
 			downloadOrg(orgID)
@@ -164,7 +180,7 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeFile("projects", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("projects", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Project: %s (id=%d)", name, id))
 		if orgID := intField(obj, "organization"); orgID > 0 {
 			downloadOrg(orgID)
@@ -189,14 +205,14 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeFile("inventories", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
+		w.writeResource("inventories", fmt.Sprintf("%d_%s.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Inventory: %s (id=%d)", name, id))
 		if orgID := intField(obj, "organization"); orgID > 0 {
 			downloadOrg(orgID)
 		}
 		sources, err := p.client.GetAll(fmt.Sprintf("/api/v2/inventories/%d/inventory_sources/", id))
 		if err == nil && len(sources) > 0 {
-			writeFile("inventories", fmt.Sprintf("%d_%s_sources.json", id, safeName(name)), sources)
+			w.write("inventories", fmt.Sprintf("%d_%s_sources.json", id, safeName(name)), sources)
 		}
 	}
 
@@ -211,12 +227,12 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 			return
 		}
 		name := obj["name"].(string)
-		writeFile("job_templates", fmt.Sprintf("%d_%s_details.json", id, safeName(name)), obj)
+		w.writeResource("job_templates", fmt.Sprintf("%d_%s_details.json", id, safeName(name)), obj)
 		log(fmt.Sprintf("  Job Template: %s (id=%d)", name, id))
 
 		var survey map[string]interface{}
 		if err := p.client.GetJSON(fmt.Sprintf("/api/v2/job_templates/%d/survey_spec/", id), nil, &survey); err == nil {
-			writeFile("job_templates", fmt.Sprintf("%d_%s_survey.json", id, safeName(name)), survey)
+			w.write("job_templates", fmt.Sprintf("%d_%s_survey.json", id, safeName(name)), survey)
 		}
 
 		if projID := intField(obj, "project"); projID > 0 {
@@ -245,9 +261,12 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 		return fmt.Errorf("fetching workflows: %w", err)
 	}
 
-	writeFile("workflow_job_templates", "_all_workflows.json", workflows)
+	w.write("workflow_job_templates", "_all_workflows.json", workflows)
 
 	for _, wf := range workflows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		wfID := resourceID(wf)
 		name := resourceName(wf)
 		if wfID == 0 {
@@ -261,21 +280,24 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 			log(fmt.Sprintf("  WARNING: workflow details %d: %v", wfID, err))
 			continue
 		}
-		writeFile("workflow_job_templates", fmt.Sprintf("%d_%s_details.json", wfID, safeName(name)), details)
+		w.writeResource("workflow_job_templates", fmt.Sprintf("%d_%s_details.json", wfID, safeName(name)), details)
 
 		nodes, err := p.client.GetAll(fmt.Sprintf("/api/v2/workflow_job_templates/%d/workflow_nodes/", wfID))
 		if err != nil {
 			log(fmt.Sprintf("  WARNING: workflow nodes %d: %v", wfID, err))
 			continue
 		}
-		writeFile("workflow_job_templates", fmt.Sprintf("%d_%s_nodes.json", wfID, safeName(name)), nodes)
+		w.write("workflow_job_templates", fmt.Sprintf("%d_%s_nodes.json", wfID, safeName(name)), nodes)
 
 		var survey map[string]interface{}
 		if err := p.client.GetJSON(fmt.Sprintf("/api/v2/workflow_job_templates/%d/survey_spec/", wfID), nil, &survey); err == nil {
-			writeFile("workflow_job_templates", fmt.Sprintf("%d_%s_survey.json", wfID, safeName(name)), survey)
+			w.write("workflow_job_templates", fmt.Sprintf("%d_%s_survey.json", wfID, safeName(name)), survey)
 		}
 
 		for _, node := range nodes {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if ujt := intField(node, "unified_job_template"); ujt > 0 {
 				if sf, ok := node["summary_fields"].(map[string]interface{}); ok {
 					if ujtData, ok := sf["unified_job_template"].(map[string]interface{}); ok {
@@ -288,10 +310,13 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 		}
 	}
 
-	log(fmt.Sprintf("\n=== Export complete: %d JSON files created ===", fileCount))
-	for k, v := range downloaded {
-		if len(v) > 0 {
-			log(fmt.Sprintf("  %s: %d", k, len(v)))
+	if err := w.writeManifest(); err != nil {
+		log(fmt.Sprintf("WARNING: writing manifest: %v", err))
+	}
+	log(fmt.Sprintf("\n=== Export complete: %d %s files created ===", w.files, strings.ToUpper(w.format())))
+	for _, k := range sortedKeys(downloaded) {
+		if n := len(downloaded[k]); n > 0 {
+			log(fmt.Sprintf("  %s: %d", k, n))
 		}
 	}
 
@@ -299,7 +324,9 @@ func (p *AWXPlatform) Export(outputDir string, logger func(string)) error {
 }
 
 // Cleanup deletes non-default objects from AWX in reverse dependency order.
-func (p *AWXPlatform) Cleanup(logger func(string)) error {
+// With opts.DryRun set it only logs what would be deleted; with opts.Archive
+// users and job templates are deactivated instead. Stops early if ctx is cancelled.
+func (p *AWXPlatform) Cleanup(ctx context.Context, opts CleanupOptions, logger func(string)) error {
 	log := logger
 
 	// Deletion order (reverse dependency)
@@ -316,53 +343,51 @@ func (p *AWXPlatform) Cleanup(logger func(string)) error {
 		findResource(awxResources, "organizations"),
 	}
 
-	deleted, skipped, failed := 0, 0, 0
+	var result cleanupResult
 
 	for _, rt := range deleteOrder {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		log(fmt.Sprintf("\n--- Cleaning %s ---", rt.Label))
 
 		resources, err := p.client.GetAll(rt.APIPath)
 		if err != nil {
 			log(fmt.Sprintf("  ERROR listing %s: %v", rt.Label, err))
-			failed++
+			result.failed++
 			continue
 		}
 
 		for _, res := range resources {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			name := resourceName(res)
-			id := resourceID(res)
 
 			// Skip managed objects (built-in credential types, etc.)
 			if managed, ok := res["managed"].(bool); ok && managed {
 				log(fmt.Sprintf("  SKIP %s (managed)", name))
-				skipped++
+				result.skipped++
 				continue
 			}
 
 			// Skip known defaults
 			if rt.Skip != nil && rt.Skip[name] {
 				log(fmt.Sprintf("  SKIP %s (default)", name))
-				skipped++
+				result.skipped++
 				continue
 			}
 
-			err := p.client.Delete(fmt.Sprintf("%s%d/", rt.APIPath, id))
-			if err != nil {
-				log(fmt.Sprintf("  FAIL %s (id=%d): %v", name, id, err))
-				failed++
-			} else {
-				log(fmt.Sprintf("  DELETED %s (id=%d)", name, id))
-				deleted++
-			}
+			removeResource(p.client, rt, res, opts, &result, log)
 		}
 	}
 
-	log(fmt.Sprintf("\nCleanup complete: %d deleted, %d skipped, %d failed", deleted, skipped, failed))
+	log(result.summary(opts))
 	return nil
 }
 
 // Populate creates sample AWX objects (orgs, teams, users, creds, projects, inventories, JTs, workflows, RBAC).
-func (p *AWXPlatform) Populate(logger func(string)) error {
+func (p *AWXPlatform) Populate(ctx context.Context, logger func(string)) error {
 	log := logger
 	c := p.client
 
@@ -405,12 +430,17 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 		return resourceID(created), nil
 	}
 
-	// Associate (POST with {"id": ...}, ignore errors for already-exists)
+	// Associate (POST with {"id": ...}); existing associations are fine
 	associate := func(path string, id int) {
-		c.Post(path, map[string]interface{}{"id": id})
+		if err := associateID(c, path, id); err != nil {
+			log(fmt.Sprintf("    WARNING: associating %d: %v", id, err))
+		}
 	}
 
 	// 1. Organizations
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Organizations ===")
 	orgCorpID, err := ensure("/api/v2/organizations/", "MigrateMe-Corp", map[string]interface{}{
 		"name": "MigrateMe-Corp", "description": "Primary corporation for migration testing",
@@ -429,6 +459,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	log(fmt.Sprintf("  Organization: MigrateMe-Ops (id=%d)", orgOpsID))
 
 	// 2. Teams
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Teams ===")
 	type teamDef struct {
 		name  string
@@ -451,6 +484,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 3. Users
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Users ===")
 	type userDef struct {
 		username  string
@@ -475,6 +511,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	userIDs := make(map[string]int)
 	orgNameToID := map[string]int{"MigrateMe-Corp": orgCorpID, "MigrateMe-Ops": orgOpsID}
 	for _, u := range users {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		id, err := ensureUser("/api/v2/users/", u.username, map[string]interface{}{
 			"username": u.username, "first_name": u.firstName, "last_name": u.lastName,
 			"email": u.email, "password": "changeme123!",
@@ -496,6 +535,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 4. Credential Types
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Credential Types ===")
 	ctID, err := ensure("/api/v2/credential_types/", "API Token", map[string]interface{}{
 		"name": "API Token",
@@ -520,6 +562,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	log(fmt.Sprintf("  Credential Type: API Token (id=%d)", ctID))
 
 	// 5. Credentials
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Credentials ===")
 	type credDef struct {
 		name      string
@@ -558,6 +603,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 6. Projects
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Projects ===")
 	type projDef struct {
 		name   string
@@ -585,7 +633,7 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	// Wait for project sync; on failure, convert to manual project so JTs can still be created
 	log("  Waiting for project sync...")
 	for name, id := range projectIDs {
-		if err := p.waitForProject(id, 120*time.Second); err != nil {
+		if err := c.WaitForProject(ctx, fmt.Sprintf("/api/v2/projects/%d/", id), 120*time.Second); err != nil {
 			log(fmt.Sprintf("  WARNING: project %s sync failed: %v", name, err))
 			log(fmt.Sprintf("  Converting %s to manual project (no SCM) so JTs can be created...", name))
 			_, _, patchErr := c.Patch(fmt.Sprintf("/api/v2/projects/%d/", id), map[string]interface{}{
@@ -602,6 +650,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 7. Inventories, Hosts, Groups
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Inventories ===")
 	type hostDef struct {
 		name string
@@ -655,6 +706,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 
 	invIDs := make(map[string]int)
 	for _, inv := range inventories {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		invID, err := ensure("/api/v2/inventories/", inv.name, map[string]interface{}{
 			"name": inv.name, "organization": inv.orgID,
 		})
@@ -701,6 +755,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 8. Job Templates
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Job Templates ===")
 	type jtDef struct {
 		name      string
@@ -722,6 +779,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 	jtIDs := make(map[string]int)
 	for _, jt := range jts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		id, err := ensure("/api/v2/job_templates/", jt.name, map[string]interface{}{
 			"name": jt.name, "project": projectIDs[jt.project],
 			"inventory": invIDs[jt.inventory], "playbook": jt.playbook,
@@ -742,6 +802,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 8b. Schedules
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Schedules ===")
 	type schedDef struct {
 		name  string
@@ -781,6 +844,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 8c. Surveys
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Surveys ===")
 	type surveyDef struct {
 		jtKey string
@@ -830,6 +896,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 9. Workflow Job Template
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Creating Workflow Job Templates ===")
 	wfjtID, err := ensure("/api/v2/workflow_job_templates/", "MigrateMe - Full Deploy Pipeline", map[string]interface{}{
 		"name": "MigrateMe - Full Deploy Pipeline", "organization": orgCorpID,
@@ -893,6 +962,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	// 10. RBAC Roles
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	log("\n=== Assigning Team Roles ===")
 	type roleDef struct {
 		teamName   string
@@ -924,6 +996,9 @@ func (p *AWXPlatform) Populate(logger func(string)) error {
 	}
 
 	for _, ra := range roleAssignments {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if ra.objectID == 0 {
 			continue
 		}
@@ -986,23 +1061,3 @@ func extractRoleID(obj map[string]interface{}, field string) int {
 	}
 	return 0
 }
-
-func (p *AWXPlatform) waitForProject(id int, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		var proj map[string]interface{}
-		err := p.client.GetJSON(fmt.Sprintf("/api/v2/projects/%d/", id), nil, &proj)
-		if err != nil {
-			return err
-		}
-		status, _ := proj["status"].(string)
-		switch status {
-		case "successful":
-			return nil
-		case "failed", "error", "canceled":
-			return fmt.Errorf("project sync status: %s", status)
-		}
-		time.Sleep(3 * time.Second)
-	}
-	return fmt.Errorf("timeout waiting for project sync")
-}
@@ -0,0 +1,79 @@
+package platform
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache keeps list pages with their ETag so repeated listings can be
+// revalidated with If-None-Match instead of transferring the full body
+// again. Entries are scoped per connection and dropped after the TTL: when
+// looked up, or by a sweep on the next put at most once per TTL. A nil
+// *ResponseCache caches nothing.
+type ResponseCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]cacheEntry // scope + " " + URL → entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type cacheEntry struct {
+	etag   string
+	body   []byte
+	stored time.Time
+}
+
+// NewResponseCache returns a cache whose entries expire after ttl.
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{ttl: ttl, entries: make(map[string]cacheEntry), now: time.Now}
+}
+
+// Invalidate drops every entry of a connection.
+func (rc *ResponseCache) Invalidate(scope string) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key := range rc.entries {
+		if strings.HasPrefix(key, scope+" ") {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+// get returns the unexpired entry for key, if any.
+func (rc *ResponseCache) get(key string) (cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e, ok := rc.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if rc.now().Sub(e.stored) > rc.ttl {
+		delete(rc.entries, key)
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+// put stores body under key when the response carried an ETag.
+func (rc *ResponseCache) put(key, etag string, body []byte) {
+	if etag == "" {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := rc.now()
+	if now.Sub(rc.lastSweep) > rc.ttl {
+		for k, e := range rc.entries {
+			if now.Sub(e.stored) > rc.ttl {
+				delete(rc.entries, k)
+			}
+		}
+		rc.lastSweep = now
+	}
+	rc.entries[key] = cacheEntry{etag: etag, body: body, stored: now}
+}
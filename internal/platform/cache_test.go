@@ -0,0 +1,91 @@
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// etagServer serves one list page with an ETag and answers 304 to requests
+// that already hold it.
+type etagServer struct {
+	mu          sync.Mutex
+	full, short int // 200 and 304 responses sent
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Header.Get("If-None-Match") == `"v1"` {
+		s.short++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.full++
+	w.Header().Set("ETag", `"v1"`)
+	w.Write([]byte(`{"count":2,"next":null,"results":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`))
+}
+
+func TestGetAll_RevalidatesCachedPage(t *testing.T) {
+	srv := &etagServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	cache := NewResponseCache(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		// A new client per listing, as the API handlers create one per request.
+		results, err := newTestClient(ts).WithCache(cache, "conn1").GetAll("/api/v2/organizations/")
+		if err != nil {
+			t.Fatalf("GetAll #%d returned error: %v", i+1, err)
+		}
+		if len(results) != 2 || resourceName(results[1]) != "b" {
+			t.Fatalf("GetAll #%d = %v, want both organizations", i+1, results)
+		}
+	}
+	if srv.full != 1 || srv.short != 2 {
+		t.Errorf("server sent %d full and %d not-modified responses, want 1 and 2", srv.full, srv.short)
+	}
+}
+
+func TestResponseCache_InvalidateAndTTL(t *testing.T) {
+	srv := &etagServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	now := time.Now()
+	cache := NewResponseCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	list := func() {
+		if _, err := newTestClient(ts).WithCache(cache, "conn1").GetAll("/api/v2/organizations/"); err != nil {
+			t.Fatalf("GetAll returned error: %v", err)
+		}
+	}
+
+	list()
+	cache.Invalidate("conn2") // other connections are unaffected
+	list()
+	cache.Invalidate("conn1")
+	list()
+	now = now.Add(2 * time.Minute)
+	list()
+
+	if srv.full != 3 || srv.short != 1 {
+		t.Errorf("server sent %d full and %d not-modified responses, want 3 and 1", srv.full, srv.short)
+	}
+}
+
+func TestResponseCache_SweepsExpiredEntries(t *testing.T) {
+	now := time.Now()
+	cache := NewResponseCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("conn1 /a", `"1"`, []byte("a"))
+	cache.put("conn1 /b", `"1"`, []byte("b"))
+	now = now.Add(2 * time.Minute)
+	cache.put("conn1 /c", `"1"`, []byte("c"))
+
+	if len(cache.entries) != 1 {
+		t.Errorf("cache holds %d entries, want only the one stored after expiry", len(cache.entries))
+	}
+}
@@ -0,0 +1,114 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// CleanupOptions controls how Platform.Cleanup removes objects.
+type CleanupOptions struct {
+	// DryRun only logs what would be removed.
+	DryRun bool
+	// Archive deactivates objects of the types in archiveFlags instead of
+	// deleting them, so they can be recovered. Other types are still deleted.
+	Archive bool
+}
+
+// archiveFlags maps the resource types that can be archived to the boolean
+// field PATCHed to false to deactivate them.
+var archiveFlags = map[string]string{
+	"users":         "is_active",
+	"job_templates": "enabled",
+}
+
+// ErrProtected is wrapped by the error of DeleteResource for objects Cleanup
+// keeps: managed objects and the defaults in a type's Skip list.
+var ErrProtected = errors.New("protected object")
+
+// deleteResource deletes one object of type rt, applying the same
+// protections as Cleanup. The error wraps ErrNotFound when the object does
+// not exist.
+func deleteResource(c *Client, rt models.ResourceType, id int) error {
+	path := fmt.Sprintf("%s%d/", rt.APIPath, id)
+	var res models.Resource
+	if err := c.GetJSON(path, nil, &res); err != nil {
+		return err
+	}
+	name := resourceName(res)
+	if managed, ok := res["managed"].(bool); ok && managed {
+		return fmt.Errorf("%s %q is managed: %w", rt.Name, name, ErrProtected)
+	}
+	if rt.Skip[name] {
+		return fmt.Errorf("%s %q is a default: %w", rt.Name, name, ErrProtected)
+	}
+	return c.Delete(path)
+}
+
+// cleanupResult tallies what a cleanup did.
+type cleanupResult struct {
+	deleted, archived, skipped, failed int
+}
+
+// removeResource deletes one object, or archives it when opts.Archive is set
+// and its type supports it. Objects of an archivable type that do not carry
+// the flag are skipped rather than deleted.
+func removeResource(c *Client, rt models.ResourceType, res models.Resource, opts CleanupOptions, r *cleanupResult, log func(string)) {
+	name := resourceName(res)
+	id := resourceID(res)
+	path := fmt.Sprintf("%s%d/", rt.APIPath, id)
+
+	if flag, ok := archiveFlags[rt.Name]; ok && opts.Archive {
+		active, ok := res[flag].(bool)
+		switch {
+		case !ok:
+			log(fmt.Sprintf("  SKIP %s (no %s field to archive)", name, flag))
+			r.skipped++
+		case !active:
+			log(fmt.Sprintf("  SKIP %s (already archived)", name))
+			r.skipped++
+		case opts.DryRun:
+			log(fmt.Sprintf("  WOULD ARCHIVE %s (id=%d)", name, id))
+			r.archived++
+		default:
+			if _, _, err := c.Patch(path, map[string]interface{}{flag: false}); err != nil {
+				log(fmt.Sprintf("  FAIL %s (id=%d): %v", name, id, err))
+				r.failed++
+				return
+			}
+			log(fmt.Sprintf("  ARCHIVED %s (id=%d)", name, id))
+			r.archived++
+		}
+		return
+	}
+
+	if opts.DryRun {
+		log(fmt.Sprintf("  WOULD DELETE %s (id=%d)", name, id))
+		r.deleted++
+		return
+	}
+	if err := c.Delete(path); err != nil {
+		log(fmt.Sprintf("  FAIL %s (id=%d): %v", name, id, err))
+		r.failed++
+		return
+	}
+	log(fmt.Sprintf("  DELETED %s (id=%d)", name, id))
+	r.deleted++
+}
+
+// summary returns the closing log line of a cleanup.
+func (r *cleanupResult) summary(opts CleanupOptions) string {
+	archived := ""
+	if opts.Archive {
+		verb := "archived"
+		if opts.DryRun {
+			verb = "would be archived"
+		}
+		archived = fmt.Sprintf(", %d %s", r.archived, verb)
+	}
+	if opts.DryRun {
+		return fmt.Sprintf("\nDry run complete: %d would be deleted%s, %d skipped, %d failed", r.deleted, archived, r.skipped, r.failed)
+	}
+	return fmt.Sprintf("\nCleanup complete: %d deleted%s, %d skipped, %d failed", r.deleted, archived, r.skipped, r.failed)
+}
@@ -0,0 +1,147 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cleanupServer lists one "Default" and one user-created object for every
+// resource type and counts DELETE requests.
+type cleanupServer struct {
+	mu      sync.Mutex
+	deletes int
+}
+
+func (c *cleanupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r.Method == "DELETE" {
+		c.deletes++
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Write([]byte(`{"count":2,"next":null,"results":[{"id":1,"name":"Default"},{"id":2,"name":"Scratch"}]}`))
+}
+
+func TestCleanup_DryRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform func(*Client) Platform
+	}{
+		{"awx", func(c *Client) Platform { return NewAWXPlatform(c) }},
+		{"aap", func(c *Client) Platform { return NewAAPPlatform(c) }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := &cleanupServer{}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+
+			var logs []string
+			p := tc.platform(newTestClient(ts))
+			if err := p.Cleanup(context.Background(), CleanupOptions{DryRun: true}, func(s string) { logs = append(logs, s) }); err != nil {
+				t.Fatalf("Cleanup returned error: %v", err)
+			}
+			if srv.deletes != 0 {
+				t.Errorf("dry run sent %d DELETE requests, want 0", srv.deletes)
+			}
+
+			out := strings.Join(logs, "\n")
+			if !strings.Contains(out, "  WOULD DELETE Scratch (id=2)") {
+				t.Error("missing WOULD DELETE line for Scratch")
+			}
+			// 10 resource types × 2 objects, less the Default organization
+			if !strings.Contains(out, "Dry run complete: 19 would be deleted, 1 skipped, 0 failed") {
+				t.Errorf("unexpected summary: %q", logs[len(logs)-1])
+			}
+		})
+	}
+}
+
+func TestCleanup_Deletes(t *testing.T) {
+	srv := &cleanupServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	if err := NewAWXPlatform(newTestClient(ts)).Cleanup(context.Background(), CleanupOptions{}, func(string) {}); err != nil {
+		t.Fatalf("Cleanup returned error: %v", err)
+	}
+	if srv.deletes != 19 {
+		t.Errorf("sent %d DELETE requests, want 19", srv.deletes)
+	}
+}
+
+func TestCleanup_StopsWhenCancelled(t *testing.T) {
+	srv := &cleanupServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	logger := func(s string) {
+		// Cancel as soon as the first object is deleted.
+		if strings.HasPrefix(s, "  DELETED ") {
+			cancel()
+		}
+	}
+	err := NewAWXPlatform(newTestClient(ts)).Cleanup(ctx, CleanupOptions{}, logger)
+	if err != context.Canceled {
+		t.Fatalf("Cleanup error = %v, want context.Canceled", err)
+	}
+	if srv.deletes != 1 {
+		t.Errorf("sent %d DELETE requests after cancel, want 1", srv.deletes)
+	}
+}
+
+func TestCleanup_ArchiveUsers(t *testing.T) {
+	var mu sync.Mutex
+	var deletes, patches []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "DELETE":
+			deletes = append(deletes, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case "PATCH":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["is_active"] != false {
+				t.Errorf("PATCH %s body = %v, want is_active false", r.URL.Path, body)
+			}
+			patches = append(patches, r.URL.Path)
+			w.Write([]byte(`{}`))
+		default:
+			if strings.HasSuffix(r.URL.Path, "/users/") {
+				w.Write([]byte(`{"count":2,"next":null,"results":[{"id":2,"username":"alice","is_active":true},{"id":3,"username":"bob","is_active":false}]}`))
+				return
+			}
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	var logs []string
+	err := NewAAPPlatform(newTestClient(ts)).Cleanup(context.Background(), CleanupOptions{Archive: true},
+		func(s string) { logs = append(logs, s) })
+	if err != nil {
+		t.Fatalf("Cleanup returned error: %v", err)
+	}
+	if len(deletes) != 0 {
+		t.Errorf("archive mode sent DELETE requests: %v", deletes)
+	}
+	if len(patches) != 1 || !strings.HasSuffix(patches[0], "/users/2/") {
+		t.Errorf("PATCH requests = %v, want only user 2", patches)
+	}
+	out := strings.Join(logs, "\n")
+	if !strings.Contains(out, "  SKIP bob (already archived)") {
+		t.Error("missing already-archived skip for bob")
+	}
+	if !strings.Contains(out, "Cleanup complete: 0 deleted, 1 archived, 1 skipped, 0 failed") {
+		t.Errorf("unexpected summary: %q", logs[len(logs)-1])
+	}
+}
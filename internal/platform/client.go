@@ -2,28 +2,66 @@ package platform
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"golang.org/x/time/rate"
+)
+
+// DefaultTimeout bounds each HTTP request when the connection sets no timeout.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultRetries is how often a transient failure is retried when the
+// connection does not configure max_retries.
+const DefaultRetries = 3
+
+const (
+	retryBaseWait = 500 * time.Millisecond
+	retryMaxWait  = 30 * time.Second
 )
 
 // Client is a shared HTTP client used by platform implementations.
 type Client struct {
-	baseURL    string
-	username   string
-	password   string
-	httpClient *http.Client
+	baseURL      string
+	username     string
+	password     string
+	token        string
+	retries      int
+	retryWait    time.Duration   // initial backoff, doubled on each retry
+	limiter      *rate.Limiter   // shared by the connection's clients; nil when unlimited
+	pageWorkers  int             // pages GetAll fetches in parallel; <= 1 is serial
+	writeWorkers int             // resources a migration creates in parallel; <= 1 is serial
+	err          error           // configuration error (e.g. unreadable CA file) returned by every request
+	cache        *ResponseCache  // list pages revalidated by ETag; nil disables
+	cacheScope   string          // connection ID the cache entries belong to
+	debug        *httpDebug      // logs requests and responses; nil disables
+	dryRun       *dryRun         // answers writes without sending them; nil disables
+	metrics      *Metrics        // counts requests and their latency; nil disables
+	ctx          context.Context // bounds requests and waits; nil is context.Background
+	httpClient   *http.Client
 }
 
 // NewClient creates a Client from a Connection.
 func NewClient(conn *models.Connection) *Client {
 	transport := &http.Transport{}
+	var caErr error
 	if conn.Insecure {
 		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	} else if conn.CACert != "" {
@@ -31,22 +69,313 @@ func NewClient(conn *models.Connection) *Client {
 		if caCertPool.AppendCertsFromPEM([]byte(conn.CACert)) {
 			transport.TLSClientConfig = &tls.Config{RootCAs: caCertPool}
 		}
+	} else if conn.CACertFile != "" {
+		var caCertPool *x509.CertPool
+		caCertPool, caErr = loadCACertFile(conn.CACertFile)
+		if caErr == nil {
+			transport.TLSClientConfig = &tls.Config{RootCAs: caCertPool}
+		}
+	}
+	clientCert, certErr := loadClientCert(conn)
+	if clientCert != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	if caErr == nil {
+		caErr = certErr
+	}
+	timeout := conn.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	defaults := defaultSettings.Get()
+	retries := conn.MaxRetries
+	if retries == 0 {
+		retries = defaults.MaxRetries
+	}
+	if retries == 0 {
+		retries = DefaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+	writeWorkers := conn.ImportConcurrency
+	if writeWorkers == 0 {
+		writeWorkers = defaults.ImportConcurrency
 	}
-	return &Client{
-		baseURL:  conn.BaseURL(),
-		username: conn.Username,
-		password: conn.Password,
-		httpClient: &http.Client{
-			Transport: transport,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				// Re-apply basic auth on redirects
-				if len(via) > 0 {
-					req.SetBasicAuth(conn.Username, conn.Password)
-				}
-				return nil
-			},
+	c := &Client{
+		baseURL:      conn.BaseURL(),
+		username:     conn.Username,
+		password:     conn.Password,
+		token:        conn.Token,
+		retries:      retries,
+		retryWait:    retryBaseWait,
+		limiter:      limiterFor(conn.ID, conn.RateLimit),
+		pageWorkers:  conn.PageConcurrency,
+		writeWorkers: writeWorkers,
+		err:          caErr,
+		debug:        defaultDebug,
+		metrics:      defaultMetrics,
+	}
+	c.httpClient = &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			// Re-apply auth on redirects, unless the request was anonymous
+			if len(via) > 0 && via[0].Header.Get("Authorization") != "" {
+				c.setAuth(req)
+			}
+			return nil
 		},
 	}
+	return c
+}
+
+// defaultSettings supplies the retries and import concurrency of clients
+// whose connection does not set them; nil uses the built-in defaults.
+var defaultSettings *models.SettingsStore
+
+// UseSettings makes clients created afterwards fall back to the current
+// values in store. It is meant to be called once at startup.
+func UseSettings(store *models.SettingsStore) {
+	defaultSettings = store
+}
+
+// WithCache makes list requests revalidate pages stored in cache under the
+// given connection ID. It returns c for chaining.
+func (c *Client) WithCache(cache *ResponseCache, connID string) *Client {
+	c.cache = cache
+	c.cacheScope = connID
+	return c
+}
+
+// WithContext makes requests, and the waits for the rate limit and between
+// retries, return once ctx is done. It returns c for chaining.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c.ctx = ctx
+	return c
+}
+
+// newRequest creates a request bound to the client's context.
+func (c *Client) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return http.NewRequestWithContext(ctx, method, url, body)
+}
+
+// WriteConcurrency returns how many independent resources (e.g. the hosts of
+// one inventory) a migration may create at once; 1 or less means serially.
+func (c *Client) WriteConcurrency() int {
+	return c.writeWorkers
+}
+
+// CheckCACert reports whether the connection's ca_cert_file can be used.
+// Inline ca_cert takes precedence, in which case the file is not read.
+func CheckCACert(conn *models.Connection) error {
+	if conn.Insecure || conn.CACert != "" || conn.CACertFile == "" {
+		return nil
+	}
+	_, err := loadCACertFile(conn.CACertFile)
+	return err
+}
+
+// CheckClientCert reports whether the connection's client certificate and
+// key can be loaded and belong together.
+func CheckClientCert(conn *models.Connection) error {
+	_, err := loadClientCert(conn)
+	return err
+}
+
+// loadClientCert returns the connection's client certificate for mutual TLS,
+// or nil if it has none. client_cert and client_key each hold PEM data or
+// the path of a PEM file.
+func loadClientCert(conn *models.Connection) (*tls.Certificate, error) {
+	if conn.ClientCert == "" && conn.ClientKey == "" {
+		return nil, nil
+	}
+	if conn.ClientCert == "" || conn.ClientKey == "" {
+		return nil, fmt.Errorf("client_cert and client_key must be set together")
+	}
+	certPEM, err := pemOrFile(conn.ClientCert, "client certificate")
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := pemOrFile(conn.ClientKey, "client key")
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+// pemOrFile returns value if it holds PEM data, and otherwise the contents
+// of the file it names.
+func pemOrFile(value, what string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s file: %w", what, err)
+	}
+	return data, nil
+}
+
+// loadCACertFile reads a PEM bundle into a new cert pool.
+func loadCACertFile(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA file %s contains no valid PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// setAuth adds credentials to req, preferring an OAuth2 bearer token over
+// basic auth when the connection has one.
+func (c *Client) setAuth(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		return
+	}
+	req.SetBasicAuth(c.username, c.password)
+}
+
+// do sends req, retrying transient failures up to c.retries times with
+// exponential backoff. Idempotent requests are retried on any connection
+// error and on 429 and 502/503/504 responses. A POST the server may already
+// have seen is not safe to replay, so with idempotent unset only errors
+// from before the request was sent, such as a refused connection, are
+// retried. Backoff waits end early once the request's context is done.
+func (c *Client) do(req *http.Request, idempotent bool) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := c.send(req)
+		if attempt >= c.retries {
+			return resp, err
+		}
+		if err != nil {
+			if req.Context().Err() != nil || !idempotent && !notSent(err) {
+				return resp, err
+			}
+			if err := sleepCtx(req.Context(), c.backoff(attempt, "")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !idempotent || !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err := sleepCtx(req.Context(), c.backoff(attempt, resp.Header.Get("Retry-After"))); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// notSent reports whether err means the request never reached the server:
+// the connection could not be established.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// sleepCtx waits for d, or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// send performs a single request once the rate limiter allows it.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.dryRun != nil {
+		if resp := c.dryRun.answer(req); resp != nil {
+			return resp, nil
+		}
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	if c.metrics == nil {
+		return c.roundTrip(req)
+	}
+	start := time.Now()
+	resp, err := c.roundTrip(req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	c.metrics.observe(req.Method, status, time.Since(start))
+	return resp, err
+}
+
+// roundTrip sends req over the network, logging it when debugging is on.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	if c.debug != nil {
+		return c.debug.do(c.httpClient, req)
+	}
+	return c.httpClient.Do(req)
+}
+
+// retryableStatus reports whether an HTTP status is worth retrying.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before retry attempt+1, honoring a
+// Retry-After header (seconds or HTTP date) when the server sends one.
+func (c *Client) backoff(attempt int, retryAfter string) time.Duration {
+	wait := c.retryWait << attempt
+	if secs, err := strconv.Atoi(retryAfter); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(retryAfter); err == nil {
+		wait = time.Until(t)
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > retryMaxWait {
+		wait = retryMaxWait
+	}
+	return wait
 }
 
 // paginatedResponse is the standard AWX/AAP paginated response envelope.
@@ -56,30 +385,67 @@ type paginatedResponse struct {
 	Results []json.RawMessage `json:"results"`
 }
 
+// acceptEncoding is requested on GETs. Setting it explicitly turns off the
+// transport's own transparent gzip handling, so readBody decodes instead.
+const acceptEncoding = "gzip, deflate"
+
+// readBody reads a response body, decoding it according to Content-Encoding.
+func readBody(resp *http.Response) ([]byte, error) {
+	var r io.Reader = resp.Body
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decoding gzip: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	case "deflate":
+		zr, err := zlib.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decoding deflate: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+	return io.ReadAll(r)
+}
+
+// ErrNotFound is wrapped by the error of a GET that the API answered with
+// 404.
+var ErrNotFound = errors.New("HTTP 404")
+
 // Get performs an authenticated GET request and returns the response body.
 func (c *Client) Get(path string, params url.Values) ([]byte, error) {
 	u := c.baseURL + path
 	if len(params) > 0 {
 		u += "?" + params.Encode()
 	}
-	req, err := http.NewRequest("GET", u, nil)
+	req, err := c.newRequest("GET", u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
-	req.SetBasicAuth(c.username, c.password)
+	c.setAuth(req)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req, true)
 	if err != nil {
 		return nil, fmt.Errorf("GET %s: %w", path, err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("reading response: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusNotFound {
+		return body, fmt.Errorf("GET %s: %w: %s", path, ErrNotFound, truncate(string(body), 200))
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return body, fmt.Errorf("GET %s: HTTP %d: %s", path, resp.StatusCode, truncate(string(body), 200))
 	}
@@ -97,56 +463,158 @@ func (c *Client) GetJSON(path string, params url.Values, dest interface{}) error
 
 // GetAll fetches all pages of a paginated endpoint, returning all results.
 func (c *Client) GetAll(path string) ([]models.Resource, error) {
-	var all []models.Resource
-	currentURL := c.baseURL + path
+	return c.GetFiltered(path, nil, 0)
+}
 
+// GetFiltered fetches a paginated endpoint with the given query parameters.
+// If limit is positive, paging stops once that many results are collected.
+// Without a limit, connections with page_concurrency above 1 fetch the
+// remaining pages in parallel once the first page reveals their number.
+func (c *Client) GetFiltered(path string, params url.Values, limit int) ([]models.Resource, error) {
+	u := c.baseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	if c.pageWorkers > 1 && limit <= 0 {
+		return c.getPagesConcurrently(u)
+	}
+	return c.getPagesSerially(u, nil, limit)
+}
+
+// getPagesSerially follows next links from currentURL, appending results to
+// all.
+func (c *Client) getPagesSerially(currentURL string, all []models.Resource, limit int) ([]models.Resource, error) {
 	for currentURL != "" {
-		req, err := http.NewRequest("GET", currentURL, nil)
+		results, next, _, err := c.getPage(currentURL)
 		if err != nil {
-			return nil, fmt.Errorf("creating request: %w", err)
+			return nil, err
 		}
-		req.SetBasicAuth(c.username, c.password)
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("GET %s: %w", currentURL, err)
+		all = append(all, results...)
+		if limit > 0 && len(all) >= limit {
+			return all[:limit], nil
 		}
+		currentURL = next
+	}
+	return all, nil
+}
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("reading response: %w", err)
-		}
+// getPagesConcurrently reads the first page to learn the result count and
+// page size, then fetches the other pages with up to c.pageWorkers requests
+// in flight and returns all results in page order. Endpoints whose next link
+// is not a numeric ?page= are paged serially instead.
+func (c *Client) getPagesConcurrently(firstURL string) ([]models.Resource, error) {
+	first, next, count, err := c.getPage(firstURL)
+	if err != nil || next == "" {
+		return first, err
+	}
+	nextURL, err := url.Parse(next)
+	if err != nil || nextURL.Query().Get("page") != "2" || len(first) == 0 {
+		return c.getPagesSerially(next, first, 0)
+	}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return nil, fmt.Errorf("GET %s: HTTP %d: %s", currentURL, resp.StatusCode, truncate(string(body), 200))
+	pageSize := len(first)
+	pages := (count + pageSize - 1) / pageSize
+	if pages < 2 {
+		pages = 2
+	}
+	rest := make([][]models.Resource, pages-1) // pages 2..pages
+	errs := make([]error, pages-1)
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < c.pageWorkers && w < len(rest); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				pageURL := *nextURL
+				q := pageURL.Query()
+				q.Set("page", strconv.Itoa(i+2))
+				pageURL.RawQuery = q.Encode()
+				rest[i], _, _, errs[i] = c.getPage(pageURL.String())
+			}
+		}()
+	}
+	for i := range rest {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	all := make([]models.Resource, 0, count)
+	all = append(all, first...)
+	for i, results := range rest {
+		if errs[i] != nil {
+			return nil, errs[i]
 		}
+		all = append(all, results...)
+	}
+	return all, nil
+}
 
-		var page paginatedResponse
-		if err := json.Unmarshal(body, &page); err != nil {
-			return nil, fmt.Errorf("parsing response: %w", err)
+// getPage fetches one page of a paginated endpoint and returns its results,
+// the absolute URL of the next page ("" on the last page) and the total
+// result count reported by the server.
+func (c *Client) getPage(pageURL string) ([]models.Resource, string, int, error) {
+	req, err := c.newRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("creating request: %w", err)
+	}
+	c.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+
+	cacheKey := c.cacheScope + " " + pageURL
+	var cached cacheEntry
+	var isCached bool
+	if c.cache != nil {
+		if cached, isCached = c.cache.get(cacheKey); isCached {
+			req.Header.Set("If-None-Match", cached.etag)
 		}
+	}
 
-		for _, raw := range page.Results {
-			var res models.Resource
-			if err := json.Unmarshal(raw, &res); err != nil {
-				return nil, fmt.Errorf("parsing resource: %w", err)
-			}
-			all = append(all, res)
+	resp, err := c.do(req, true)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("GET %s: %w", pageURL, err)
+	}
+
+	body, err := readBody(resp)
+	resp.Body.Close()
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("reading response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && isCached:
+		body = cached.body
+	case resp.StatusCode >= 200 && resp.StatusCode < 300 && c.cache != nil:
+		c.cache.put(cacheKey, resp.Header.Get("ETag"), body)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, "", 0, fmt.Errorf("GET %s: HTTP %d: %s", pageURL, resp.StatusCode, truncate(string(body), 200))
+	}
+
+	var page paginatedResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, "", 0, fmt.Errorf("parsing response: %w", err)
+	}
+
+	results := make([]models.Resource, 0, len(page.Results))
+	for _, raw := range page.Results {
+		var res models.Resource
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return nil, "", 0, fmt.Errorf("parsing resource: %w", err)
 		}
+		results = append(results, res)
+	}
 
-		if page.Next != nil && *page.Next != "" {
-			currentURL = *page.Next
-			// If relative URL, make absolute
-			if len(currentURL) > 0 && currentURL[0] == '/' {
-				currentURL = c.baseURL + currentURL
-			}
-		} else {
-			currentURL = ""
+	var next string
+	if page.Next != nil && *page.Next != "" {
+		next = *page.Next
+		// If relative URL, make absolute
+		if next[0] == '/' {
+			next = c.baseURL + next
 		}
 	}
-	return all, nil
+	return results, next, page.Count, nil
 }
 
 // Post performs an authenticated POST request with a JSON body.
@@ -160,14 +628,14 @@ func (c *Client) Post(path string, payload interface{}) ([]byte, int, error) {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+path, bodyReader)
+	req, err := c.newRequest("POST", c.baseURL+path, bodyReader)
 	if err != nil {
 		return nil, 0, fmt.Errorf("creating request: %w", err)
 	}
-	req.SetBasicAuth(c.username, c.password)
+	c.setAuth(req)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req, false)
 	if err != nil {
 		return nil, 0, fmt.Errorf("POST %s: %w", path, err)
 	}
@@ -179,7 +647,7 @@ func (c *Client) Post(path string, payload interface{}) ([]byte, int, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return body, resp.StatusCode, fmt.Errorf("POST %s: HTTP %d: %s", path, resp.StatusCode, truncate(string(body), 200))
+		return body, resp.StatusCode, fmt.Errorf("POST %s: HTTP %d: %s", path, resp.StatusCode, truncate(string(body), errorBodyLimit))
 	}
 	return body, resp.StatusCode, nil
 }
@@ -195,14 +663,14 @@ func (c *Client) Patch(path string, payload interface{}) ([]byte, int, error) {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest("PATCH", c.baseURL+path, bodyReader)
+	req, err := c.newRequest("PATCH", c.baseURL+path, bodyReader)
 	if err != nil {
 		return nil, 0, fmt.Errorf("creating request: %w", err)
 	}
-	req.SetBasicAuth(c.username, c.password)
+	c.setAuth(req)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("PATCH %s: %w", path, err)
 	}
@@ -214,20 +682,20 @@ func (c *Client) Patch(path string, payload interface{}) ([]byte, int, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return body, resp.StatusCode, fmt.Errorf("PATCH %s: HTTP %d: %s", path, resp.StatusCode, truncate(string(body), 200))
+		return body, resp.StatusCode, fmt.Errorf("PATCH %s: HTTP %d: %s", path, resp.StatusCode, truncate(string(body), errorBodyLimit))
 	}
 	return body, resp.StatusCode, nil
 }
 
 // Delete performs an authenticated DELETE request.
 func (c *Client) Delete(path string) error {
-	req, err := http.NewRequest("DELETE", c.baseURL+path, nil)
+	req, err := c.newRequest("DELETE", c.baseURL+path, nil)
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
 	}
-	req.SetBasicAuth(c.username, c.password)
+	c.setAuth(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return fmt.Errorf("DELETE %s: %w", path, err)
 	}
@@ -244,46 +712,37 @@ func (c *Client) Delete(path string) error {
 	}
 }
 
-// FindByName searches for a resource by name at the given API path.
+// FindByName searches for a resource by name at the given API path. Some
+// AWX versions match the name filter loosely, so only a result whose name
+// is exactly name is returned; nil means there is none.
 func (c *Client) FindByName(path, name string) (models.Resource, error) {
-	params := url.Values{"name": {name}}
-	body, err := c.Get(path, params)
-	if err != nil {
-		return nil, err
-	}
-	var page paginatedResponse
-	if err := json.Unmarshal(body, &page); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
-	}
-	if len(page.Results) == 0 {
-		return nil, nil
-	}
-	var res models.Resource
-	if err := json.Unmarshal(page.Results[0], &res); err != nil {
-		return nil, fmt.Errorf("parsing resource: %w", err)
-	}
-	return res, nil
+	return c.findExact(path, "name", name)
 }
 
-// FindByUsername searches for a user by username at the given API path.
+// FindByUsername searches for a user by username at the given API path,
+// returning only an exact match.
 func (c *Client) FindByUsername(path, username string) (models.Resource, error) {
-	params := url.Values{"username": {username}}
-	body, err := c.Get(path, params)
-	if err != nil {
-		return nil, err
-	}
-	var page paginatedResponse
-	if err := json.Unmarshal(body, &page); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
-	}
-	if len(page.Results) == 0 {
-		return nil, nil
-	}
-	var res models.Resource
-	if err := json.Unmarshal(page.Results[0], &res); err != nil {
-		return nil, fmt.Errorf("parsing resource: %w", err)
+	return c.findExact(path, "username", username)
+}
+
+// findExact filters path by field=value and returns the first result whose
+// field equals value, ignoring near matches the filter let through. Near
+// matches can fill whole pages, so later pages are read until one matches.
+func (c *Client) findExact(path, field, value string) (models.Resource, error) {
+	pageURL := c.baseURL + path + "?" + url.Values{field: {value}}.Encode()
+	for pageURL != "" {
+		results, next, _, err := c.getPage(pageURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range results {
+			if got, _ := res[field].(string); got == value {
+				return res, nil
+			}
+		}
+		pageURL = next
 	}
-	return res, nil
+	return nil, nil
 }
 
 // Ping checks connectivity by hitting the API root.
@@ -292,6 +751,34 @@ func (c *Client) Ping(apiPath string) error {
 	return err
 }
 
+// PingAnonymous checks that the API answers at apiPath without sending
+// credentials. 401 and 403 count as reachable, so wrong credentials are
+// reported by the auth check rather than as a connectivity failure.
+func (c *Client) PingAnonymous(apiPath string) error {
+	req, err := c.newRequest("GET", c.baseURL+apiPath, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.do(req, true)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", apiPath, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300,
+		resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden:
+		return nil
+	}
+	return fmt.Errorf("GET %s: HTTP %d", apiPath, resp.StatusCode)
+}
+
+// errorBodyLimit caps the response body quoted in POST and PATCH errors. It
+// is generous so field-level validation messages survive.
+const errorBodyLimit = 2048
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -1,12 +1,38 @@
 package platform
 
 import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+	"golang.org/x/time/rate"
 )
 
 func newTestClient(ts *httptest.Server) *Client {
@@ -100,6 +126,48 @@ func TestClient_GetAll_Pagination(t *testing.T) {
 	}
 }
 
+func TestClient_GetAll_Gzip(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		json.NewEncoder(gz).Encode(map[string]interface{}{
+			"count": 2, "next": nil,
+			"results": []interface{}{map[string]interface{}{"id": 1, "name": "Org1"}, map[string]interface{}{"id": 2, "name": "Org2"}},
+		})
+		gz.Close()
+	}))
+	defer ts.Close()
+
+	results, err := newTestClient(ts).GetAll("/api/v2/orgs/")
+	if err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if len(results) != 2 || results[1]["name"] != "Org2" {
+		t.Errorf("GetAll = %v, want Org1 and Org2", results)
+	}
+}
+
+func TestClient_Get_Deflate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "deflate")
+		zw := zlib.NewWriter(w)
+		zw.Write([]byte(`{"version":"4.5.0"}`))
+		zw.Close()
+	}))
+	defer ts.Close()
+
+	body, err := newTestClient(ts).Get("/api/v2/ping/", nil)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if string(body) != `{"version":"4.5.0"}` {
+		t.Errorf("body = %q, want decoded JSON", body)
+	}
+}
+
 func TestClient_Post(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
@@ -168,6 +236,68 @@ func TestClient_Ping(t *testing.T) {
 	}
 }
 
+func TestClient_PingAnonymous(t *testing.T) {
+	var sawAuth bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			sawAuth = true
+		}
+		switch r.URL.Path {
+		case "/api/v2/ping/":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/api/v2/forbidden/":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := newTestClient(ts)
+	if err := c.PingAnonymous("/api/v2/ping/"); err != nil {
+		t.Errorf("PingAnonymous on 401 = %v, want reachable", err)
+	}
+	if err := c.PingAnonymous("/api/v2/forbidden/"); err != nil {
+		t.Errorf("PingAnonymous on 403 = %v, want reachable", err)
+	}
+	if err := c.PingAnonymous("/api/v2/missing/"); err == nil {
+		t.Error("PingAnonymous on 404 succeeded")
+	}
+	if sawAuth {
+		t.Error("PingAnonymous sent an Authorization header")
+	}
+}
+
+func TestClient_PingAnonymous_ConnectionRefused(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	c := newTestClient(ts)
+	ts.Close()
+
+	if err := c.PingAnonymous("/api/v2/ping/"); err == nil {
+		t.Error("PingAnonymous against a closed server succeeded")
+	}
+}
+
+func TestCheckHealth_WrongPasswordIsAuthFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, ok := r.BasicAuth(); !ok || pass != "right" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	conn := &models.Connection{Type: "awx", Scheme: "http", Host: u.Hostname(), Port: port,
+		Username: "admin", Password: "wrong", MaxRetries: -1}
+	ping, _, auth, _ := CheckHealth(conn)
+	if ping != "ok" || auth != "error" {
+		t.Errorf("ping = %s, auth = %s; want ok, error", ping, auth)
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -224,3 +354,754 @@ func TestNewClient(t *testing.T) {
 		t.Error("credentials not set correctly")
 	}
 }
+
+// authServer serves a two-page list and a name lookup, recording the
+// Authorization header of every request it sees.
+func authServer(t *testing.T, seen *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = append(*seen, r.Header.Get("Authorization"))
+		switch {
+		case r.URL.Query().Get("name") != "":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":7,"name":"Default"}]}`))
+		case r.URL.Query().Get("page") == "2":
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":2}]}`))
+		default:
+			w.Write([]byte(`{"count":2,"next":"/api/v2/items/?page=2","results":[{"id":1}]}`))
+		}
+	}))
+}
+
+func TestClient_BearerToken(t *testing.T) {
+	var seen []string
+	ts := authServer(t, &seen)
+	defer ts.Close()
+
+	c := &Client{baseURL: ts.URL, username: "admin", password: "secret", token: "tok123", httpClient: ts.Client()}
+	if _, err := c.GetAll("/api/v2/items/"); err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if _, err := c.FindByName("/api/v2/organizations/", "Default"); err != nil {
+		t.Fatalf("FindByName returned error: %v", err)
+	}
+	if len(seen) != 3 {
+		t.Fatalf("got %d requests, want 3", len(seen))
+	}
+	for i, h := range seen {
+		if h != "Bearer tok123" {
+			t.Errorf("request %d Authorization = %q, want %q", i, h, "Bearer tok123")
+		}
+	}
+}
+
+func TestClient_BasicAuthWithoutToken(t *testing.T) {
+	var seen []string
+	ts := authServer(t, &seen)
+	defer ts.Close()
+
+	c := newTestClient(ts)
+	if _, err := c.GetAll("/api/v2/items/"); err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if _, err := c.FindByName("/api/v2/organizations/", "Default"); err != nil {
+		t.Fatalf("FindByName returned error: %v", err)
+	}
+	for i, h := range seen {
+		if !strings.HasPrefix(h, "Basic ") {
+			t.Errorf("request %d Authorization = %q, want basic auth", i, h)
+		}
+	}
+}
+
+func TestNewClient_TokenReappliedOnRedirect(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old/" {
+			http.Redirect(w, r, "/new/", http.StatusMovedPermanently)
+			return
+		}
+		got = r.Header.Get("Authorization")
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	c := NewClient(&models.Connection{Scheme: "http", Host: u.Hostname(), Port: port, Token: "tok123"})
+	if _, err := c.Get("/old/", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if got != "Bearer tok123" {
+		t.Errorf("Authorization after redirect = %q, want %q", got, "Bearer tok123")
+	}
+}
+
+func TestNewClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	c := NewClient(&models.Connection{Scheme: "http", Host: u.Hostname(), Port: port, Timeout: 50 * time.Millisecond, MaxRetries: -1})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.GetAll("/api/v2/items/")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected timeout error, got nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetAll did not time out")
+	}
+}
+
+func TestNewClient_DefaultTimeout(t *testing.T) {
+	c := NewClient(&models.Connection{Scheme: "https", Host: "example.com", Port: 443})
+	if c.httpClient.Timeout != DefaultTimeout {
+		t.Errorf("Timeout = %v, want %v", c.httpClient.Timeout, DefaultTimeout)
+	}
+}
+
+// flakyServer answers the first `failures` requests with status and then 200.
+func flakyServer(failures, status int, body string, calls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if *calls <= failures {
+			if status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func newRetryClient(ts *httptest.Server) *Client {
+	c := newTestClient(ts)
+	c.retries = 3
+	c.retryWait = time.Millisecond
+	return c
+}
+
+func TestClient_Get_RetriesTransient(t *testing.T) {
+	var calls int
+	ts := flakyServer(2, http.StatusServiceUnavailable, `{"status":"ok"}`, &calls)
+	defer ts.Close()
+
+	body, err := newRetryClient(ts).Get("/api/v2/ping/", nil)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if string(body) != `{"status":"ok"}` {
+		t.Errorf("body = %q", body)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestClient_GetAll_RetriesTooManyRequests(t *testing.T) {
+	var calls int
+	ts := flakyServer(2, http.StatusTooManyRequests, `{"count":1,"next":null,"results":[{"id":1}]}`, &calls)
+	defer ts.Close()
+
+	items, err := newRetryClient(ts).GetAll("/api/v2/items/")
+	if err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if len(items) != 1 || calls != 3 {
+		t.Errorf("got %d items after %d calls, want 1 after 3", len(items), calls)
+	}
+}
+
+func TestClient_Get_RetriesExhausted(t *testing.T) {
+	var calls int
+	ts := flakyServer(10, http.StatusBadGateway, "{}", &calls)
+	defer ts.Close()
+
+	if _, err := newRetryClient(ts).Get("/api/v2/ping/", nil); err == nil {
+		t.Fatal("expected error after retries exhausted, got nil")
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4 (1 + 3 retries)", calls)
+	}
+}
+
+func TestClient_Post_NoRetryOnStatus(t *testing.T) {
+	var calls int
+	ts := flakyServer(2, http.StatusServiceUnavailable, `{"id":1}`, &calls)
+	defer ts.Close()
+
+	_, status, err := newRetryClient(ts).Post("/api/v2/items/", map[string]string{"name": "x"})
+	if err == nil || status != http.StatusServiceUnavailable {
+		t.Errorf("Post = (%d, %v), want 503 error", status, err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClient_Post_RetriesDialError(t *testing.T) {
+	var gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer ts.Close()
+
+	var dials int
+	c := newRetryClient(ts)
+	c.httpClient = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials++
+			if dials == 1 {
+				return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+			}
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	_, status, err := c.Post("/api/v2/items/", map[string]string{"name": "x"})
+	if err != nil || status != http.StatusCreated {
+		t.Fatalf("Post = (%d, %v), want 201", status, err)
+	}
+	if gotBody != `{"name":"x"}` {
+		t.Errorf("retried body = %q, want %q", gotBody, `{"name":"x"}`)
+	}
+}
+
+func TestClient_Post_NoRetryAfterSend(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer ts.Close()
+
+	if _, _, err := newRetryClient(ts).Post("/api/v2/items/", map[string]string{"name": "x"}); err == nil {
+		t.Fatal("Post returned no error for a dropped connection")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1: the server may have created the object", n)
+	}
+}
+
+func TestClient_BackoffHonorsContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newRetryClient(ts).WithContext(ctx)
+	c.retryWait = time.Minute
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := c.Get("/api/v2/ping/", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get kept backing off for %v after cancel", elapsed)
+	}
+}
+
+func TestClient_Backoff(t *testing.T) {
+	c := &Client{retryWait: 100 * time.Millisecond}
+	if got := c.backoff(2, ""); got != 400*time.Millisecond {
+		t.Errorf("backoff(2) = %v, want 400ms", got)
+	}
+	if got := c.backoff(0, "5"); got != 5*time.Second {
+		t.Errorf("backoff with Retry-After 5 = %v, want 5s", got)
+	}
+	if got := c.backoff(0, "3600"); got != retryMaxWait {
+		t.Errorf("backoff with large Retry-After = %v, want %v", got, retryMaxWait)
+	}
+}
+
+func TestListResourcesFiltered_ForwardsQuery(t *testing.T) {
+	var got url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/hosts/" {
+			t.Errorf("path = %s, want /api/v2/hosts/", r.URL.Path)
+		}
+		got = r.URL.Query()
+		w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"web1"}]}`))
+	}))
+	defer ts.Close()
+
+	p := NewAWXPlatform(newTestClient(ts))
+	results, err := p.ListResourcesFiltered("hosts", ListFilter{Search: "web", Name: "web1", PageSize: 50})
+	if err != nil {
+		t.Fatalf("ListResourcesFiltered returned error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	for key, want := range map[string]string{"search": "web", "name": "web1", "page_size": "50"} {
+		if got.Get(key) != want {
+			t.Errorf("query %s = %q, want %q", key, got.Get(key), want)
+		}
+	}
+}
+
+func TestListResourcesFiltered_NoFilter(t *testing.T) {
+	var rawQuery string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	p := NewAAPPlatform(newTestClient(ts))
+	if _, err := p.ListResourcesFiltered("organizations", ListFilter{}); err != nil {
+		t.Fatalf("ListResourcesFiltered returned error: %v", err)
+	}
+	if rawQuery != "" {
+		t.Errorf("query = %q, want none", rawQuery)
+	}
+}
+
+func TestClient_GetFiltered_StopsAtLimit(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"count":6,"next":"/api/v2/hosts/?page=2","results":[{"id":1},{"id":2},{"id":3}]}`))
+	}))
+	defer ts.Close()
+
+	c := newTestClient(ts)
+	results, err := c.GetFiltered("/api/v2/hosts/", url.Values{"page_size": {"2"}}, 2)
+	if err != nil {
+		t.Fatalf("GetFiltered returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("got %d results, want 2", len(results))
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d pages, want 1", calls)
+	}
+}
+
+// pagedServer serves total numbered results, pageSize per page, via
+// ?page=N. Later pages respond faster so that out-of-order completion is
+// exercised. It records the highest number of requests in flight.
+func pagedServer(total, pageSize int, numeric bool, maxInFlight *int32) *httptest.Server {
+	var inFlight int32
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		mu.Lock()
+		if n > *maxInFlight {
+			*maxInFlight = n
+		}
+		mu.Unlock()
+
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		} else if c := r.URL.Query().Get("cursor"); c != "" {
+			page, _ = strconv.Atoi(c)
+		}
+		pages := (total + pageSize - 1) / pageSize
+		time.Sleep(time.Duration(pages-page) * 2 * time.Millisecond)
+
+		var results []map[string]interface{}
+		for id := (page-1)*pageSize + 1; id <= page*pageSize && id <= total; id++ {
+			results = append(results, map[string]interface{}{"id": id})
+		}
+		var next interface{}
+		if page < pages {
+			if numeric {
+				next = fmt.Sprintf("/api/v2/hosts/?page=%d&page_size=%d", page+1, pageSize)
+			} else {
+				next = fmt.Sprintf("/api/v2/hosts/?cursor=%d", page+1)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": total, "next": next, "results": results})
+	}))
+}
+
+func TestClient_GetAll_ConcurrentPages(t *testing.T) {
+	var maxInFlight int32
+	ts := pagedServer(95, 10, true, &maxInFlight)
+	defer ts.Close()
+
+	c := newTestClient(ts)
+	c.pageWorkers = 4
+	results, err := c.GetAll("/api/v2/hosts/")
+	if err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if len(results) != 95 {
+		t.Fatalf("got %d results, want 95", len(results))
+	}
+	for i, r := range results {
+		if got := int(r["id"].(float64)); got != i+1 {
+			t.Fatalf("results[%d].id = %d, want %d", i, got, i+1)
+		}
+	}
+	if maxInFlight < 2 || maxInFlight > 4 {
+		t.Errorf("max requests in flight = %d, want 2..4", maxInFlight)
+	}
+}
+
+func TestClient_GetAll_ConcurrentFallsBackToSerial(t *testing.T) {
+	var maxInFlight int32
+	ts := pagedServer(25, 10, false, &maxInFlight)
+	defer ts.Close()
+
+	c := newTestClient(ts)
+	c.pageWorkers = 4
+	results, err := c.GetAll("/api/v2/hosts/")
+	if err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if len(results) != 25 || int(results[24]["id"].(float64)) != 25 {
+		t.Fatalf("got %d results, want 25 in order", len(results))
+	}
+	if maxInFlight != 1 {
+		t.Errorf("max requests in flight = %d, want 1 for a cursor-paged endpoint", maxInFlight)
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	c := newTestClient(ts)
+	c.limiter = rate.NewLimiter(2, 1)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := c.Get("/api/v2/ping/", nil); err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+	}
+	// The first request goes out at once, the next three 500ms apart.
+	if elapsed := time.Since(start); elapsed < 1500*time.Millisecond || elapsed > 3*time.Second {
+		t.Errorf("4 requests at 2 rps took %v, want about 1.5s", elapsed)
+	}
+}
+
+func TestNewClient_RateLimitSharedPerConnection(t *testing.T) {
+	conn := &models.Connection{ID: "conn-rl", Scheme: "http", Host: "localhost", Port: 80, RateLimit: 2}
+	a, b := NewClient(conn), NewClient(conn)
+	if a.limiter == nil || a.limiter != b.limiter {
+		t.Fatal("clients of one connection should share a limiter")
+	}
+	conn.RateLimit = 5
+	if c := NewClient(conn); c.limiter != a.limiter || c.limiter.Limit() != 5 {
+		t.Error("a changed rate_limit should apply to the shared limiter")
+	}
+	other := &models.Connection{ID: "conn-other", Scheme: "http", Host: "localhost", Port: 80, RateLimit: 5}
+	if NewClient(other).limiter == NewClient(conn).limiter {
+		t.Error("connections should not share a limiter")
+	}
+}
+
+func TestClient_RateLimitWaitHonorsContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newTestClient(ts).WithContext(ctx)
+	c.limiter = rate.NewLimiter(0.1, 1)
+	if _, err := c.Get("/api/v2/ping/", nil); err != nil {
+		t.Fatalf("first Get returned error: %v", err)
+	}
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := c.Get("/api/v2/ping/", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Get after cancel = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get waited %v for the rate limit after cancel", elapsed)
+	}
+}
+
+func TestForgetConnection_DropsLimiter(t *testing.T) {
+	conn := &models.Connection{ID: "conn-forget", Scheme: "http", Host: "localhost", Port: 80, RateLimit: 2}
+	before := NewClient(conn).limiter
+	ForgetConnection(conn.ID)
+	limiters.Lock()
+	_, kept := limiters.byConn[conn.ID]
+	limiters.Unlock()
+	if kept {
+		t.Error("limiter of a forgotten connection is still registered")
+	}
+	if NewClient(conn).limiter == before {
+		t.Error("a new client reused the forgotten limiter")
+	}
+}
+
+func TestNewClient_RateLimitDefaultUnlimited(t *testing.T) {
+	c := NewClient(&models.Connection{Scheme: "http", Host: "localhost", Port: 80})
+	if c.limiter != nil {
+		t.Error("limiter should be nil when rate_limit is unset")
+	}
+}
+
+// writeCAFile PEM-encodes the TLS test server's certificate into a temp file.
+func writeCAFile(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("writing CA file: %v", err)
+	}
+	return path
+}
+
+// tlsConnection returns a connection to ts that verifies TLS with caFile.
+func tlsConnection(t *testing.T, ts *httptest.Server, caFile string) *models.Connection {
+	t.Helper()
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	return &models.Connection{
+		Scheme:     "https",
+		Host:       u.Hostname(),
+		Port:       port,
+		Username:   "admin",
+		Password:   "secret",
+		CACertFile: caFile,
+		MaxRetries: -1,
+	}
+}
+
+func TestNewClient_CACertFile(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	conn := tlsConnection(t, ts, writeCAFile(t, ts))
+	if err := CheckCACert(conn); err != nil {
+		t.Fatalf("CheckCACert returned error: %v", err)
+	}
+	if _, err := NewClient(conn).Get("/api/v2/ping/", nil); err != nil {
+		t.Fatalf("Get with CA file returned error: %v", err)
+	}
+
+	// Without the CA file the self-signed server must be rejected.
+	conn.CACertFile = ""
+	if _, err := NewClient(conn).Get("/api/v2/ping/", nil); err == nil {
+		t.Error("Get without CA file should fail certificate verification")
+	}
+}
+
+func TestNewClient_CACertFileNoValidCerts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(path, []byte("not a certificate"), 0600)
+	conn := &models.Connection{Scheme: "https", Host: "example.com", Port: 443, CACertFile: path}
+
+	err := CheckCACert(conn)
+	if err == nil || !strings.Contains(err.Error(), "no valid PEM certificates") {
+		t.Fatalf("CheckCACert error = %v, want no valid PEM certificates", err)
+	}
+	if _, err := NewClient(conn).Get("/api/v2/ping/", nil); err == nil || !strings.Contains(err.Error(), "no valid PEM certificates") {
+		t.Errorf("Get error = %v, want the CA file error", err)
+	}
+}
+
+func TestNewClient_CACertFileMissing(t *testing.T) {
+	conn := &models.Connection{Scheme: "https", Host: "example.com", Port: 443,
+		CACertFile: filepath.Join(t.TempDir(), "missing.pem")}
+	if err := CheckCACert(conn); err == nil || !strings.Contains(err.Error(), "reading CA file") {
+		t.Errorf("CheckCACert error = %v, want reading CA file error", err)
+	}
+}
+
+// newClientCert returns a self-signed CA and a PEM client certificate and key
+// it issued.
+func newClientCert(t *testing.T) (ca *x509.Certificate, certPEM, keyPEM []byte) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating CA key: %v", err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("creating CA certificate: %v", err)
+	}
+	ca, _ = x509.ParseCertificate(caDER)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating client key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "workbench"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("creating client certificate: %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestNewClient_ClientCert(t *testing.T) {
+	ca, certPEM, keyPEM := newClientCert(t)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0) // rejected handshakes
+	ts.StartTLS()
+	defer ts.Close()
+
+	// Inline PEM.
+	conn := tlsConnection(t, ts, writeCAFile(t, ts))
+	conn.ClientCert, conn.ClientKey = string(certPEM), string(keyPEM)
+	if err := CheckClientCert(conn); err != nil {
+		t.Fatalf("CheckClientCert returned error: %v", err)
+	}
+	if _, err := NewClient(conn).Get("/api/v2/ping/", nil); err != nil {
+		t.Fatalf("Get with inline client certificate returned error: %v", err)
+	}
+
+	// Files.
+	dir := t.TempDir()
+	conn.ClientCert, conn.ClientKey = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	os.WriteFile(conn.ClientCert, certPEM, 0600)
+	os.WriteFile(conn.ClientKey, keyPEM, 0600)
+	if _, err := NewClient(conn).Get("/api/v2/ping/", nil); err != nil {
+		t.Fatalf("Get with client certificate files returned error: %v", err)
+	}
+
+	// The server refuses clients without a certificate.
+	conn.ClientCert, conn.ClientKey = "", ""
+	if _, err := NewClient(conn).Get("/api/v2/ping/", nil); err == nil {
+		t.Error("Get without a client certificate should be rejected")
+	}
+}
+
+func TestNewClient_ClientCertMismatch(t *testing.T) {
+	_, certPEM, _ := newClientCert(t)
+	_, _, otherKey := newClientCert(t)
+	conn := &models.Connection{Scheme: "https", Host: "example.com", Port: 443,
+		ClientCert: string(certPEM), ClientKey: string(otherKey)}
+
+	err := CheckClientCert(conn)
+	if err == nil || !strings.Contains(err.Error(), "private key does not match public key") {
+		t.Fatalf("CheckClientCert error = %v, want key mismatch", err)
+	}
+	if _, err := NewClient(conn).Get("/api/v2/ping/", nil); err == nil || !strings.Contains(err.Error(), "loading client certificate") {
+		t.Errorf("Get error = %v, want the client certificate error", err)
+	}
+
+	conn.ClientKey = filepath.Join(t.TempDir(), "missing.key")
+	if err := CheckClientCert(conn); err == nil || !strings.Contains(err.Error(), "reading client key file") {
+		t.Errorf("CheckClientCert error = %v, want reading client key file error", err)
+	}
+	conn.ClientKey = ""
+	if err := CheckClientCert(conn); err == nil || !strings.Contains(err.Error(), "set together") {
+		t.Errorf("CheckClientCert error = %v, want client_cert without client_key rejected", err)
+	}
+}
+
+func TestNewClient_UsesSettingsDefaults(t *testing.T) {
+	store := models.NewSettingsStore(models.Settings{MaxRetries: 5, ImportConcurrency: 4})
+	UseSettings(store)
+	defer UseSettings(nil)
+
+	c := NewClient(&models.Connection{Scheme: "https", Host: "aap.lab.local", Port: 443})
+	if c.retries != 5 || c.WriteConcurrency() != 4 {
+		t.Errorf("retries/write concurrency = %d/%d, want 5/4 from settings", c.retries, c.WriteConcurrency())
+	}
+
+	store.Update(models.Settings{MaxRetries: -1, ImportConcurrency: 8})
+	c = NewClient(&models.Connection{Scheme: "https", Host: "aap.lab.local", Port: 443, ImportConcurrency: 2})
+	if c.retries != 0 || c.WriteConcurrency() != 2 {
+		t.Errorf("retries/write concurrency = %d/%d, want 0 (disabled) and the connection's 2", c.retries, c.WriteConcurrency())
+	}
+}
+
+func TestClient_FindByName_ExactMatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("name"); got != "Deploy" {
+			t.Errorf("name filter = %q, want Deploy", got)
+		}
+		// A loose filter lets near matches through, ahead of the exact one.
+		w.Write([]byte(`{"count":3,"next":null,"results":[
+			{"id":1,"name":"Deploy App"},{"id":2,"name":"deploy"},{"id":3,"name":"Deploy"}]}`))
+	}))
+	defer ts.Close()
+	c := newTestClient(ts)
+
+	res, err := c.FindByName("/api/v2/job_templates/", "Deploy")
+	if err != nil {
+		t.Fatalf("FindByName returned error: %v", err)
+	}
+	if res == nil || res["id"] != float64(3) {
+		t.Errorf("FindByName = %v, want the exact match with id 3", res)
+	}
+
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Deploy App"}]}`))
+	})
+	res, err = c.FindByName("/api/v2/job_templates/", "Deploy")
+	if err != nil || res != nil {
+		t.Errorf("FindByName with only near matches = %v, %v; want nil, nil", res, err)
+	}
+}
+
+func TestClient_FindByName_FollowsPages(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("name"); got != "Deploy" {
+			t.Errorf("name filter = %q, want Deploy", got)
+		}
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`{"count":3,"next":null,"results":[{"id":3,"name":"Deploy"}]}`))
+			return
+		}
+		next := ts.URL + "/api/v2/job_templates/?name=Deploy&page=2"
+		w.Write([]byte(`{"count":3,"next":"` + next + `","results":[
+			{"id":1,"name":"Deploy App"},{"id":2,"name":"deploy"}]}`))
+	}))
+	defer ts.Close()
+
+	res, err := newTestClient(ts).FindByName("/api/v2/job_templates/", "Deploy")
+	if err != nil {
+		t.Fatalf("FindByName returned error: %v", err)
+	}
+	if res == nil || res["id"] != float64(3) {
+		t.Errorf("FindByName = %v, want the match on page 2", res)
+	}
+}
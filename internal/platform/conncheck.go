@@ -0,0 +1,109 @@
+package platform
+
+import (
+	"sync"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// DefaultCheckConcurrency bounds how many connections CheckConnections
+// tests at once.
+const DefaultCheckConcurrency = 8
+
+// CheckResult is the outcome of CheckConnection for one connection.
+type CheckResult struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	PingOK    bool   `json:"ping_ok"`
+	PingError string `json:"ping_error"`
+	AuthOK    bool   `json:"auth_ok"`
+	AuthError string `json:"auth_error"`
+	Version   string `json:"version"`
+}
+
+// CheckConnection pings a connection, verifies its credentials and, once
+// both succeed, discovers its version and API prefix. Health and discovery
+// results are recorded in store.
+func CheckConnection(conn *models.Connection, store *models.ConnectionStore) CheckResult {
+	pingStatus, pingError, authStatus, authError := CheckHealth(conn)
+
+	version, _, _ := store.Versions(conn.ID)
+	if authStatus == "ok" {
+		if info, err := RefreshVersion(conn, store); err == nil {
+			version = info.Version
+		}
+	}
+
+	store.SetHealth(conn.ID, pingStatus, pingError, authStatus, authError)
+	return CheckResult{
+		ID:        conn.ID,
+		Name:      conn.Name,
+		PingOK:    pingStatus == "ok",
+		PingError: pingError,
+		AuthOK:    authStatus == "ok",
+		AuthError: authError,
+		Version:   version,
+	}
+}
+
+// VersionInfo is what RefreshVersion discovered about a connection.
+type VersionInfo struct {
+	ID             string `json:"id"`
+	Version        string `json:"version"`
+	GatewayVersion string `json:"gateway_version"`
+	APIPrefix      string `json:"api_prefix"`
+}
+
+// RefreshVersion re-reads a connection's version from its ping endpoint and
+// re-runs API discovery, recording the results in store. Health status is
+// left untouched. It fails only if no ping endpoint answers.
+func RefreshVersion(conn *models.Connection, store *models.ConnectionStore) (VersionInfo, error) {
+	client := NewClient(conn)
+	var pingResp *PingResponse
+	var err error
+	for _, pp := range PingPaths(conn.Type) {
+		pingResp, err = client.PingWithVersion(pp)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	if pingResp.Version != "" {
+		// Keep the known prefix in case discovery below fails.
+		_, _, prefix := store.Versions(conn.ID)
+		store.SetVersion(conn.ID, pingResp.Version, prefix)
+	}
+	DiscoverAndStore(client, conn, store)
+	info := VersionInfo{ID: conn.ID}
+	info.Version, info.GatewayVersion, info.APIPrefix = store.Versions(conn.ID)
+	return info, nil
+}
+
+// CheckConnections runs CheckConnection for every connection, at most
+// workers at a time, and returns the results in the order of conns.
+// A non-positive workers uses DefaultCheckConcurrency.
+func CheckConnections(conns []*models.Connection, store *models.ConnectionStore, workers int) []CheckResult {
+	if workers <= 0 {
+		workers = DefaultCheckConcurrency
+	}
+	results := make([]CheckResult, len(conns))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = CheckConnection(conns[i], store)
+			}
+		}()
+	}
+	for i := range conns {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,144 @@
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestCheckConnections(t *testing.T) {
+	reachable := authServer(t, new([]string))
+	defer reachable.Close()
+	authFail := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/ping/" {
+			w.Write([]byte(`{"version":"23.4.0"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer authFail.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	store := models.NewConnectionStore()
+	var conns []*models.Connection
+	for _, ts := range []*httptest.Server{reachable, authFail, unreachable} {
+		u, _ := url.Parse(ts.URL)
+		port, _ := strconv.Atoi(u.Port())
+		conn := &models.Connection{Type: "awx", Scheme: "http", Host: u.Hostname(), Port: port,
+			Username: "admin", Password: "secret", MaxRetries: -1}
+		store.Create(conn)
+		conns = append(conns, conn)
+	}
+
+	results := CheckConnections(conns, store, 2)
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for i, r := range results {
+		if r.ID != conns[i].ID {
+			t.Errorf("result %d is for %s, want %s", i, r.ID, conns[i].ID)
+		}
+	}
+	if r := results[0]; !r.PingOK || !r.AuthOK {
+		t.Errorf("reachable: %+v, want ping and auth ok", r)
+	}
+	if r := results[1]; !r.PingOK || r.AuthOK || r.AuthError == "" {
+		t.Errorf("auth failure: %+v, want ping ok and an auth error", r)
+	}
+	if r := results[2]; r.PingOK || r.AuthOK || !strings.Contains(r.PingError, "connection refused") {
+		t.Errorf("unreachable: %+v, want a connection error", r)
+	}
+
+	// Health is recorded on the stored connections too.
+	if got := store.Get(conns[1].ID).AuthStatus; got != "error" {
+		t.Errorf("stored auth status = %q, want error", got)
+	}
+}
+
+func TestRefreshVersion_PicksUpUpgrade(t *testing.T) {
+	versions := []string{"23.4.0", "24.6.1"}
+	var pings int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ping/":
+			v := versions[pings]
+			if pings < len(versions)-1 {
+				pings++
+			}
+			w.Write([]byte(`{"version":"` + v + `"}`))
+		case "/api/":
+			w.Write([]byte(`{"current_version":"/api/v2/"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	store := models.NewConnectionStore()
+	conn := &models.Connection{Type: "awx", Scheme: "http", Host: u.Hostname(), Port: port,
+		Username: "admin", Password: "secret", MaxRetries: -1}
+	store.Create(conn)
+
+	for _, want := range versions {
+		info, err := RefreshVersion(conn, store)
+		if err != nil {
+			t.Fatalf("RefreshVersion returned error: %v", err)
+		}
+		if info.Version != want || info.APIPrefix != "/api/v2/" {
+			t.Errorf("info = %+v, want version %s and prefix /api/v2/", info, want)
+		}
+		if got := store.Get(conn.ID).Version; got != want {
+			t.Errorf("stored version = %q, want %q", got, want)
+		}
+	}
+	if store.Get(conn.ID).LastChecked != nil {
+		t.Error("RefreshVersion updated health status")
+	}
+}
+
+func TestRefreshVersion_KeepsPrefixWhenDiscoveryFails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/ping/" {
+			w.Write([]byte(`{"version":"24.6.1"}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	store := models.NewConnectionStore()
+	conn := &models.Connection{Type: "awx", Scheme: "http", Host: u.Hostname(), Port: port,
+		Username: "admin", Password: "secret", MaxRetries: -1}
+	store.Create(conn)
+	store.SetVersion(conn.ID, "23.4.0", "/api/v2/")
+
+	info, err := RefreshVersion(conn, store)
+	if err != nil {
+		t.Fatalf("RefreshVersion returned error: %v", err)
+	}
+	if info.Version != "24.6.1" || info.APIPrefix != "/api/v2/" {
+		t.Errorf("info = %+v, want version 24.6.1 and the old prefix", info)
+	}
+}
+
+func TestRefreshVersion_Unreachable(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	conn := &models.Connection{Type: "awx", Scheme: "http", Host: u.Hostname(), Port: port, MaxRetries: -1}
+
+	if _, err := RefreshVersion(conn, models.NewConnectionStore()); err == nil {
+		t.Error("RefreshVersion succeeded against a closed server")
+	}
+}
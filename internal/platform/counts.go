@@ -0,0 +1,57 @@
+package platform
+
+import (
+	"sync"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// countWorkers bounds the count requests CountResources sends at once.
+const countWorkers = 4
+
+// ResourceCount is a resource type with the number of objects of it. Error
+// is set, and Count is zero, when the count could not be read.
+type ResourceCount struct {
+	models.ResourceType
+	Count int    `json:"count"`
+	Error string `json:"error,omitempty"`
+}
+
+// Count returns the number of objects at a paginated endpoint. It asks for a
+// single result and reads the total from the page's count, so large lists
+// are not fetched; with a cache (see WithCache) the page is revalidated by
+// ETag like other list pages.
+func (c *Client) Count(path string) (int, error) {
+	_, _, count, err := c.getPage(c.baseURL + path + "?page_size=1")
+	return count, err
+}
+
+// CountResources counts the objects of each type through client, with up to
+// countWorkers requests in flight, and returns the counts in the order of
+// types.
+func CountResources(client *Client, types []models.ResourceType) []ResourceCount {
+	counts := make([]ResourceCount, len(types))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < countWorkers && w < len(types); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				counts[i].ResourceType = types[i]
+				n, err := client.Count(types[i].APIPath)
+				if err != nil {
+					counts[i].Error = err.Error()
+					continue
+				}
+				counts[i].Count = n
+			}
+		}()
+	}
+	for i := range types {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	return counts
+}
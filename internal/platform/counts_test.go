@@ -0,0 +1,59 @@
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestCountResources(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.RequestURI())
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v2/hosts/":
+			w.Write([]byte(`{"count":2500,"next":"/api/v2/hosts/?page=2&page_size=1","results":[{"id":1,"name":"web1"}]}`))
+		case "/api/v2/teams/":
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	types := []models.ResourceType{
+		{Name: "hosts", APIPath: "/api/v2/hosts/"},
+		{Name: "teams", APIPath: "/api/v2/teams/"},
+		{Name: "gone", APIPath: "/api/v2/gone/"},
+	}
+	counts := CountResources(newTestClient(ts), types)
+
+	if len(counts) != 3 {
+		t.Fatalf("got %d counts, want 3", len(counts))
+	}
+	if counts[0].Name != "hosts" || counts[0].Count != 2500 || counts[0].Error != "" {
+		t.Errorf("hosts = %+v, want count 2500", counts[0])
+	}
+	if counts[1].Name != "teams" || counts[1].Count != 0 || counts[1].Error != "" {
+		t.Errorf("teams = %+v, want count 0", counts[1])
+	}
+	if counts[2].Name != "gone" || counts[2].Error == "" {
+		t.Errorf("gone = %+v, want an error", counts[2])
+	}
+	// One single-result request per type; later pages are never fetched.
+	if len(requests) != 3 {
+		t.Errorf("sent %d requests, want 3: %v", len(requests), requests)
+	}
+	for _, uri := range requests {
+		if u, _ := url.Parse(uri); u.Query().Get("page_size") != "1" || u.Query().Get("page") != "" {
+			t.Errorf("request %s, want page_size=1 on the first page", uri)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package platform
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// debugBodyLimit caps how much of a request or response body is logged.
+const debugBodyLimit = 1024
+
+// httpDebug logs every request a client sends and the response it gets,
+// with credentials and secret fields masked.
+type httpDebug struct {
+	log      func(string)
+	redactor *models.Redactor
+}
+
+// defaultDebug is given to clients created by NewClient; nil leaves HTTP
+// logging off.
+var defaultDebug *httpDebug
+
+// SetHTTPDebug makes clients created afterwards log their HTTP traffic to
+// logger, masking values of secretKeys in addition to
+// models.DefaultSecretKeys. A nil logger turns logging off again. It is
+// meant to be called once at startup.
+func SetHTTPDebug(logger func(string), secretKeys []string) {
+	defaultDebug = newHTTPDebug(logger, secretKeys)
+}
+
+// WithDebug makes c log its HTTP traffic to logger, as SetHTTPDebug does for
+// new clients. It returns c for chaining.
+func (c *Client) WithDebug(logger func(string), secretKeys []string) *Client {
+	c.debug = newHTTPDebug(logger, secretKeys)
+	return c
+}
+
+func newHTTPDebug(logger func(string), secretKeys []string) *httpDebug {
+	if logger == nil {
+		return nil
+	}
+	return &httpDebug{log: logger, redactor: models.NewRedactor(secretKeys)}
+}
+
+// do sends req with client and logs both sides of the exchange. The
+// response body is read in full and replaced, so callers see it unchanged.
+func (d *httpDebug) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	auth := ""
+	if req.Header.Get("Authorization") != "" {
+		auth = " Authorization: ••••"
+	}
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+	d.log(fmt.Sprintf("HTTP %s %s%s%s", req.Method, req.URL, auth, d.body(reqBody)))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		d.log(fmt.Sprintf("HTTP %s %s failed after %s: %v", req.Method, req.URL, elapsed, err))
+		return resp, err
+	}
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		d.log(fmt.Sprintf("HTTP %s %s → %d in %s, reading body: %v", req.Method, req.URL, resp.StatusCode, elapsed, err))
+		return resp, nil
+	}
+	decoded, err := readBody(&http.Response{Header: resp.Header, Body: io.NopCloser(bytes.NewReader(raw))})
+	if err != nil {
+		decoded = nil
+	}
+	d.log(fmt.Sprintf("HTTP %s %s → %d in %s%s", req.Method, req.URL, resp.StatusCode, elapsed, d.body(decoded)))
+	return resp, nil
+}
+
+// body formats a body for the log: masked and truncated to debugBodyLimit.
+func (d *httpDebug) body(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	s := string(b)
+	if len(s) > debugBodyLimit {
+		s = s[:debugBodyLimit] + fmt.Sprintf("… (%d bytes)", len(b))
+	}
+	return " " + d.redactor.Redact(s)
+}
@@ -0,0 +1,42 @@
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_DebugLogsGet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":7,"name":"Vault","inputs":{"password":"hunter2"}}`))
+	}))
+	defer ts.Close()
+
+	var logs []string
+	client := newTestClient(ts).WithDebug(func(line string) { logs = append(logs, line) }, nil)
+	client.token = "s3cr3t-token"
+	body, err := client.Get("/api/v2/credentials/7/", nil)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !strings.Contains(string(body), "hunter2") {
+		t.Errorf("caller got body %s, want it unmasked", body)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("logged %d lines, want 2: %q", len(logs), logs)
+	}
+	if want := "HTTP GET " + ts.URL + "/api/v2/credentials/7/ Authorization: ••••"; logs[0] != want {
+		t.Errorf("request line = %q, want %q", logs[0], want)
+	}
+	if !strings.HasPrefix(logs[1], "HTTP GET "+ts.URL+"/api/v2/credentials/7/ → 200 in ") ||
+		!strings.Contains(logs[1], `"name":"Vault"`) {
+		t.Errorf("response line = %q", logs[1])
+	}
+	for _, line := range logs {
+		if strings.Contains(line, "s3cr3t-token") || strings.Contains(line, "hunter2") {
+			t.Errorf("secret leaked into %q", line)
+		}
+	}
+}
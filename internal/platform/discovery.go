@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -24,15 +25,129 @@ type APIRootResponse struct {
 }
 
 // ParsePingResponse extracts the version from a /ping/ JSON response body.
+// The top-level "version" field is preferred. When it is absent (e.g. some
+// AAP 2.5 gateway payloads), the version is taken from the active node in
+// "instances", or from a "services" list/map, preferring the controller.
 func ParsePingResponse(body []byte) (*PingResponse, error) {
-	var resp PingResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
 		return nil, fmt.Errorf("parsing ping response: %w", err)
 	}
-	if resp.Version == "" {
+	version := stringValue(raw["version"])
+	if version == "" {
+		version = instancesVersion(raw)
+	}
+	if version == "" {
+		version = servicesVersion(raw["services"])
+	}
+	if version == "" {
 		return nil, fmt.Errorf("ping response missing version field")
 	}
-	return &resp, nil
+	return &PingResponse{Version: version}, nil
+}
+
+// instancesVersion returns the version of the active node listed under
+// "instances", or the first instance that reports one.
+func instancesVersion(raw map[string]interface{}) string {
+	instances, ok := raw["instances"].([]interface{})
+	if !ok {
+		return ""
+	}
+	activeNode := stringValue(raw["active_node"])
+	var first string
+	for _, inst := range instances {
+		im, ok := inst.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v := stringValue(im["version"])
+		if v == "" {
+			continue
+		}
+		if activeNode != "" && stringValue(im["node"]) == activeNode {
+			return v
+		}
+		if first == "" {
+			first = v
+		}
+	}
+	return first
+}
+
+// servicesVersion returns a version from a gateway "services" field, preferring
+// the controller.
+func servicesVersion(services interface{}) string {
+	byName, order := serviceVersions(services)
+	if v, ok := byName["controller"]; ok {
+		return v
+	}
+	if len(order) > 0 {
+		return byName[order[0]]
+	}
+	return ""
+}
+
+// serviceVersions collects the versions in a gateway "services" field, which may be
+// a list of {"name"/"service_type", "version"} objects or a map keyed by service name.
+// The names are returned in list order, or sorted for a map.
+func serviceVersions(services interface{}) (map[string]string, []string) {
+	byName := make(map[string]string)
+	var order []string
+	switch sv := services.(type) {
+	case []interface{}:
+		for _, s := range sv {
+			sm, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := stringValue(sm["name"])
+			if name == "" {
+				name = stringValue(sm["service_type"])
+			}
+			if v := stringValue(sm["version"]); v != "" {
+				byName[name] = v
+				order = append(order, name)
+			}
+		}
+	case map[string]interface{}:
+		for name, s := range sv {
+			if sm, ok := s.(map[string]interface{}); ok {
+				if v := stringValue(sm["version"]); v != "" {
+					byName[name] = v
+					order = append(order, name)
+				}
+			}
+		}
+		sort.Strings(order)
+	}
+	return byName, order
+}
+
+// GatewayPingPath is the AAP 2.5+ platform gateway ping endpoint.
+const GatewayPingPath = "/api/gateway/v1/ping/"
+
+// ParseGatewayPingResponse extracts the gateway's own version from a
+// /api/gateway/v1/ping/ response: the top-level "version", or the "gateway"
+// entry of "services". Controller versions are never used here.
+func ParseGatewayPingResponse(body []byte) (*PingResponse, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("parsing gateway ping response: %w", err)
+	}
+	version := stringValue(raw["version"])
+	if version == "" {
+		byName, _ := serviceVersions(raw["services"])
+		version = byName["gateway"]
+	}
+	if version == "" {
+		return nil, fmt.Errorf("gateway ping response missing version field")
+	}
+	return &PingResponse{Version: version}, nil
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
 }
 
 // ParseAPIRoot parses the /api/ response body.
@@ -71,6 +186,29 @@ func DetectAPIPrefix(root *APIRootResponse) string {
 	return ""
 }
 
+// APIRootPath is the API root listing that both AWX and AAP serve.
+const APIRootPath = "/api/"
+
+// DetectType tells AWX from AAP by their /api/ root: AWX reports a
+// current_version, while the AAP gateway lists a controller among its apis.
+func DetectType(client *Client) (string, error) {
+	body, err := client.Get(APIRootPath, nil)
+	if err != nil {
+		return "", err
+	}
+	root, err := ParseAPIRoot(body)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case root.CurrentVersion != "":
+		return "awx", nil
+	case root.APIs["controller"] != "":
+		return "aap", nil
+	}
+	return "", fmt.Errorf("unrecognized API root at %s", APIRootPath)
+}
+
 // CompareVersions performs a simple semver comparison.
 // Returns -1 if a < b, 0 if a == b, 1 if a > b.
 // Handles partial versions (e.g. "4.7" vs "4.7.8").
@@ -167,10 +305,33 @@ func (c *Client) PingWithVersion(apiPath string) (*PingResponse, error) {
 	return resp, nil
 }
 
+// discoverGatewayVersion records the AAP gateway version. AAP 2.4 has no
+// gateway, so a failed request only leaves the version empty.
+func discoverGatewayVersion(client *Client, conn *models.Connection, store *models.ConnectionStore) {
+	body, err := client.Get(GatewayPingPath, nil)
+	if err != nil {
+		return
+	}
+	resp, err := ParseGatewayPingResponse(body)
+	if err != nil {
+		log.Printf("  DISCOVERY: %s: %v", conn.Name, err)
+		return
+	}
+	store.SetGatewayVersion(conn.ID, resp.Version)
+	fmt.Printf("  DISCOVERY: %s: gateway version: %s\n", conn.Name, resp.Version)
+}
+
 // DiscoverAndStore orchestrates API discovery for a connection.
-// It calls /api/ to detect the API prefix, then stores the result on the connection.
+// For AAP it first records the gateway version, then it calls /api/ to
+// detect the API prefix and stores the result on the connection. The
+// connection is shared with running jobs, so results are only written
+// through store; read them back with store.Versions.
 // All discovery is best-effort: failures are logged but do not produce errors.
 func DiscoverAndStore(client *Client, conn *models.Connection, store *models.ConnectionStore) {
+	if conn.Type == "aap" {
+		discoverGatewayVersion(client, conn, store)
+	}
+
 	// GET /api/ to discover prefix
 	body, err := client.Get("/api/", nil)
 	if err != nil {
@@ -190,8 +351,6 @@ func DiscoverAndStore(client *Client, conn *models.Connection, store *models.Con
 		return
 	}
 
-	store.SetVersion(conn.ID, conn.Version, prefix)
-	// Update local conn so callers see it immediately
-	conn.APIPrefix = prefix
+	store.SetAPIPrefix(conn.ID, prefix)
 	fmt.Printf("  DISCOVERY: %s: detected API prefix: %s\n", conn.Name, prefix)
 }
@@ -47,6 +47,43 @@ func TestParsePingResponse_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestParsePingResponse_ActiveNodeInstance(t *testing.T) {
+	body := []byte(`{"ha":true,"active_node":"controller-2","instances":[
+		{"node":"controller-1","version":"4.6.1"},
+		{"node":"controller-2","version":"4.6.2"}]}`)
+	resp, err := ParsePingResponse(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Version != "4.6.2" {
+		t.Errorf("Version = %q, want %q", resp.Version, "4.6.2")
+	}
+}
+
+func TestParsePingResponse_GatewayServicesList(t *testing.T) {
+	body := []byte(`{"pong":"2025-01-01T00:00:00Z","status":"good","services":[
+		{"service_type":"eda","version":"1.1.0"},
+		{"service_type":"controller","version":"4.6.8"}]}`)
+	resp, err := ParsePingResponse(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Version != "4.6.8" {
+		t.Errorf("Version = %q, want %q", resp.Version, "4.6.8")
+	}
+}
+
+func TestParsePingResponse_GatewayServicesMap(t *testing.T) {
+	body := []byte(`{"status":"good","services":{"gateway":{"version":"2.5.3"},"hub":{"version":"4.10.1"}}}`)
+	resp, err := ParsePingResponse(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Version != "2.5.3" {
+		t.Errorf("Version = %q, want %q", resp.Version, "2.5.3")
+	}
+}
+
 func TestParseAPIRoot_AWX(t *testing.T) {
 	body := []byte(`{"description":"AWX REST API","current_version":"/api/v2/","available_versions":{"v2":"/api/v2/"}}`)
 	resp, err := ParseAPIRoot(body)
@@ -273,3 +310,192 @@ func TestPingPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestParseGatewayPingResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"top-level", `{"version":"2.5.20250115","pong":"2025-01-15T10:00:00Z","status":"good","db_connected":true,"proxy_connected":true}`, "2.5.20250115"},
+		{"services", `{"status":"good","services":[{"service_type":"controller","version":"4.6.8"},{"service_type":"gateway","version":"2.5.3"}]}`, "2.5.3"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseGatewayPingResponse([]byte(tc.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Version != tc.want {
+				t.Errorf("Version = %q, want %q", resp.Version, tc.want)
+			}
+		})
+	}
+
+	// A controller-only services list has no gateway version.
+	if _, err := ParseGatewayPingResponse([]byte(`{"services":[{"service_type":"controller","version":"4.6.8"}]}`)); err == nil {
+		t.Error("expected error when no gateway version is present")
+	}
+}
+
+func TestDiscoverAndStore_GatewayVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/controller/v2/ping/":
+			w.Write([]byte(`{"version":"4.6.8","active_node":"ctrl-1"}`))
+		case GatewayPingPath:
+			w.Write([]byte(`{"version":"2.5.20250115","status":"good"}`))
+		case "/api/":
+			w.Write([]byte(`{"apis":{"controller":"/api/controller/","gateway":"/api/gateway/"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	store := models.NewConnectionStore()
+	conn := &models.Connection{Name: "aap25", Type: "aap"}
+	store.Create(conn)
+	client := &Client{baseURL: ts.URL, httpClient: ts.Client()}
+
+	resp, err := client.PingWithVersion("/api/controller/v2/ping/")
+	if err != nil {
+		t.Fatalf("controller ping: %v", err)
+	}
+	conn.Version = resp.Version
+	DiscoverAndStore(client, conn, store)
+
+	got := store.Get(conn.ID)
+	if got.Version != "4.6.8" {
+		t.Errorf("Version = %q, want 4.6.8", got.Version)
+	}
+	if got.GatewayVersion != "2.5.20250115" {
+		t.Errorf("GatewayVersion = %q, want 2.5.20250115", got.GatewayVersion)
+	}
+	if got.APIPrefix != "/api/controller/v2/" {
+		t.Errorf("APIPrefix = %q, want /api/controller/v2/", got.APIPrefix)
+	}
+}
+
+func TestAAPGetResourceTypes_MinGatewayVersion(t *testing.T) {
+	p := NewAAPPlatform(&Client{})
+	p.resources = []models.ResourceType{
+		{Name: "organizations"},
+		{Name: "gateway_only", MinGatewayVersion: "2.5"},
+	}
+
+	if got := len(p.GetResourceTypes()); got != 1 {
+		t.Errorf("unknown versions: %d types, want 1", got)
+	}
+
+	p.version = "4.5.0" // AAP 2.4: no gateway
+	if got := len(p.GetResourceTypes()); got != 1 {
+		t.Errorf("without gateway version: %d types, want 1", got)
+	}
+
+	p.gwVersion = "2.4.9"
+	if got := len(p.GetResourceTypes()); got != 1 {
+		t.Errorf("old gateway: %d types, want 1", got)
+	}
+
+	p.gwVersion = "2.5.20250115"
+	if got := len(p.GetResourceTypes()); got != 2 {
+		t.Errorf("gateway 2.5: %d types, want 2", got)
+	}
+}
+
+func TestAAPGetResourceTypes_GatewayIdentityPaths(t *testing.T) {
+	pathOf := func(p Platform, name string) string {
+		for _, rt := range p.GetResourceTypes() {
+			if rt.Name == name {
+				return rt.APIPath
+			}
+		}
+		return ""
+	}
+	tests := []struct {
+		name            string
+		conn            models.Connection
+		wantUsers       string
+		wantCredentials string
+	}{
+		{"AAP 2.4 RPM", models.Connection{Type: "aap", Version: "4.5.0", APIPrefix: "/api/v2/"},
+			"/api/v2/users/", "/api/v2/credentials/"},
+		{"AAP 2.4 old gateway", models.Connection{Type: "aap", Version: "4.5.0", GatewayVersion: "2.4.9"},
+			"/api/controller/v2/users/", "/api/controller/v2/credentials/"},
+		{"AAP 2.5", models.Connection{Type: "aap", Version: "4.6.8", GatewayVersion: "2.5.20250115", APIPrefix: "/api/controller/v2/"},
+			"/api/gateway/v1/users/", "/api/controller/v2/credentials/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlatform(&tt.conn)
+			if got := pathOf(p, "users"); got != tt.wantUsers {
+				t.Errorf("users path = %q, want %q", got, tt.wantUsers)
+			}
+			if got := pathOf(p, "credentials"); got != tt.wantCredentials {
+				t.Errorf("credentials path = %q, want %q", got, tt.wantCredentials)
+			}
+		})
+	}
+	if aapResources[2].APIPath != "/api/controller/v2/users/" {
+		t.Errorf("registry was modified: users path = %q", aapResources[2].APIPath)
+	}
+}
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"AWX", `{"description":"AWX REST API","current_version":"/api/v2/","available_versions":{"v2":"/api/v2/"}}`, "awx"},
+		{"AAP", `{"apis":{"gateway":"/api/gateway/","controller":"/api/controller/","eda":"/api/eda/"}}`, "aap"},
+		{"unknown", `{"description":"something else"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != APIRootPath {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			got, err := DetectType(newTestClient(ts))
+			if got != tt.want {
+				t.Errorf("DetectType = %q, want %q", got, tt.want)
+			}
+			if (err != nil) != (tt.want == "") {
+				t.Errorf("DetectType error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAAPListResources_Applications(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"count":1,"next":null,"results":[{"id":4,"name":"Grafana","client_type":"confidential"}]}`))
+	}))
+	defer ts.Close()
+
+	p := NewAAPPlatform(newTestClient(ts))
+	p.version = "4.5.0" // AAP 2.4: applications live in the controller
+	apps, err := p.ListResources("applications")
+	if err != nil {
+		t.Fatalf("ListResources returned error: %v", err)
+	}
+	if len(apps) != 1 || apps[0]["name"] != "Grafana" {
+		t.Errorf("applications = %v, want Grafana", apps)
+	}
+
+	p.gwVersion = "2.5.20250115" // AAP 2.5: the gateway owns them
+	if _, err := p.ListResources("applications"); err != nil {
+		t.Fatalf("ListResources returned error: %v", err)
+	}
+	want := []string{"/api/controller/v2/applications/", "/api/gateway/v1/applications/"}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("requested %v, want %v", paths, want)
+	}
+}
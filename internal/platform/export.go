@@ -0,0 +1,198 @@
+package platform
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Export file formats.
+const (
+	ExportJSON = "json"
+	ExportYAML = "yaml"
+)
+
+// ExportOptions controls optional outputs of Platform.Export.
+type ExportOptions struct {
+	// Metadata writes a "_meta.json" sidecar next to each exported resource
+	// recording its source provenance (created/modified timestamps and users).
+	// The destination assigns fresh timestamps on import, so this is the only
+	// record of when and by whom the original object was created.
+	Metadata bool `json:"metadata"`
+
+	// Format is ExportJSON (the default when empty) or ExportYAML. YAML
+	// files hold the same structure with a ".yaml" extension, which reads
+	// better in git diffs.
+	Format string `json:"-"`
+}
+
+// ResourceMetadata is the provenance recorded for one exported resource.
+type ResourceMetadata struct {
+	Type       string `json:"type"`
+	SourceID   int    `json:"source_id"`
+	Name       string `json:"name"`
+	Created    string `json:"created,omitempty"`
+	Modified   string `json:"modified,omitempty"`
+	CreatedBy  string `json:"created_by,omitempty"`
+	ModifiedBy string `json:"modified_by,omitempty"`
+}
+
+// ManifestEntry describes one resource written by Export.
+type ManifestEntry struct {
+	Type         string `json:"type"`
+	SourceID     int    `json:"source_id"`
+	Name         string `json:"name"`
+	File         string `json:"file"`
+	MetadataFile string `json:"metadata_file,omitempty"`
+}
+
+// Manifest is written to "_manifest.json" at the root of an export.
+type Manifest struct {
+	Source     string             `json:"source"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Resources  []ManifestEntry    `json:"resources"`
+	Metadata   []ResourceMetadata `json:"metadata,omitempty"`
+}
+
+// exportWriter writes export files under outputDir and tracks the manifest.
+type exportWriter struct {
+	outputDir string
+	opts      ExportOptions
+	files     int
+	manifest  Manifest
+}
+
+func newExportWriter(outputDir, source string, opts ExportOptions) *exportWriter {
+	return &exportWriter{
+		outputDir: outputDir,
+		opts:      opts,
+		manifest:  Manifest{Source: source, ExportedAt: time.Now().UTC(), Resources: []ManifestEntry{}},
+	}
+}
+
+// write marshals data as indented JSON, or YAML, into
+// outputDir/dir/filename. Filenames are given with a ".json" extension.
+func (w *exportWriter) write(dir, filename string, data interface{}) error {
+	dirPath := filepath.Join(w.outputDir, dir)
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if w.opts.Format == ExportYAML {
+		if b, err = jsonToYAML(b); err != nil {
+			return err
+		}
+	}
+	w.files++
+	return os.WriteFile(filepath.Join(dirPath, w.filename(filename)), b, 0644)
+}
+
+// format returns the export's file format.
+func (w *exportWriter) format() string {
+	if w.opts.Format == "" {
+		return ExportJSON
+	}
+	return w.opts.Format
+}
+
+// filename returns the name a ".json" file is written under in the export's
+// format.
+func (w *exportWriter) filename(name string) string {
+	if w.opts.Format == ExportYAML {
+		return strings.TrimSuffix(name, ".json") + ".yaml"
+	}
+	return name
+}
+
+// jsonToYAML converts a JSON document to block-style YAML, keeping key order
+// and writing numbers as they appear in the JSON. Strings that YAML 1.1
+// would read as another type stay quoted, see BlockStyle.
+func jsonToYAML(b []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	BlockStyle(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeResource writes a single resource object, records it in the manifest
+// and, if enabled, writes its provenance sidecar.
+func (w *exportWriter) writeResource(dir, filename string, obj map[string]interface{}) error {
+	if err := w.write(dir, filename, obj); err != nil {
+		return err
+	}
+	entry := ManifestEntry{
+		Type:     dir,
+		SourceID: intField(obj, "id"),
+		Name:     resourceName(obj),
+		File:     filepath.Join(dir, w.filename(filename)),
+	}
+	if w.opts.Metadata {
+		meta := resourceMetadata(dir, obj)
+		metaFile := strings.TrimSuffix(filename, ".json") + "_meta.json"
+		if err := w.write(dir, metaFile, meta); err != nil {
+			return err
+		}
+		entry.MetadataFile = filepath.Join(dir, w.filename(metaFile))
+		w.manifest.Metadata = append(w.manifest.Metadata, meta)
+	}
+	w.manifest.Resources = append(w.manifest.Resources, entry)
+	return nil
+}
+
+// writeManifest writes "_manifest.json" (or "_manifest.yaml") at the root of
+// the export.
+func (w *exportWriter) writeManifest() error {
+	return w.write("", "_manifest.json", w.manifest)
+}
+
+// resourceMetadata extracts provenance fields from a resource. created_by and
+// modified_by come from summary_fields when the API exposes them.
+func resourceMetadata(typeName string, obj map[string]interface{}) ResourceMetadata {
+	meta := ResourceMetadata{
+		Type:     typeName,
+		SourceID: intField(obj, "id"),
+		Name:     resourceName(obj),
+	}
+	meta.Created, _ = obj["created"].(string)
+	meta.Modified, _ = obj["modified"].(string)
+	if sf, ok := obj["summary_fields"].(map[string]interface{}); ok {
+		if u, ok := sf["created_by"].(map[string]interface{}); ok {
+			meta.CreatedBy, _ = u["username"].(string)
+		}
+		if u, ok := sf["modified_by"].(map[string]interface{}); ok {
+			meta.ModifiedBy, _ = u["username"].(string)
+		}
+	}
+	return meta
+}
+
+// sortedKeys returns the resource types of a per-type ID set in order, so
+// export summaries are logged the same way every run.
+func sortedKeys(m map[string]map[int]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,175 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportWriter_MetadataSidecar(t *testing.T) {
+	dir := t.TempDir()
+	w := newExportWriter(dir, "https://awx.example.com:443", ExportOptions{Metadata: true})
+
+	obj := map[string]interface{}{
+		"id":       float64(4),
+		"name":     "Deploy",
+		"created":  "2023-01-02T03:04:05Z",
+		"modified": "2024-05-06T07:08:09Z",
+		"summary_fields": map[string]interface{}{
+			"created_by":  map[string]interface{}{"username": "alice"},
+			"modified_by": map[string]interface{}{"username": "bob"},
+		},
+	}
+	if err := w.writeResource("job_templates", "4_Deploy_details.json", obj); err != nil {
+		t.Fatalf("writeResource: %v", err)
+	}
+	if err := w.writeManifest(); err != nil {
+		t.Fatalf("writeManifest: %v", err)
+	}
+
+	var meta ResourceMetadata
+	b, err := os.ReadFile(filepath.Join(dir, "job_templates", "4_Deploy_details_meta.json"))
+	if err != nil {
+		t.Fatalf("reading sidecar: %v", err)
+	}
+	json.Unmarshal(b, &meta)
+	if meta.SourceID != 4 || meta.CreatedBy != "alice" || meta.ModifiedBy != "bob" || meta.Created != "2023-01-02T03:04:05Z" {
+		t.Errorf("sidecar = %+v", meta)
+	}
+
+	var manifest Manifest
+	b, err = os.ReadFile(filepath.Join(dir, "_manifest.json"))
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	json.Unmarshal(b, &manifest)
+	if len(manifest.Resources) != 1 || manifest.Resources[0].MetadataFile == "" {
+		t.Errorf("manifest resources = %+v", manifest.Resources)
+	}
+	if len(manifest.Metadata) != 1 {
+		t.Errorf("manifest metadata = %+v", manifest.Metadata)
+	}
+}
+
+func TestExportWriter_NoMetadata(t *testing.T) {
+	dir := t.TempDir()
+	w := newExportWriter(dir, "http://awx.example.com:80", ExportOptions{})
+	obj := map[string]interface{}{"id": float64(1), "name": "Ops"}
+	if err := w.writeResource("organizations", "1_Ops.json", obj); err != nil {
+		t.Fatalf("writeResource: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "organizations", "1_Ops_meta.json")); !os.IsNotExist(err) {
+		t.Error("sidecar should not be written when metadata is disabled")
+	}
+	if w.files != 1 {
+		t.Errorf("files = %d, want 1", w.files)
+	}
+}
+
+func TestExportWriter_YAML(t *testing.T) {
+	dir := t.TempDir()
+	w := newExportWriter(dir, "https://awx.example.com:443", ExportOptions{Metadata: true, Format: ExportYAML})
+
+	obj := map[string]interface{}{
+		"id":              float64(1000004),
+		"name":            "Deploy",
+		"description":     "yes",
+		"scm_branch":      "on",
+		"job_tags":        "1:20",
+		"skip_tags":       "1_000",
+		"host_config_key": "~",
+		"extra_vars":      "---\nregion: eu\n",
+		"limit":           "",
+		"verbosity":       float64(0),
+		"timeout":         1.5,
+		"survey":          nil,
+		"labels":          []interface{}{"web", "123"},
+		"summary_fields": map[string]interface{}{
+			"created_by": map[string]interface{}{"username": "alice"},
+		},
+	}
+	if err := w.writeResource("job_templates", "4_Deploy_details.json", obj); err != nil {
+		t.Fatalf("writeResource: %v", err)
+	}
+	if err := w.writeManifest(); err != nil {
+		t.Fatalf("writeManifest: %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "job_templates", "4_Deploy_details.yaml"))
+	if err != nil {
+		t.Fatalf("reading resource: %v", err)
+	}
+	var got map[string]interface{}
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatalf("parsing %s: %v", b, err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(obj)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("YAML parses back as\n%s\nwant\n%s", gotJSON, wantJSON)
+	}
+	// PyYAML reads these as bools, numbers or null when unquoted.
+	for _, line := range []string{`name: Deploy`, `description: "yes"`, `scm_branch: "on"`,
+		`job_tags: "1:20"`, `skip_tags: "1_000"`, `host_config_key: "~"`, `  - "123"`} {
+		if !strings.Contains(string(b), line+"\n") {
+			t.Errorf("missing %q in\n%s", line, b)
+		}
+	}
+
+	var manifest map[string]interface{}
+	b, err = os.ReadFile(filepath.Join(dir, "_manifest.yaml"))
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	yaml.Unmarshal(b, &manifest)
+	resources, _ := manifest["resources"].([]interface{})
+	if len(resources) != 1 {
+		t.Fatalf("manifest resources = %v", manifest["resources"])
+	}
+	entry, _ := resources[0].(map[string]interface{})
+	if entry["file"] != filepath.Join("job_templates", "4_Deploy_details.yaml") || entry["metadata_file"] != filepath.Join("job_templates", "4_Deploy_details_meta.yaml") {
+		t.Errorf("manifest entry = %v", entry)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "job_templates", "4_Deploy_details_meta.yaml")); err != nil {
+		t.Errorf("sidecar: %v", err)
+	}
+}
+
+func TestAWXExport_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	details := 0
+	detailPath := regexp.MustCompile(`^/api/v2/workflow_job_templates/\d+/$`)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/workflow_job_templates/" {
+			w.Write([]byte(`{"count":3,"next":null,"results":[{"id":1,"name":"A"},{"id":2,"name":"B"},{"id":3,"name":"C"}]}`))
+			return
+		}
+		if detailPath.MatchString(r.URL.Path) {
+			mu.Lock()
+			details++
+			mu.Unlock()
+			// Cancel while the first workflow is being exported.
+			cancel()
+		}
+		w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	err := NewAWXPlatform(newTestClient(ts)).Export(ctx, t.TempDir(), ExportOptions{}, func(string) {})
+	if err != context.Canceled {
+		t.Fatalf("Export error = %v, want context.Canceled", err)
+	}
+	if details != 1 {
+		t.Errorf("fetched %d workflow details, want 1 before stopping", details)
+	}
+}
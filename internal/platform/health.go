@@ -0,0 +1,155 @@
+package platform
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// DefaultHealthInterval is how often the health poller re-checks each
+// connection when no interval is configured.
+const DefaultHealthInterval = 60 * time.Second
+
+// healthMaxBackoff caps how many intervals a failing connection waits
+// between checks.
+const healthMaxBackoff = 16
+
+// HealthStore is the part of models.ConnectionStore the health poller uses.
+type HealthStore interface {
+	List() []*models.Connection
+	SetHealth(id, pingStatus, pingError, authStatus, authError string)
+}
+
+// CheckHealth pings a connection and, if it is reachable, verifies its
+// credentials. Statuses are "ok", "error" or "unknown" (auth not attempted).
+func CheckHealth(conn *models.Connection) (pingStatus, pingError, authStatus, authError string) {
+	p := NewPlatform(conn)
+	if err := p.Ping(); err != nil {
+		return "error", err.Error(), "unknown", ""
+	}
+	if !conn.HasCredentials() {
+		return "ok", "", "error", "no credentials configured"
+	}
+	if err := p.CheckAuth(); err != nil {
+		return "ok", "", "error", err.Error()
+	}
+	return "ok", "", "ok", ""
+}
+
+// HealthPoller periodically re-checks every connection in a store so the
+// dashboard stays current without users pressing Test. Connections without
+// credentials are skipped, failing connections are checked less often, and
+// a connection is never checked twice at the same time.
+type HealthPoller struct {
+	store    HealthStore
+	interval time.Duration
+	check    func(*models.Connection) (string, string, string, string)
+
+	mu       sync.Mutex
+	running  map[string]bool
+	failures map[string]int
+	next     map[string]time.Time // earliest time of the next check
+}
+
+// NewHealthPoller creates a poller for store. A non-positive interval
+// uses DefaultHealthInterval.
+func NewHealthPoller(store HealthStore, interval time.Duration) *HealthPoller {
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+	return &HealthPoller{
+		store:    store,
+		interval: interval,
+		check:    CheckHealth,
+		running:  make(map[string]bool),
+		failures: make(map[string]int),
+		next:     make(map[string]time.Time),
+	}
+}
+
+// SetInterval changes how often connections are checked, from the next
+// poll on. A non-positive interval uses DefaultHealthInterval.
+func (h *HealthPoller) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+	h.mu.Lock()
+	h.interval = interval
+	h.mu.Unlock()
+}
+
+// Run polls until ctx is cancelled.
+func (h *HealthPoller) Run(ctx context.Context) {
+	for {
+		h.mu.Lock()
+		timer := time.NewTimer(h.interval)
+		h.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			h.poll(now)
+		}
+	}
+}
+
+// poll starts a check for every connection that is due and not already
+// being checked, and forgets the backoff of connections that were removed
+// or lost their credentials.
+func (h *HealthPoller) poll(now time.Time) {
+	polled := make(map[string]bool)
+	for _, conn := range h.store.List() {
+		if !conn.HasCredentials() {
+			continue
+		}
+		polled[conn.ID] = true
+		h.mu.Lock()
+		due := !h.running[conn.ID] && !now.Before(h.next[conn.ID])
+		if due {
+			h.running[conn.ID] = true
+		}
+		h.mu.Unlock()
+		if due {
+			go h.checkOne(conn)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id := range h.failures {
+		if !polled[id] {
+			delete(h.failures, id)
+			delete(h.next, id)
+		}
+	}
+}
+
+// checkOne checks a single connection and schedules its next check,
+// doubling the wait after each consecutive failure.
+func (h *HealthPoller) checkOne(conn *models.Connection) {
+	pingStatus, pingError, authStatus, authError := h.check(conn)
+	h.store.SetHealth(conn.ID, pingStatus, pingError, authStatus, authError)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.running, conn.ID)
+	if pingStatus == "ok" && authStatus == "ok" {
+		delete(h.failures, conn.ID)
+		delete(h.next, conn.ID)
+		return
+	}
+	h.failures[conn.ID]++
+	h.next[conn.ID] = time.Now().Add(h.interval * time.Duration(backoffFactor(h.failures[conn.ID])))
+}
+
+// backoffFactor returns how many intervals to wait after n consecutive failures.
+func backoffFactor(n int) int {
+	f := 1
+	for i := 1; i < n && f < healthMaxBackoff; i++ {
+		f *= 2
+	}
+	return f
+}
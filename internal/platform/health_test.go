@@ -0,0 +1,152 @@
+package platform
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// fakeHealthStore records SetHealth calls per connection ID.
+type fakeHealthStore struct {
+	mu     sync.Mutex
+	conns  []*models.Connection
+	health map[string][4]string
+}
+
+func (s *fakeHealthStore) List() []*models.Connection { return s.conns }
+
+func (s *fakeHealthStore) SetHealth(id, pingStatus, pingError, authStatus, authError string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health[id] = [4]string{pingStatus, pingError, authStatus, authError}
+}
+
+func (s *fakeHealthStore) get(id string) ([4]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.health[id]
+	return h, ok
+}
+
+func TestHealthPoller_UpdatesHealth(t *testing.T) {
+	ts := authServer(t, new([]string))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	port, _ := strconv.Atoi(u.Port())
+	conn := &models.Connection{ID: "c1", Type: "awx", Scheme: "http", Host: u.Hostname(), Port: port,
+		Username: "admin", Password: "secret"}
+	noCreds := &models.Connection{ID: "c2", Scheme: "http", Host: "127.0.0.1", Port: 1}
+	store := &fakeHealthStore{conns: []*models.Connection{conn, noCreds}, health: make(map[string][4]string)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewHealthPoller(store, 10*time.Millisecond).Run(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if got, ok := store.get("c1"); ok {
+			if got != [4]string{"ok", "", "ok", ""} {
+				t.Errorf("health = %v, want ping ok, auth ok", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("health was not updated")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, ok := store.get("c2"); ok {
+		t.Error("connection without credentials should not be checked")
+	}
+}
+
+func TestHealthPoller_NoOverlappingChecks(t *testing.T) {
+	conn := &models.Connection{ID: "c1", Username: "admin", Password: "secret"}
+	store := &fakeHealthStore{conns: []*models.Connection{conn}, health: make(map[string][4]string)}
+	h := NewHealthPoller(store, time.Minute)
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	h.check = func(*models.Connection) (string, string, string, string) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return "ok", "", "ok", ""
+	}
+
+	now := time.Now()
+	h.poll(now)
+	h.poll(now.Add(time.Second))
+	h.poll(now.Add(2 * time.Second))
+	close(release)
+
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Errorf("check ran %d times while in flight, want 1", calls)
+	}
+}
+
+func TestHealthPoller_BacksOffOnFailure(t *testing.T) {
+	conn := &models.Connection{ID: "c1", Username: "admin", Password: "secret"}
+	store := &fakeHealthStore{conns: []*models.Connection{conn}, health: make(map[string][4]string)}
+	h := NewHealthPoller(store, time.Minute)
+	h.check = func(*models.Connection) (string, string, string, string) {
+		return "error", "connection refused", "unknown", ""
+	}
+
+	for i := 0; i < 3; i++ {
+		h.checkOne(conn)
+	}
+	h.mu.Lock()
+	wait := time.Until(h.next["c1"])
+	h.mu.Unlock()
+	// Third consecutive failure waits 4 intervals.
+	if wait < 3*time.Minute || wait > 4*time.Minute {
+		t.Errorf("next check in %v, want about 4m", wait)
+	}
+
+	// Not due yet, so polling must not start a check.
+	h.poll(time.Now())
+	h.mu.Lock()
+	running := h.running["c1"]
+	h.mu.Unlock()
+	if running {
+		t.Error("check started before backoff expired")
+	}
+}
+
+func TestHealthPoller_ForgetsRemovedConnections(t *testing.T) {
+	conn := &models.Connection{ID: "c1", Username: "admin", Password: "secret"}
+	store := &fakeHealthStore{conns: []*models.Connection{conn}, health: make(map[string][4]string)}
+	h := NewHealthPoller(store, time.Minute)
+	h.check = func(*models.Connection) (string, string, string, string) {
+		return "error", "connection refused", "unknown", ""
+	}
+	h.checkOne(conn)
+
+	store.conns = nil
+	h.poll(time.Now())
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.failures) != 0 || len(h.next) != 0 {
+		t.Errorf("failures = %v, next = %v; want both empty after the connection was removed", h.failures, h.next)
+	}
+}
+
+func TestBackoffFactor(t *testing.T) {
+	for n, want := range map[int]int{1: 1, 2: 2, 3: 4, 5: 16, 10: 16} {
+		if got := backoffFactor(n); got != want {
+			t.Errorf("backoffFactor(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
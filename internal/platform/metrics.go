@@ -0,0 +1,72 @@
+package platform
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics counts the HTTP requests clients send to the platforms, by method
+// and status, and records their latency, up to the response headers, by
+// method. It serves them in the Prometheus exposition format.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	handler  http.Handler
+}
+
+// NewMetrics returns an empty set of request metrics with a registry of
+// its own.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "workbench_platform_requests_total",
+			Help: "HTTP requests sent to AWX and AAP, by method and status.",
+		}, []string{"method", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "workbench_platform_request_duration_seconds",
+			Help:    "Latency of HTTP requests sent to AWX and AAP, by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m.requests, m.latency)
+	m.handler = promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+	return m
+}
+
+// defaultMetrics is given to clients created by NewClient; nil leaves
+// requests uncounted.
+var defaultMetrics *Metrics
+
+// SetMetrics makes clients created afterwards record their requests in m;
+// nil turns recording off again. It is meant to be called once at startup.
+func SetMetrics(m *Metrics) {
+	defaultMetrics = m
+}
+
+// WithMetrics makes c record its requests in m, as SetMetrics does for new
+// clients. It returns c for chaining.
+func (c *Client) WithMetrics(m *Metrics) *Client {
+	c.metrics = m
+	return c
+}
+
+// observe records one request. A status of 0 means the request failed
+// without a response, which is counted with status "error".
+func (m *Metrics) observe(method string, status int, d time.Duration) {
+	label := "error"
+	if status != 0 {
+		label = strconv.Itoa(status)
+	}
+	m.requests.WithLabelValues(method, label).Inc()
+	m.latency.WithLabelValues(method).Observe(d.Seconds())
+}
+
+// ServeHTTP serves the metrics for a Prometheus scrape.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,82 @@
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+func TestMetrics_CountsRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":1}`))
+		case r.URL.Path == "/api/v2/missing/":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+
+	m := NewMetrics()
+	c := newTestClient(ts).WithMetrics(m)
+	c.Get("/api/v2/ping/", nil)
+	c.Get("/api/v2/ping/", nil)
+	c.Get("/api/v2/missing/", nil)
+	c.Post("/api/v2/organizations/", map[string]interface{}{"name": "Eng"})
+	ts.Close()
+	c.Get("/api/v2/ping/", nil)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(rec.Body)
+	if err != nil {
+		t.Fatalf("scrape is not valid exposition format: %v", err)
+	}
+
+	requests := families["workbench_platform_requests_total"]
+	if requests == nil || requests.GetType() != dto.MetricType_COUNTER {
+		t.Fatalf("workbench_platform_requests_total = %v, want a counter", requests)
+	}
+	counts := make(map[[2]string]float64)
+	for _, metric := range requests.GetMetric() {
+		labels := labelMap(metric)
+		counts[[2]string{labels["method"], labels["status"]}] = metric.GetCounter().GetValue()
+	}
+	for key, want := range map[[2]string]float64{
+		{"GET", "200"}:   2,
+		{"GET", "404"}:   1,
+		{"GET", "error"}: 1,
+		{"POST", "201"}:  1,
+	} {
+		if counts[key] != want {
+			t.Errorf("requests%v = %v, want %v", key, counts[key], want)
+		}
+	}
+
+	latency := families["workbench_platform_request_duration_seconds"]
+	if latency == nil || latency.GetType() != dto.MetricType_HISTOGRAM {
+		t.Fatalf("workbench_platform_request_duration_seconds = %v, want a histogram", latency)
+	}
+	observed := make(map[string]uint64)
+	for _, metric := range latency.GetMetric() {
+		observed[labelMap(metric)["method"]] = metric.GetHistogram().GetSampleCount()
+	}
+	if observed["GET"] != 4 || observed["POST"] != 1 {
+		t.Errorf("latency sample counts = %v, want GET 4 and POST 1", observed)
+	}
+}
+
+func labelMap(m *dto.Metric) map[string]string {
+	labels := make(map[string]string)
+	for _, l := range m.GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	return labels
+}
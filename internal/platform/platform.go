@@ -1,6 +1,12 @@
 package platform
 
-import "github.com/rflorenc/ansible-automation-workbench/internal/models"
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
 
 // Platform defines operations available on an automation platform (AWX or AAP).
 type Platform interface {
@@ -13,17 +19,55 @@ type Platform interface {
 	// ListResources returns all objects of a given resource type.
 	ListResources(resourceType string) ([]models.Resource, error)
 
+	// ListResourcesFiltered returns objects of a given resource type that
+	// match the filter, passed to the API as query parameters.
+	ListResourcesFiltered(resourceType string, filter ListFilter) ([]models.Resource, error)
+
+	// GetResource returns one object of a given resource type by ID. The
+	// error wraps ErrNotFound when the object does not exist.
+	GetResource(resourceType string, id int) (models.Resource, error)
+
+	// DeleteResource deletes one object of a given resource type by ID. The
+	// error wraps ErrProtected for objects Cleanup keeps, and ErrNotFound
+	// when the object does not exist.
+	DeleteResource(resourceType string, id int) error
+
 	// GetResourceTypes returns all browsable resource types for this platform.
 	GetResourceTypes() []models.ResourceType
 
 	// Cleanup deletes non-default objects in correct dependency order.
-	Cleanup(logger func(string)) error
+	// With opts.DryRun set nothing is deleted; the objects are only logged.
+	// With opts.Archive supported types are deactivated instead of deleted.
+	// Stops early if ctx is cancelled.
+	Cleanup(ctx context.Context, opts CleanupOptions, logger func(string)) error
 
-	// Populate creates sample objects (AWX only).
-	Populate(logger func(string)) error
+	// Populate creates sample objects. Stops early if ctx is cancelled.
+	Populate(ctx context.Context, logger func(string)) error
 
-	// Export downloads assets in breadth-first dependency order (AAP only).
-	Export(outputDir string, logger func(string)) error
+	// Export downloads assets in breadth-first dependency order. Stops early if ctx is cancelled.
+	Export(ctx context.Context, outputDir string, opts ExportOptions, logger func(string)) error
+}
+
+// ListFilter narrows a resource listing. Zero values are not sent.
+type ListFilter struct {
+	Search   string // full-text ?search= across the type's searchable fields
+	Name     string // exact ?name= match
+	PageSize int    // maximum number of results to return
+}
+
+// params returns the filter as AWX/AAP query parameters.
+func (f ListFilter) params() url.Values {
+	params := url.Values{}
+	if f.Search != "" {
+		params.Set("search", f.Search)
+	}
+	if f.Name != "" {
+		params.Set("name", f.Name)
+	}
+	if f.PageSize > 0 {
+		params.Set("page_size", strconv.Itoa(f.PageSize))
+	}
+	return params
 }
 
 // CleanupExclusions returns the default skip lists used during cleanup for each platform type.
@@ -51,7 +95,16 @@ func extractSkips(resources []models.ResourceType) map[string][]string {
 // If the connection has a detected APIPrefix that differs from the default,
 // resource paths are rewritten accordingly. No HTTP calls are made here.
 func NewPlatform(conn *models.Connection) Platform {
-	client := NewClient(conn)
+	return newPlatform(conn, NewClient(conn))
+}
+
+// NewCachedPlatform is NewPlatform with list pages revalidated against cache
+// (see ResponseCache). A nil cache behaves like NewPlatform.
+func NewCachedPlatform(conn *models.Connection, cache *ResponseCache) Platform {
+	return newPlatform(conn, NewClient(conn).WithCache(cache, conn.ID))
+}
+
+func newPlatform(conn *models.Connection, client *Client) Platform {
 	switch conn.Type {
 	case "awx":
 		p := NewAWXPlatform(client)
@@ -63,6 +116,7 @@ func NewPlatform(conn *models.Connection) Platform {
 	case "aap":
 		p := NewAAPPlatform(client)
 		p.version = conn.Version
+		p.gwVersion = conn.GatewayVersion
 		if conn.APIPrefix != "" {
 			p.apiPrefix = conn.APIPrefix
 			if conn.APIPrefix != defaultAAPPrefix {
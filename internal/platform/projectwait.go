@@ -0,0 +1,68 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Project sync polling starts at projectPollMin and backs off by half on
+// each poll up to projectPollMax, so many projects syncing at once do not
+// flood the controller.
+var (
+	projectPollMin = 2 * time.Second
+	projectPollMax = 15 * time.Second
+)
+
+// WaitForProject polls the project at path until its sync succeeds, fails,
+// ctx is cancelled or timeout passes.
+func (c *Client) WaitForProject(ctx context.Context, path string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	wait := projectPollMin
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var proj map[string]interface{}
+		if err := c.GetJSON(path, nil, &proj); err != nil {
+			return err
+		}
+		status, _ := proj["status"].(string)
+		switch status {
+		case "successful":
+			return nil
+		case "failed", "error", "canceled":
+			return fmt.Errorf("project sync status: %s", status)
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("timeout waiting for project sync")
+		}
+		sleep := jitter(wait)
+		if sleep > remaining {
+			sleep = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleep):
+		}
+		wait = nextProjectPoll(wait)
+	}
+}
+
+// nextProjectPoll returns the poll interval that follows wait.
+func nextProjectPoll(wait time.Duration) time.Duration {
+	wait += wait / 2
+	if wait > projectPollMax {
+		wait = projectPollMax
+	}
+	return wait
+}
+
+// jitter returns d shifted randomly by up to 10% either way.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration((rand.Float64()*0.2-0.1)*float64(d))
+}
@@ -0,0 +1,92 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withProjectPolling shortens project polling for a test.
+func withProjectPolling(t *testing.T, min, max time.Duration) {
+	oldMin, oldMax := projectPollMin, projectPollMax
+	projectPollMin, projectPollMax = min, max
+	t.Cleanup(func() { projectPollMin, projectPollMax = oldMin, oldMax })
+}
+
+func TestWaitForProject_SucceedsAfterPolls(t *testing.T) {
+	withProjectPolling(t, 2*time.Millisecond, 8*time.Millisecond)
+	var polls []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls = append(polls, time.Now())
+		if len(polls) < 5 {
+			w.Write([]byte(`{"id":7,"status":"running"}`))
+			return
+		}
+		w.Write([]byte(`{"id":7,"status":"successful"}`))
+	}))
+	defer ts.Close()
+
+	if err := newTestClient(ts).WaitForProject(context.Background(), "/api/v2/projects/7/", time.Second); err != nil {
+		t.Fatalf("WaitForProject returned error: %v", err)
+	}
+	if len(polls) != 5 {
+		t.Errorf("polled %d times, want 5", len(polls))
+	}
+}
+
+func TestWaitForProject_Failed(t *testing.T) {
+	withProjectPolling(t, time.Millisecond, time.Millisecond)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":7,"status":"failed"}`))
+	}))
+	defer ts.Close()
+
+	err := newTestClient(ts).WaitForProject(context.Background(), "/api/v2/projects/7/", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("err = %v, want a failed sync", err)
+	}
+}
+
+func TestWaitForProject_TimeoutAndCancel(t *testing.T) {
+	withProjectPolling(t, 5*time.Millisecond, 5*time.Millisecond)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":7,"status":"running"}`))
+	}))
+	defer ts.Close()
+	c := newTestClient(ts)
+
+	err := c.WaitForProject(context.Background(), "/api/v2/projects/7/", 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.WaitForProject(ctx, "/api/v2/projects/7/", time.Second); err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestNextProjectPoll(t *testing.T) {
+	wait := 2 * time.Second
+	var got []time.Duration
+	for i := 0; i < 7; i++ {
+		got = append(got, wait)
+		wait = nextProjectPoll(wait)
+	}
+	want := []time.Duration{2 * time.Second, 3 * time.Second, 4500 * time.Millisecond,
+		6750 * time.Millisecond, 10125 * time.Millisecond, 15 * time.Second, 15 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("intervals = %v, want %v", got, want)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if d := jitter(10 * time.Second); d < 9*time.Second || d > 11*time.Second {
+			t.Fatalf("jitter(10s) = %v, want within 10%%", d)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package platform
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// limiters holds the limiter of each connection ID. A migration, the
+// resource browser and health checks each create their own Client, so the
+// limit only holds if they share one.
+var limiters = struct {
+	sync.Mutex
+	byConn map[string]*rate.Limiter
+}{byConn: make(map[string]*rate.Limiter)}
+
+// limiterFor returns the limiter shared by clients of connection connID,
+// allowing rps requests per second, or nil (unlimited) when rps is not
+// positive. A changed rps is applied to the shared limiter. Connections
+// without an ID, which are not stored, get a limiter of their own.
+func limiterFor(connID string, rps float64) *rate.Limiter {
+	if rps <= 0 || connID == "" {
+		ForgetConnection(connID)
+		if rps <= 0 {
+			return nil
+		}
+		return rate.NewLimiter(rate.Limit(rps), 1)
+	}
+	limiters.Lock()
+	defer limiters.Unlock()
+	l := limiters.byConn[connID]
+	if l == nil {
+		l = rate.NewLimiter(rate.Limit(rps), 1)
+		limiters.byConn[connID] = l
+	} else if l.Limit() != rate.Limit(rps) {
+		l.SetLimit(rate.Limit(rps))
+	}
+	return l
+}
+
+// ForgetConnection drops the shared state kept for connection connID. It is
+// called when the connection is deleted.
+func ForgetConnection(connID string) {
+	limiters.Lock()
+	delete(limiters.byConn, connID)
+	limiters.Unlock()
+}
@@ -0,0 +1,51 @@
+package platform
+
+import (
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BlockStyle prepares a parsed JSON or YAML document for block-style output:
+// flow collections become blocks, and quotes are dropped from strings that
+// read back as the same string unquoted. AWX and AAP parse YAML with PyYAML,
+// which follows YAML 1.1, so strings such as "yes", "off" or "1:20" keep
+// their quotes even though YAML 1.2 would read them as strings.
+func BlockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" && !YAML11Scalar(n.Value) {
+		n.Style &^= yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	}
+	for _, c := range n.Content {
+		BlockStyle(c)
+	}
+}
+
+// yaml11Bool matches the booleans of YAML 1.1, including y/n and on/off.
+var yaml11Bool = regexp.MustCompile(`^(y|Y|yes|Yes|YES|n|N|no|No|NO|true|True|TRUE|false|False|FALSE|on|On|ON|off|Off|OFF)$`)
+
+// yaml11Other matches the YAML 1.1 nulls, ints (with underscores, binary,
+// octal, hex and base 60) and floats.
+var yaml11Other = regexp.MustCompile(`^(~|null|Null|NULL|` +
+	`[-+]?0b[0-1_]+|[-+]?0[0-7_]+|[-+]?(0|[1-9][0-9_]*)|[-+]?0x[0-9a-fA-F_]+|[-+]?[1-9][0-9_]*(:[0-5]?[0-9])+|` +
+	`[-+]?([0-9][0-9_]*)?\.[0-9_]*([eE][-+][0-9]+)?|[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+\.[0-9_]*|[-+]?\.(inf|Inf|INF)|\.(nan|NaN|NAN))$`)
+
+// YAML11Scalar reports whether a YAML 1.1 parser reads s, written unquoted,
+// as something other than a string: a bool, null or number.
+func YAML11Scalar(s string) bool {
+	return s == "" || yaml11Bool.MatchString(s) || yaml11Other.MatchString(s)
+}
+
+// YAML11Bool reports whether s is a YAML 1.1 boolean, and its value.
+func YAML11Bool(s string) (value, ok bool) {
+	if !yaml11Bool.MatchString(s) {
+		return false, false
+	}
+	switch s[0] {
+	case 'y', 'Y', 't', 'T':
+		return true, true
+	case 'o', 'O':
+		return s == "on" || s == "On" || s == "ON", true
+	}
+	return false, true
+}
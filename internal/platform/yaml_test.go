@@ -0,0 +1,26 @@
+package platform
+
+import "testing"
+
+func TestYAML11Scalar(t *testing.T) {
+	for s, want := range map[string]bool{
+		"yes": true, "Off": true, "y": true, "~": true, "": true, "null": true,
+		"12": true, "1_000": true, "0x1F": true, "017": true, "1:20": true, "1.5": true, ".inf": true,
+		"prod": false, "yesterday": false, "v1.2": false, "1.2.3": false, "10.0.0.1": false, "eu-west-1": false,
+	} {
+		if got := YAML11Scalar(s); got != want {
+			t.Errorf("YAML11Scalar(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestYAML11Bool(t *testing.T) {
+	for s, want := range map[string]bool{"yes": true, "On": true, "Y": true, "TRUE": true, "no": false, "off": false, "N": false} {
+		if got, ok := YAML11Bool(s); !ok || got != want {
+			t.Errorf("YAML11Bool(%q) = %v, %v; want %v", s, got, ok, want)
+		}
+	}
+	if _, ok := YAML11Bool("maybe"); ok {
+		t.Error(`YAML11Bool("maybe") reported a bool`)
+	}
+}
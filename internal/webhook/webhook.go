@@ -0,0 +1,54 @@
+// Package webhook posts job outcomes to an external URL.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+// DefaultTimeout bounds each delivery.
+const DefaultTimeout = 10 * time.Second
+
+// Notifier POSTs finished jobs to a URL as JSON.
+type Notifier struct {
+	url    string
+	client *http.Client
+}
+
+// New returns a Notifier that posts to url.
+func New(url string) *Notifier {
+	return &Notifier{url: url, client: &http.Client{Timeout: DefaultTimeout}}
+}
+
+// Notify delivers r in the background so a slow receiver never holds up the
+// job. It matches the signature of models.JobStore.OnFinish.
+func (n *Notifier) Notify(r models.JobResult) {
+	go func() {
+		if err := n.Send(r); err != nil {
+			log.Printf("webhook: job %s: %v", r.ID, err)
+		}
+	}()
+}
+
+// Send posts r and waits for the response. Any non-2xx status is an error.
+func (n *Notifier) Send(r models.JobResult) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", n.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rflorenc/ansible-automation-workbench/internal/models"
+)
+
+func TestNotify_CompletedAndFailed(t *testing.T) {
+	received := make(chan models.JobResult, 2)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var res models.JobResult
+		if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		received <- res
+	}))
+	defer ts.Close()
+
+	jobs := models.NewJobStore()
+	jobs.OnFinish(New(ts.URL).Notify)
+
+	ok := jobs.Create("migration-run", "conn-1")
+	ok.Complete()
+	bad := jobs.Create("export", "conn-2")
+	bad.Fail("boom")
+
+	got := make(map[string]models.JobResult)
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-received:
+			got[r.ID] = r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for webhook")
+		}
+	}
+
+	for _, want := range []models.JobResult{
+		{ID: ok.ID, Type: "migration-run", ConnectionID: "conn-1", Status: "completed"},
+		{ID: bad.ID, Type: "export", ConnectionID: "conn-2", Status: "failed", Error: "boom"},
+	} {
+		r, found := got[want.ID]
+		if !found {
+			t.Errorf("no payload for job %s", want.ID)
+			continue
+		}
+		if r.Type != want.Type || r.ConnectionID != want.ConnectionID || r.Status != want.Status || r.Error != want.Error {
+			t.Errorf("payload = %+v, want %+v", r, want)
+		}
+		if r.FinishedAt.Before(r.StartedAt) || r.Duration < 0 {
+			t.Errorf("job %s: bad timing %v → %v (%gs)", r.ID, r.StartedAt, r.FinishedAt, r.Duration)
+		}
+	}
+}
+
+func TestSend_ErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	if err := New(ts.URL).Send(models.JobResult{ID: "x"}); err == nil {
+		t.Fatal("Send succeeded on a 500 response")
+	}
+}
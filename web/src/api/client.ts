@@ -1,5 +1,13 @@
 const BASE = '';
 
+export interface Settings {
+  export_concurrency: number;
+  import_concurrency: number;
+  max_retries: number;
+  health_interval: string;
+  secret_keys: string[];
+}
+
 async function request<T>(method: string, path: string, body?: unknown): Promise<T> {
   const opts: RequestInit = {
     method,
@@ -19,39 +27,163 @@ export const api = {
   // Connections
   createConnection: (conn: unknown) => request<unknown>('POST', '/api/connections', conn),
   listConnections: () => request<unknown[]>('GET', '/api/connections'),
+  getConnection: (id: string) => request<unknown>('GET', `/api/connections/${id}`),
   updateConnection: (id: string, conn: unknown) => request<unknown>('PUT', `/api/connections/${id}`, conn),
   deleteConnection: (id: string) => request<void>('DELETE', `/api/connections/${id}`),
+  deleteConnections: (ids: string[]) =>
+    request<{ results: { id: string; status: string; error?: string }[] }>('DELETE', '/api/connections', { ids }),
   testConnection: (id: string) => request<{ ok: boolean; error?: string }>('POST', `/api/connections/${id}/test`),
+  testAllConnections: () => request<{ results: unknown[] }>('POST', '/api/connections/test-all'),
+  exportConnections: async (masked = false) => {
+    const resp = await fetch(`${BASE}/api/connections/export${masked ? '?masked=true' : ''}`);
+    if (!resp.ok) throw new Error(`HTTP ${resp.status}`);
+    return resp.text();
+  },
+  importConnections: async (yaml: string) => {
+    const resp = await fetch(`${BASE}/api/connections/import`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/yaml' },
+      body: yaml,
+    });
+    const data = await resp.json();
+    if (!resp.ok) throw new Error(data.error || `HTTP ${resp.status}`);
+    return data as { results: { name: string; id?: string; status: string; error?: string }[] };
+  },
+  refreshConnectionVersion: (id: string) =>
+    request<{ id: string; version: string; gateway_version: string; api_prefix: string }>('POST', `/api/connections/${id}/version`),
 
   // Resources
-  listResourceTypes: (connId: string) => request<unknown[]>('GET', `/api/connections/${connId}/resources`),
-  listResources: (connId: string, type: string) => request<unknown[]>('GET', `/api/connections/${connId}/resources/${type}`),
+  listResourceTypes: (connId: string, withCounts?: boolean) =>
+    request<unknown[]>('GET', `/api/connections/${connId}/resources${withCounts ? '?with_counts=true' : ''}`),
+  listResources: (connId: string, type: string, filter?: { search?: string; name?: string; page_size?: number; refresh?: boolean }) => {
+    const params = new URLSearchParams();
+    if (filter?.search) params.set('search', filter.search);
+    if (filter?.name) params.set('name', filter.name);
+    if (filter?.page_size) params.set('page_size', String(filter.page_size));
+    if (filter?.refresh) params.set('refresh', 'true');
+    const qs = params.toString();
+    return request<unknown[]>('GET', `/api/connections/${connId}/resources/${type}${qs ? `?${qs}` : ''}`);
+  },
+  getResource: (connId: string, type: string, objId: number) =>
+    request<unknown>('GET', `/api/connections/${connId}/resources/${type}/${objId}`),
+  deleteResource: (connId: string, type: string, objId: number) =>
+    request<void>('DELETE', `/api/connections/${connId}/resources/${type}/${objId}`),
 
   // Operations
-  runCleanup: (connId: string) => request<{ job_id: string }>('POST', `/api/connections/${connId}/cleanup`),
+  runCleanup: (connId: string, dryRun?: boolean, archive?: boolean) => {
+    const params = new URLSearchParams();
+    if (dryRun) params.set('dry_run', 'true');
+    if (archive) params.set('archive', 'true');
+    const qs = params.toString();
+    return request<{ job_id: string }>('POST', `/api/connections/${connId}/cleanup${qs ? `?${qs}` : ''}`);
+  },
   runPopulate: (connId: string) => request<{ job_id: string }>('POST', `/api/connections/${connId}/populate`),
-  runExport: (connId: string) => request<{ job_id: string; output_dir: string }>('POST', `/api/connections/${connId}/export`),
+  runExport: (connId: string, metadata?: boolean, format?: 'migration', output?: 'json' | 'yaml') =>
+    request<{ job_id: string; output_dir: string }>('POST', `/api/connections/${connId}/export${output ? `?format=${output}` : ''}`,
+      { metadata: metadata || false, format }),
 
   // Migration
-  migrationPreview: (sourceId: string, destinationId: string) =>
-    request<{ job_id: string }>('POST', '/api/migrate/preview', { source_id: sourceId, destination_id: destinationId }),
+  migrationValidate: (sourceId: string, destinationId: string) =>
+    request<{ name: string; status: 'pass' | 'warn' | 'fail'; message: string }[]>('POST', '/api/migrate/validate', {
+      source_id: sourceId,
+      destination_id: destinationId,
+    }),
+  migrationPreview: (
+    sourceId: string,
+    destinationId: string,
+    excludeDisabled?: boolean,
+    conflicts?: Record<string, 'skip' | 'update'>,
+    types?: string[],
+    exclude?: Record<string, string[]>,
+    includeGroups?: Record<string, string[]>,
+  ) =>
+    request<{ job_id: string }>('POST', '/api/migrate/preview', {
+      source_id: sourceId,
+      destination_id: destinationId,
+      exclude_disabled: excludeDisabled || false,
+      conflicts: conflicts || {},
+      types: types || [],
+      exclude: exclude || {},
+      include_groups: includeGroups || {},
+    }),
   getMigrationPreview: (jobId: string) =>
     request<unknown>('GET', `/api/migrate/preview/${jobId}`),
-  migrationRun: (sourceId: string, destinationId: string, previewJobId: string, exclude?: Record<string, string[]>) =>
+  previewExportUrl: (jobId: string) => `${BASE}/api/migrate/preview/${jobId}/export`,
+  exportArchive: (sourceId: string, name?: string, excludeDisabled?: boolean) =>
+    request<{ job_id: string; name: string }>('POST', '/api/migrate/export-archive', {
+      source_id: sourceId,
+      name: name || '',
+      exclude_disabled: excludeDisabled || false,
+    }),
+  importArchive: (destinationId: string, name: string, exclude?: Record<string, string[]>) =>
+    request<{ job_id: string }>('POST', '/api/migrate/import-archive', {
+      destination_id: destinationId,
+      name,
+      exclude: exclude || {},
+    }),
+  migrationRun: (sourceId: string, destinationId: string, previewJobId: string, exclude?: Record<string, string[]>,
+    includeGroups?: Record<string, string[]>) =>
     request<{ job_id: string }>('POST', '/api/migrate/run', {
       source_id: sourceId,
       destination_id: destinationId,
       preview_job_id: previewJobId,
       exclude: exclude || {},
+      include_groups: includeGroups || {},
     }),
+  migrationDryRun: (destinationId: string, previewJobId: string, exclude?: Record<string, string[]>,
+    includeGroups?: Record<string, string[]>) =>
+    request<{ job_id: string }>('POST', '/api/migrate/dry-run', {
+      destination_id: destinationId,
+      preview_job_id: previewJobId,
+      exclude: exclude || {},
+      include_groups: includeGroups || {},
+    }),
+  migrate: (sourceId: string, destinationId: string, exclude?: Record<string, string[]>,
+    includeGroups?: Record<string, string[]>) =>
+    request<{ job_id: string }>('POST', '/api/migrate', {
+      source_id: sourceId,
+      destination_id: destinationId,
+      exclude: exclude || {},
+      include_groups: includeGroups || {},
+    }),
+  migrationResume: (runJobId: string) =>
+    request<{ job_id: string }>('POST', '/api/migrate/resume', { run_job_id: runJobId }),
+
+  // Diff
+  diffResource: (sourceId: string, destinationId: string, type: string, name: string) =>
+    request<unknown>('GET', `/api/diff/resource?${new URLSearchParams({
+      source_id: sourceId,
+      destination_id: destinationId,
+      type,
+      name,
+    })}`),
+  diffConnections: (sourceId: string, destinationId: string, types?: string[]) =>
+    request<{ job_id: string }>('POST', `/api/connections/${sourceId}/diff?${new URLSearchParams({
+      destination_id: destinationId,
+      ...(types && types.length > 0 ? { types: types.join(',') } : {}),
+    })}`),
+  getConnectionDiff: (jobId: string) =>
+    request<unknown>('GET', `/api/diff/connections/${jobId}`),
 
   // Exclusions
   getExclusions: () => request<unknown>('GET', '/api/exclusions'),
 
   // Jobs
-  listJobs: () => request<unknown[]>('GET', '/api/jobs'),
-  getJob: (id: string) => request<unknown>('GET', `/api/jobs/${id}`),
+  listJobs: (limit = 0, offset = 0) =>
+    request<{ jobs: unknown[]; total: number; limit: number; offset: number }>('GET', `/api/jobs?limit=${limit}&offset=${offset}`),
+  getJob: (id: string, structured = false) =>
+    request<unknown>('GET', `/api/jobs/${id}${structured ? '?log=structured' : ''}`),
+  getJobLogs: (id: string, offset = 0) =>
+    request<{ status: string; offset: number; lines: string[] }>('GET', `/api/jobs/${id}/logs?offset=${offset}`),
   cancelJob: (jobId: string) => request<{ status: string }>('POST', `/api/jobs/${jobId}/cancel`),
+  deleteJob: (jobId: string) => request<void>('DELETE', `/api/jobs/${jobId}`),
+  pruneJobs: (olderThan?: string) =>
+    request<{ removed: string[] }>('POST', '/api/jobs/prune', olderThan ? { older_than: olderThan } : {}),
+
+  // Settings
+  getSettings: () => request<Settings>('GET', '/api/settings'),
+  updateSettings: (settings: Partial<Settings>, persist = false) =>
+    request<Settings>('PUT', `/api/settings${persist ? '?persist=true' : ''}`, settings),
 };
 
 export function createJobLogSocket(jobId: string, onMessage: (line: string) => void, onClose?: (status: string) => void): WebSocket {
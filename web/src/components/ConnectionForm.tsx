@@ -26,7 +26,7 @@ interface Props {
 export function ConnectionForm({ isOpen, initial, onSave, onClose }: Props) {
   const [name, setName] = useState(initial?.name || '');
   const [type, setType] = useState<'awx' | 'aap'>(initial?.type || 'awx');
-  const [role, setRole] = useState<'source' | 'destination'>(initial?.role || 'source');
+  const [role, setRole] = useState<Connection['role']>(initial?.role || 'source');
   const [scheme, setScheme] = useState<'http' | 'https'>(initial?.scheme || 'http');
   const [host, setHost] = useState(initial?.host || '');
   const [port, setPort] = useState(initial?.port || 80);
@@ -73,16 +73,15 @@ export function ConnectionForm({ isOpen, initial, onSave, onClose }: Props) {
           </FormSelect>
         </FormGroup>
         <FormGroup label="Role" fieldId="role">
-          <FormSelect id="role" value={role} onChange={(_e, v) => setRole(v as 'source' | 'destination')}
-            isDisabled={type === 'awx'}
-          >
+          <FormSelect id="role" value={role} onChange={(_e, v) => setRole(v as Connection['role'])}>
             <FormSelectOption value="source" label="Source (migrate FROM)" />
-            <FormSelectOption value="destination" label="Destination (migrate TO)" />
+            {type === 'aap' && <FormSelectOption value="destination" label="Destination (migrate TO)" />}
+            <FormSelectOption value="auditor" label="Auditor (read-only)" />
           </FormSelect>
           <FormHelperText>
             <HelperText>
               <HelperTextItem>
-                {type === 'awx' ? 'AWX instances are always sources' : 'AAP can be source (older) or destination (2.5+)'}
+                {type === 'awx' ? 'AWX instances are sources, or auditors for inspection only' : 'AAP can be source (older), destination (2.5+) or auditor (read-only)'}
               </HelperTextItem>
             </HelperText>
           </FormHelperText>
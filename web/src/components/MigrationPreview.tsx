@@ -14,10 +14,13 @@ const resourceTypeLabels: Record<string, string> = {
   users: 'Users',
   credential_types: 'Credential Types',
   credentials: 'Credentials',
+  execution_environments: 'Execution Environments',
+  applications: 'Applications',
   projects: 'Projects',
   inventories: 'Inventories',
   hosts: 'Hosts',
   groups: 'Groups',
+  notification_templates: 'Notification Templates',
   job_templates: 'Job Templates',
   workflow_job_templates: 'Workflow Job Templates',
   schedules: 'Schedules',
@@ -25,7 +28,7 @@ const resourceTypeLabels: Record<string, string> = {
 
 const displayOrder = [
   'organizations', 'teams', 'users', 'credential_types', 'credentials',
-  'projects', 'inventories', 'hosts', 'groups',
+  'execution_environments', 'applications', 'projects', 'inventories', 'hosts', 'groups', 'notification_templates',
   'job_templates', 'workflow_job_templates', 'schedules',
 ];
 
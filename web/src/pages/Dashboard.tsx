@@ -105,6 +105,7 @@ export function Dashboard() {
               <SplitItem>
                 <Label color={conn.type === 'awx' ? 'blue' : 'purple'}>
                   {conn.type.toUpperCase()}{conn.version ? ` v${conn.version}` : ''}
+                  {conn.gateway_version ? ` (gateway v${conn.gateway_version})` : ''}
                 </Label>
               </SplitItem>
               <SplitItem>{pingLabel(conn)}</SplitItem>
@@ -126,7 +127,7 @@ export function Dashboard() {
             </DescriptionListGroup>
             <DescriptionListGroup>
               <DescriptionListTerm>TLS Verify</DescriptionListTerm>
-              <DescriptionListDescription>{conn.insecure ? 'Off' : conn.ca_cert ? 'Custom CA' : 'On'}</DescriptionListDescription>
+              <DescriptionListDescription>{conn.insecure ? 'Off' : conn.ca_cert || conn.ca_cert_file ? 'Custom CA' : 'On'}</DescriptionListDescription>
             </DescriptionListGroup>
           </DescriptionList>
           {conn.ping_status === 'error' && conn.ping_error && (
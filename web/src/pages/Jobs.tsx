@@ -15,9 +15,23 @@ export function Jobs() {
 
   const loadJobs = useCallback(async () => {
     const data = await api.listJobs();
-    setJobs(data as Job[]);
+    setJobs(data.jobs as Job[]);
   }, []);
 
+  const cancelJob = async (id: string) => {
+    await api.cancelJob(id);
+    loadJobs();
+  };
+
+  const resumeJob = async (id: string) => {
+    await api.migrationResume(id);
+    loadJobs();
+  };
+
+  const canResume = (job: Job) =>
+    (job.type === 'migration-run' || job.type === 'migration-resume' || job.type === 'migration-one-shot') &&
+    (job.status === 'failed' || job.status === 'cancelled');
+
   useEffect(() => {
     loadJobs();
     const interval = setInterval(loadJobs, 3000);
@@ -73,6 +87,16 @@ export function Jobs() {
                 <Button variant="link" onClick={() => setSelectedJob(job.id)}>
                   View Logs
                 </Button>
+                {job.status === 'running' && (
+                  <Button variant="link" isDanger onClick={() => cancelJob(job.id)}>
+                    Cancel
+                  </Button>
+                )}
+                {canResume(job) && (
+                  <Button variant="link" onClick={() => resumeJob(job.id)}>
+                    Resume
+                  </Button>
+                )}
               </Td>
             </Tr>
           ))}
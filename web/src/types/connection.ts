@@ -2,15 +2,24 @@ export interface Connection {
   id: string;
   name: string;
   type: 'awx' | 'aap';
-  role: 'source' | 'destination';
+  role: 'source' | 'destination' | 'auditor';
   scheme: 'http' | 'https';
   host: string;
   port: number;
   username: string;
   password: string;
+  token?: string;
   insecure: boolean;
   ca_cert?: string;
+  ca_cert_file?: string; // server-side path to a PEM CA bundle
+  client_cert?: string; // PEM client certificate for mutual TLS, or a server-side path
+  client_key?: string; // PEM key or server-side path; inline keys come back masked
+  timeout?: string; // duration, e.g. "45s"
+  max_retries?: number;
+  rate_limit?: number; // requests per second; 0 is unlimited
+  page_concurrency?: number; // list pages fetched in parallel; 0 or 1 is serial
   version?: string;
+  gateway_version?: string; // AAP 2.5+ gateway
   api_prefix?: string;
   ping_status?: 'unknown' | 'ok' | 'error';
   ping_error?: string;
@@ -2,6 +2,8 @@ export interface ResourceType {
   name: string;
   label: string;
   api_path: string;
+  count?: number; // set when listed with counts
+  error?: string; // why the count could not be read
 }
 
 export interface Job {
@@ -13,13 +15,24 @@ export interface Job {
   finished_at?: string;
   error?: string;
   output: string[];
+  total: number;
+  completed: number;
+  log_entries?: JobLogEntry[]; // only with getJob(id, true)
+}
+
+export interface JobLogEntry {
+  line: number;
+  level: 'info' | 'warn' | 'error';
+  phase?: string;
+  resource?: string;
+  message: string;
 }
 
 export interface MigrationResource {
   source_id: number;
   name: string;
   type: string;
-  action: string; // "create", "skip_exists"
+  action: string; // "create", "update", "skip_exists", "skip_excluded"
   dest_id?: number;
 }
 
@@ -30,6 +43,8 @@ export interface MigrationPreviewData {
   warnings: string[];
   host_counts?: Record<string, number>;
   group_counts?: Record<string, number>;
+  excluded?: Record<string, number>;
+  totals: Record<string, number>;
 }
 
 export interface DefaultExclusions {